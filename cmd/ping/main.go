@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
+	"github.com/ivankoTut/ping-url/internal/admin"
 	"github.com/ivankoTut/ping-url/internal/config"
 	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/notification"
 	"github.com/ivankoTut/ping-url/internal/ping"
 	"github.com/ivankoTut/ping-url/internal/secure"
 	"github.com/ivankoTut/ping-url/internal/server"
+	"github.com/ivankoTut/ping-url/internal/statistic"
 	"github.com/ivankoTut/ping-url/internal/storage/clickhouse"
 	"github.com/ivankoTut/ping-url/internal/storage/postgres"
 	postgresRepository "github.com/ivankoTut/ping-url/internal/storage/postgres/repository"
@@ -13,6 +17,11 @@ import (
 	redisRepository "github.com/ivankoTut/ping-url/internal/storage/redis/repository"
 	"github.com/ivankoTut/ping-url/internal/telegram"
 	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
 )
 
 func main() {
@@ -32,40 +41,130 @@ func main() {
 	k := kernel.MustCreateKernel(cfg, db, r)
 	k.Log().Debug("kernel is initialize")
 
+	// инициируем очередь исходящих сообщений, все сообщения бота уходят через нее
+	queue := notification.NewQueue(redisRepository.NewOutboxRepository(r), k)
+
+	// администраторы из конфига сохраняются в бд, режим доступа и блокировки загружаются до начала приема сообщений
+	userRepo := postgresRepository.NewUser(db)
+	accessRepo := postgresRepository.NewAccess(db)
+	workspaceRepo := postgresRepository.NewWorkspace(db)
+	access := secure.NewUserProvider(k, userRepo, accessRepo, workspaceRepo)
+	if err := access.Load(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	go access.Run()
+
 	// инициируем бота и начинаем слушать сообщения и команды в нем
-	bot := telegram.MustCreateBot(k, secure.NewUserProvider(cfg))
+	bot := telegram.MustCreateBot(k, access, queue)
 	go bot.StartListen()
+	go queue.Run(bot)
 
 	// инициируем репозитории
-	dc := redisRepository.NewCommandRepository(r)
+	dc := redisRepository.NewCommandRepository(r, cfg.Telegram.DialogTimeout)
 	pingRepository := postgresRepository.NewPing(db)
-	userRepo := postgresRepository.NewUser(db)
+	digestRepo := redisRepository.NewDigestRepository(r)
+	templateRepo := postgresRepository.NewTemplate(db)
+	incidentRepo := postgresRepository.NewIncident(db)
+
+	// статистика по опросам вместе с инцидентами
+	statsRepo := statistic.NewStatistic(statisticRepo, incidentRepo, pingRepository)
+
+	// инициируем "пингер"
+	notifier := ping.NewNotifier(k, bot, pingRepository, workspaceRepo, userRepo, digestRepo, templateRepo)
+	stateRepo := redisRepository.NewStateRepository(r)
+	runer := ping.NewPing(pingRepository, k, statisticRepo, notifier, stateRepo, incidentRepo)
+	editor := ping.NewEditor(k, pingRepository, statisticRepo, runer)
+	importer := ping.NewImporter(k, pingRepository, runer)
+	overview := ping.NewOverview(pingRepository, stateRepo)
+
+	// инструменты администратора
+	broadcast := admin.NewBroadcast(k.Log(), userRepo, bot)
+	commandMetrics := command.NewCommandMetrics()
+	runtime := admin.NewRuntime(runer, queue, commandMetrics)
 
 	// запускаем апи сервер
-	go server.RunApiServer(userRepo, k, statisticRepo, pingRepository)
+	go server.RunApiServer(userRepo, k, statsRepo, pingRepository, incidentRepo, statisticRepo, overview, runer, editor, importer, bot, access, broadcast, runtime)
 
-	// подключаем команды, которые хотим обрабатывать и слушаем их
-	handlerBot := command.NewCommand(k, bot, []command.HandlerCommand{
+	// подключаем команды, которые хотим обрабатывать, /help строит справку по этому же списку, /cancel отменяет их диалоги
+	commands := []command.HandlerCommand{
 		command.NewAddUrlCommand(dc, pingRepository),
-		command.NewRemoveUrlCommand(dc, pingRepository),
-		command.NewRegistrationCommand(userRepo),
+		command.NewRemoveUrlCommand(dc, pingRepository, runer),
+		command.NewRegistrationCommand(dc, userRepo, workspaceRepo, access),
 		command.NewListUrlCommand(pingRepository),
 		command.NewMuteCommand(userRepo),
 		command.NewUnmuteAllCommand(userRepo),
-		command.NewStatisticAllCommand(statisticRepo),
-		command.NewStatisticCommand(statisticRepo, pingRepository),
-		command.NewStatisticUrlCommand(statisticRepo, dc, pingRepository),
+		command.NewStatisticAllCommand(statsRepo),
+		command.NewStatisticCommand(statisticRepo, pingRepository, statsRepo),
+		command.NewStatisticUrlCommand(statsRepo, dc, pingRepository),
 		command.NewApiKeyRefreshCommand(userRepo, cfg.FullApiPath()),
+		command.NewMuteUrlCommand(dc, pingRepository),
+		command.NewUnmuteUrlCommand(dc, pingRepository),
+		command.NewMuteListCommand(pingRepository, userRepo),
+		command.NewSettingsCommand(dc, userRepo),
+		command.NewCriticalUrlCommand(dc, pingRepository),
+		command.NewTemplateCommand(dc, templateRepo, cfg.FullApiPath()),
+		command.NewTemplatePreviewCommand(templateRepo, cfg.FullApiPath()),
+		command.NewIncidentsCommand(incidentRepo),
+		command.NewWorkspaceCommand(dc, workspaceRepo, bot),
+		command.NewConnectChatCommand(workspaceRepo, bot),
+		command.NewEditUrlCommand(dc, pingRepository, editor),
+		command.NewImportCommand(dc, importer, bot),
+		command.NewExportCommand(importer, bot),
+		command.NewChartCommand(statisticRepo, pingRepository, userRepo, bot),
+		command.NewStatusCommand(overview),
+		command.NewCheckNowCommand(pingRepository, runer),
+		command.NewAdminCommand(userRepo, access, accessRepo, broadcast, runtime, bot),
+	}
+	help := command.NewHelpCommand(append(commands, command.NewCancelCommand(dc, commands)))
+
+	// слушаем подключенные команды
+	handlerBot := command.NewCommand(k, bot, workspaceRepo, userRepo, access, help.Commands(), []command.HandlerCallback{
+		command.NewAlertCallback(pingRepository, pingRepository, runer, statsRepo, incidentRepo),
 	})
+	// общие для всех команд и их кнопок ограничение частоты, аудит, метрики и перехват паники, паника перехватывается ближе всего к команде,
+	// чтобы остальные middleware увидели ее как обычную ошибку
+	handlerBot.Use(
+		command.RateLimit(cfg.Telegram.RateLimit),
+		command.Audit(k.Log()),
+		commandMetrics.Middleware(),
+		command.Recover(),
+	)
 	go handlerBot.ListenCommandAndMessage()
 
-	// инициируем и запускаем "пингер"
-	runer := ping.NewPing(pingRepository, k, statisticRepo, bot)
+	// меню команд в телеграме всегда соответствует подключенным командам
+	if err := handlerBot.RegisterMenu(); err != nil {
+		k.Log().Error(err.Error())
+	}
+
+	// запускаем "пингер"
 	go runer.Run()
 
+	// снимаем отключения уведомлений с истекшим сроком
+	go ping.NewMuteWatcher(pingRepository, userRepo, k, bot).Run()
+
+	// отменяем диалоги команд оставшиеся без ответа
+	go command.NewDialogWatcher(dc, userRepo, k, bot).Run()
+
+	// отправляем сводку уведомлений отложенных на время тихих часов
+	go ping.NewDigestWatcher(userRepo, digestRepo, k, bot).Run()
+
 	// слушаем события от бота по командам
 	go runer.ListenCommandEvents(handlerBot.CommandEventChanelRead())
 
-	// сохраняем данные по "пингам"
+	// при остановке перестаем получать обновления, в режиме webhook он снимается в телеграме,
+	// затем останавливаем запись результатов опросов, оставшиеся результаты записываются перед выходом
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+		<-stop
+
+		if err := bot.StopListen(); err != nil {
+			k.Log().Error(err.Error())
+		}
+
+		runer.Stop()
+	}()
+
+	// сохраняем данные по "пингам", возвращает управление после записи оставшихся результатов при остановке
 	runer.SaveCompleteUrl()
 }
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"log/slog"
+	"strings"
+)
+
+// MaxBroadcastLength ограничение длины объявления, вместе с заголовком оно должно поместиться в одно сообщение телеграма
+const MaxBroadcastLength = 3500
+
+type (
+	// RecipientProvider этот интерфейс реализует возможность получить пользователей, которым отправляются объявления
+	RecipientProvider interface {
+		BroadcastRecipients(ctx context.Context) ([]model.User, error)
+	}
+
+	// MessageSender этот интерфейс реализует возможность поставить сообщение в очередь на отправку
+	MessageSender interface {
+		SendMessage(msg tgbotapi.MessageConfig) error
+	}
+
+	// Broadcast отправляет объявление всем незаблокированным пользователям через общую очередь сообщений,
+	// поэтому рассылка соблюдает ограничения телеграма на частоту отправки
+	Broadcast struct {
+		log    *slog.Logger
+		users  RecipientProvider
+		sender MessageSender
+	}
+)
+
+func NewBroadcast(log *slog.Logger, users RecipientProvider, sender MessageSender) *Broadcast {
+	return &Broadcast{
+		log:    log,
+		users:  users,
+		sender: sender,
+	}
+}
+
+// Broadcast ставит объявление в очередь каждому пользователю и возвращает кол-во получателей,
+// заголовок объявления на языке пользователя, текст передается как есть без разметки
+func (b *Broadcast) Broadcast(ctx context.Context, text string) (int, error) {
+	const op = "admin.Broadcast.Broadcast"
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, i18n.NewError("admin.error_broadcast_empty")
+	}
+
+	if length := len([]rune(text)); length > MaxBroadcastLength {
+		return 0, i18n.NewError("admin.error_broadcast_length", length, MaxBroadcastLength)
+	}
+
+	users, err := b.users.BroadcastRecipients(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sent int
+	for _, user := range users {
+		loc := i18n.Resolve(user.Language, "")
+		msg := tgbotapi.NewMessage(user.Id, loc.T("admin.broadcast_message", html.EscapeString(text)))
+		msg.ParseMode = tgbotapi.ModeHTML
+
+		// один недоступный пользователь не должен останавливать рассылку остальным
+		if err := b.sender.SendMessage(msg); err != nil {
+			b.log.Error(fmt.Sprintf("%s, user: %d, error: %s", op, user.Id, err))
+			continue
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
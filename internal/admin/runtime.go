@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"runtime"
+	"time"
+)
+
+type (
+	// SchedulerStatsProvider этот интерфейс реализует возможность получить состояние опроса ссылок
+	SchedulerStatsProvider interface {
+		Stats() model.SchedulerStats
+	}
+
+	// QueueStatsProvider этот интерфейс реализует возможность получить состояние очереди исходящих сообщений
+	QueueStatsProvider interface {
+		Stats(ctx context.Context) (model.QueueStats, error)
+	}
+
+	// CommandStatsProvider этот интерфейс реализует возможность получить статистику выполнения команд бота
+	CommandStatsProvider interface {
+		Stats() []model.CommandStats
+	}
+
+	// Runtime собирает состояние приложения для администратора
+	Runtime struct {
+		scheduler SchedulerStatsProvider
+		queue     QueueStatsProvider
+		commands  CommandStatsProvider
+		startedAt time.Time
+	}
+)
+
+func NewRuntime(scheduler SchedulerStatsProvider, queue QueueStatsProvider, commands CommandStatsProvider) *Runtime {
+	return &Runtime{
+		scheduler: scheduler,
+		queue:     queue,
+		commands:  commands,
+		startedAt: time.Now(),
+	}
+}
+
+func (r *Runtime) Stats(ctx context.Context) (model.RuntimeStats, error) {
+	const op = "admin.Runtime.Stats"
+
+	queue, err := r.queue.Stats(ctx)
+	if err != nil {
+		return model.RuntimeStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return model.RuntimeStats{
+		Scheduler:  r.scheduler.Stats(),
+		Queue:      queue,
+		Commands:   r.commands.Stats(),
+		Goroutines: runtime.NumGoroutine(),
+		Uptime:     time.Since(r.startedAt).Seconds(),
+	}, nil
+}
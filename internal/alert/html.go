@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"regexp"
+	"strings"
+)
+
+// telegramTags теги, которые телеграм принимает в сообщениях с ParseMode HTML
+var telegramTags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "ins": true, "s": true, "strike": true, "del": true,
+	"span": true, "tg-spoiler": true, "a": true, "code": true, "pre": true, "blockquote": true, "tg-emoji": true,
+}
+
+var (
+	// htmlTagPattern открывающий или закрывающий тег, атрибуты в кавычках
+	htmlTagPattern = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:\s+[a-zA-Z-]+(?:="[^"<>]*"|='[^'<>]*')?)*)\s*>`)
+	// htmlEntityPattern именованная или числовая html сущность
+	htmlEntityPattern = regexp.MustCompile(`^&(?:lt|gt|amp|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+)
+
+// ValidateHTML проверяет, что текст примет телеграм: только поддерживаемые теги, каждый тег закрыт
+// в правильном порядке, символы "<" и "&" вне тегов и сущностей экранированы
+func ValidateHTML(text string) error {
+	var open []string
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			match := htmlTagPattern.FindStringSubmatch(text[i:])
+			if match == nil {
+				return i18n.NewError("template.error_html_char", "<", "&lt;")
+			}
+
+			tag := strings.ToLower(match[2])
+			if !telegramTags[tag] {
+				return i18n.NewError("template.error_html_tag", tag)
+			}
+
+			if match[1] == "" {
+				open = append(open, tag)
+			} else {
+				if len(open) == 0 || open[len(open)-1] != tag {
+					return i18n.NewError("template.error_html_unexpected", tag)
+				}
+
+				open = open[:len(open)-1]
+			}
+
+			i += len(match[0])
+		case '&':
+			entity := htmlEntityPattern.FindString(text[i:])
+			if entity == "" {
+				return i18n.NewError("template.error_html_char", "&", "&amp;")
+			}
+
+			i += len(entity)
+		default:
+			i++
+		}
+	}
+
+	if len(open) > 0 {
+		return i18n.NewError("template.error_html_unclosed", open[len(open)-1])
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package alert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// degradedLatencyRatio доля от времени ожидания ответа, после которой ссылка считается медленной
+const degradedLatencyRatio = 0.8
+
+const (
+	ErrorClassNone       = ""
+	ErrorClassTimeout    = "timeout"
+	ErrorClassDns        = "dns"
+	ErrorClassConnection = "connection"
+	ErrorClassTls        = "tls"
+	ErrorClassHttp       = "http"
+	ErrorClassSlow       = "slow"
+	ErrorClassUnknown    = "unknown"
+)
+
+// Status определяет состояние ссылки по результату опроса
+func Status(result model.PingResult) model.MonitorStatus {
+	if result.Error != nil {
+		return model.StatusDown
+	}
+
+	if result.StatusCode >= http.StatusBadRequest || isSlow(result) {
+		return model.StatusDegraded
+	}
+
+	return model.StatusUp
+}
+
+// ErrorClass тип проблемы по результату опроса, для доступной ссылки пустая строка
+func ErrorClass(result model.PingResult) string {
+	err := result.Error
+	if err == nil {
+		switch {
+		case result.StatusCode >= http.StatusBadRequest:
+			return ErrorClassHttp
+		case isSlow(result):
+			return ErrorClassSlow
+		default:
+			return ErrorClassNone
+		}
+	}
+
+	var (
+		dnsErr  *net.DNSError
+		netErr  net.Error
+		certErr *tls.CertificateVerificationError
+		unkErr  x509.UnknownAuthorityError
+		hostErr x509.HostnameError
+		invErr  x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		return ErrorClassTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorClassDns
+	case errors.As(err, &certErr) || errors.As(err, &unkErr) || errors.As(err, &hostErr) || errors.As(err, &invErr):
+		return ErrorClassTls
+	case errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET):
+		return ErrorClassConnection
+	default:
+		return ErrorClassUnknown
+	}
+}
+
+func isSlow(result model.PingResult) bool {
+	timeout, err := time.ParseDuration(result.Ping.ConnectionTime)
+	if err != nil {
+		return false
+	}
+
+	return result.RealConnectionTime > timeout.Seconds()*degradedLatencyRatio
+}
@@ -0,0 +1,177 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// maxTemplateLength ограничение длины готового уведомления, у телеграма лимит 4096 символов на сообщение
+const maxTemplateLength = 3000
+
+// Kinds виды уведомлений в порядке вывода пользователю
+var Kinds = []model.AlertKind{model.AlertDown, model.AlertRecovery, model.AlertDegraded}
+
+// defaultTemplateKeys ключи шаблонов в каталоге i18n, которые используются, если пользователь не задал свои
+var defaultTemplateKeys = map[model.AlertKind]string{
+	model.AlertDown:     "template.default_down",
+	model.AlertRecovery: "template.default_recovery",
+	model.AlertDegraded: "template.default_degraded",
+}
+
+// kindNameKeys ключи названий видов уведомлений в каталоге i18n
+var kindNameKeys = map[model.AlertKind]string{
+	model.AlertDown:     "template.kind_down",
+	model.AlertRecovery: "template.kind_recovery",
+	model.AlertDegraded: "template.kind_degraded",
+}
+
+type (
+	// Data переменные доступные в шаблоне уведомления, строки уже экранированы для HTML
+	Data struct {
+		Url        string
+		Name       string
+		Tags       []string
+		StatusCode int
+		ErrorClass string
+		Error      string
+		Latency    float64
+		Duration   time.Duration
+		Links      Links
+	}
+
+	Links struct {
+		Url       string
+		Statistic string
+	}
+)
+
+// NewData собирает переменные шаблона по результату опроса, outage - сколько длилась проблема
+func NewData(result model.PingResult, outage time.Duration, apiPath string) Data {
+	errText := ""
+	if result.Error != nil {
+		errText = result.Error.Error()
+	}
+
+	name := result.Ping.Url
+	if u, err := url.Parse(result.Ping.Url); err == nil && u.Host != "" {
+		name = u.Host
+	}
+
+	tags := make([]string, 0, len(result.Ping.Tags))
+	for _, tag := range result.Ping.Tags {
+		tags = append(tags, html.EscapeString(tag))
+	}
+
+	return Data{
+		Url:        html.EscapeString(result.Ping.Url),
+		Name:       html.EscapeString(name),
+		Tags:       tags,
+		StatusCode: result.StatusCode,
+		ErrorClass: ErrorClass(result),
+		Error:      html.EscapeString(errText),
+		Latency:    result.RealConnectionTime,
+		Duration:   outage.Round(time.Second),
+		Links: Links{
+			Url:       html.EscapeString(result.Ping.Url),
+			Statistic: html.EscapeString(fmt.Sprintf("%s/statistics/url?url=%s", apiPath, url.QueryEscape(result.Ping.Url))),
+		},
+	}
+}
+
+// SampleData пример переменных для предпросмотра и проверки шаблона
+func SampleData(apiPath string) Data {
+	result := model.PingResult{
+		Ping: model.Ping{
+			Url:            "https://example.com/health",
+			ConnectionTime: "10s",
+		},
+		Error:              errors.New(`Get "https://example.com/health": context deadline exceeded`),
+		RealConnectionTime: 10.0012,
+		StatusCode:         504,
+		IsCancel:           true,
+	}
+
+	data := NewData(result, 17*time.Minute+42*time.Second, apiPath)
+	data.ErrorClass = ErrorClassTimeout
+	data.Tags = []string{"env:prod", "team:payments"}
+
+	return data
+}
+
+// DefaultTemplate шаблон который используется, если пользователь не задал свой
+func DefaultTemplate(loc i18n.Locale, kind model.AlertKind) string {
+	return loc.T(defaultTemplateKeys[kind])
+}
+
+// KindName название вида уведомления для пользователя
+func KindName(loc i18n.Locale, kind model.AlertKind) string {
+	return loc.T(kindNameKeys[kind])
+}
+
+// VariablesHelp описание переменных и функций доступных в шаблоне
+func VariablesHelp(loc i18n.Locale) string {
+	return loc.T("template.variables")
+}
+
+// funcs функции доступные в шаблоне, длительность и числа форматируются по правилам языка получателя
+func funcs(loc i18n.Locale) template.FuncMap {
+	return template.FuncMap{
+		"join":     strings.Join,
+		"duration": loc.Duration,
+		"number":   loc.Number,
+	}
+}
+
+// Render заполняет шаблон на языке получателя, пустой шаблон заменяется шаблоном по умолчанию.
+// Готовый текст должен быть корректным html для телеграма, иначе уведомление не будет доставлено
+func Render(loc i18n.Locale, kind model.AlertKind, body string, data Data) (string, error) {
+	if body == "" {
+		body = DefaultTemplate(loc, kind)
+	}
+
+	// ошибки text/template показываются пользователю, чтобы он мог исправить шаблон
+	tpl, err := template.New(string(kind)).Funcs(funcs(loc)).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", i18n.WrapError(err, "template.error_syntax", err.Error())
+	}
+
+	str := strings.Builder{}
+	if err := tpl.Execute(&str, data); err != nil {
+		return "", i18n.WrapError(err, "template.error_syntax", err.Error())
+	}
+
+	if err := ValidateHTML(str.String()); err != nil {
+		return "", err
+	}
+
+	return str.String(), nil
+}
+
+// Validate проверяет что шаблон разбирается и заполняется на тестовых данных
+func Validate(kind model.AlertKind, body string) error {
+	if strings.TrimSpace(body) == "" {
+		return i18n.NewError("template.error_empty")
+	}
+
+	text, err := Render(i18n.Default, kind, body, SampleData(""))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return i18n.NewError("template.error_no_text")
+	}
+
+	if len(text) > maxTemplateLength {
+		return i18n.NewError("template.error_too_long", len(text), maxTemplateLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package chart
+
+import (
+	"strings"
+	"time"
+)
+
+type (
+	// Period промежуток времени графика, опросы группируются в интервалы длиной Step
+	Period struct {
+		Name     string // название для команды и апи: 24h, 7d, 30d
+		Duration time.Duration
+		Step     time.Duration
+		Layout   string // формат подписей оси времени
+	}
+)
+
+var (
+	Day   = Period{Name: "24h", Duration: 24 * time.Hour, Step: 30 * time.Minute, Layout: "15:04"}
+	Week  = Period{Name: "7d", Duration: 7 * 24 * time.Hour, Step: 4 * time.Hour, Layout: "02.01 15:04"}
+	Month = Period{Name: "30d", Duration: 30 * 24 * time.Hour, Step: 12 * time.Hour, Layout: "02.01"}
+
+	// Periods поддерживаемые промежутки в порядке вывода пользователю, первый используется по умолчанию
+	Periods = []Period{Day, Week, Month}
+)
+
+// ParsePeriod промежуток по названию, пустое название - промежуток по умолчанию
+func ParsePeriod(name string) (Period, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Periods[0], true
+	}
+
+	for _, p := range Periods {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Period{}, false
+}
+
+// From начало графика, выровненное по интервалу так же как интервалы в clickhouse (от начала эпохи)
+func (p Period) From(now time.Time) time.Time {
+	return now.Add(-p.Duration).Truncate(p.Step)
+}
@@ -0,0 +1,321 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"time"
+)
+
+// размеры картинки и областей графика в пикселях
+const (
+	width  = 960
+	height = 540
+
+	plotLeft  = 80
+	plotRight = width - 24
+
+	latencyTop    = 48
+	latencyBottom = 330
+	failureTop    = 360
+	failureBottom = 480
+	timeLabelY    = 500
+
+	ticks     = 4 // кол-во делений по вертикали
+	timeTicks = 6 // кол-во подписей оси времени
+)
+
+var (
+	colorBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	colorText       = color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff}
+	colorAxis       = color.RGBA{R: 0x55, G: 0x55, B: 0x55, A: 0xff}
+	colorGrid       = color.RGBA{R: 0xe6, G: 0xe6, B: 0xe6, A: 0xff}
+	colorP50        = color.RGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff}
+	colorP95        = color.RGBA{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff}
+	colorFailures   = color.RGBA{R: 0xd6, G: 0x27, B: 0x28, A: 0xff}
+)
+
+// шрифт содержит только ascii, поэтому подписи на графике не переводятся
+var face = basicfont.Face7x13
+
+type (
+	// Options подпись и промежуток графика, подписи оси времени выводятся в часовом поясе Location
+	Options struct {
+		Title    string
+		Period   Period
+		Location *time.Location
+		Now      time.Time
+	}
+
+	canvas struct {
+		img  *image.RGBA
+		from time.Time
+		to   time.Time
+	}
+)
+
+// Render рисует png: сверху линии медианы и 95-го перцентиля времени ответа, снизу столбцы неудачных опросов
+func Render(points model.ChartPointList, options Options) ([]byte, error) {
+	const op = "chart.Render"
+
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+
+	c := &canvas{
+		img:  image.NewRGBA(image.Rect(0, 0, width, height)),
+		from: options.Period.From(options.Now),
+		to:   options.Now,
+	}
+	draw.Draw(c.img, c.img.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+
+	c.header(options.Title + " (" + options.Period.Name + ")")
+	c.timeAxis(options.Period, options.Location)
+	c.latency(points, options.Period.Step)
+	c.failures(points, options.Period.Step)
+
+	if len(points) == 0 {
+		c.text((plotLeft+plotRight-textWidth("no data"))/2, (latencyTop+latencyBottom)/2, "no data", colorAxis)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// header подпись графика слева и легенда справа, длинная подпись обрезается
+func (c *canvas) header(title string) {
+	legend := []struct {
+		name  string
+		color color.Color
+	}{{"p50", colorP50}, {"p95", colorP95}, {"failures", colorFailures}}
+
+	x := plotRight
+	for n := len(legend) - 1; n >= 0; n-- {
+		x -= textWidth(legend[n].name)
+		c.text(x, 24, legend[n].name, colorText)
+		x -= 14
+		c.rect(x, 14, x+10, 24, legend[n].color)
+		x -= 16
+	}
+
+	maxChars := (x - plotLeft) / textWidth("w")
+	if runes := []rune(title); len(runes) > maxChars {
+		title = string(runes[:maxChars-3]) + "..."
+	}
+
+	c.text(plotLeft, 24, title, colorText)
+}
+
+// timeAxis вертикальная сетка и подписи времени под графиком
+func (c *canvas) timeAxis(period Period, location *time.Location) {
+	for n := 0; n <= timeTicks; n++ {
+		t := c.from.Add(period.Duration * time.Duration(n) / timeTicks)
+		x := c.x(t)
+
+		c.vline(x, latencyTop, latencyBottom, colorGrid)
+		c.vline(x, failureTop, failureBottom, colorGrid)
+
+		label := t.In(location).Format(period.Layout)
+		c.text(min(max(x-textWidth(label)/2, 0), width-textWidth(label)), timeLabelY, label, colorText)
+	}
+}
+
+// latency линии перцентилей в миллисекундах, интервалы без успешных опросов разрывают линию
+func (c *canvas) latency(points model.ChartPointList, step time.Duration) {
+	var top float64
+	for _, p := range points {
+		if p.HasLatency() {
+			top = math.Max(top, p.P95*1000)
+		}
+	}
+
+	top = c.scale(top, latencyTop, latencyBottom, func(v float64) string {
+		if v < 10 && v != math.Trunc(v) {
+			return fmt.Sprintf("%.1f ms", v)
+		}
+
+		return fmt.Sprintf("%.0f ms", v)
+	})
+
+	y := func(seconds float64) int {
+		return latencyBottom - int(seconds*1000/top*(latencyBottom-latencyTop))
+	}
+
+	for _, series := range []struct {
+		value func(p model.ChartPoint) float64
+		color color.Color
+	}{
+		{func(p model.ChartPoint) float64 { return p.P95 }, colorP95},
+		{func(p model.ChartPoint) float64 { return p.P50 }, colorP50},
+	} {
+		var prev *model.ChartPoint
+		for n := range points {
+			p := points[n]
+			if !p.HasLatency() {
+				prev = nil
+				continue
+			}
+
+			x := c.x(p.Time.Add(step / 2))
+			c.rect(x-1, y(series.value(p))-1, x+2, y(series.value(p))+2, series.color)
+
+			if prev != nil && p.Time.Sub(prev.Time) == step {
+				c.line(c.x(prev.Time.Add(step/2)), y(series.value(*prev)), x, y(series.value(p)), series.color)
+			}
+
+			prev = &points[n]
+		}
+	}
+}
+
+// failures столбцы неудачных опросов по интервалам
+func (c *canvas) failures(points model.ChartPointList, step time.Duration) {
+	var top float64
+	for _, p := range points {
+		top = math.Max(top, float64(p.Failures))
+	}
+
+	top = c.scale(math.Max(top, ticks), failureTop, failureBottom, func(v float64) string {
+		return fmt.Sprintf("%.0f", v)
+	})
+
+	for _, p := range points {
+		if p.Failures == 0 {
+			continue
+		}
+
+		x0, x1 := c.x(p.Time), c.x(p.Time.Add(step))-1
+		c.rect(x0, failureBottom-int(float64(p.Failures)/top*(failureBottom-failureTop)), max(x1, x0+1), failureBottom, colorFailures)
+	}
+}
+
+// scale рисует оси и горизонтальную сетку области графика и возвращает верхнюю границу шкалы
+func (c *canvas) scale(value float64, top, bottom int, label func(v float64) string) float64 {
+	step := niceStep(value / ticks)
+	for n := 0; n <= ticks; n++ {
+		y := bottom - (bottom-top)*n/ticks
+		if n > 0 {
+			c.hline(plotLeft, plotRight, y, colorGrid)
+		}
+
+		text := label(step * float64(n))
+		c.text(plotLeft-8-textWidth(text), y+4, text, colorText)
+	}
+
+	c.hline(plotLeft, plotRight, bottom, colorAxis)
+	c.vline(plotLeft, top, bottom, colorAxis)
+
+	return step * ticks
+}
+
+// x координата момента времени на оси, моменты за пределами графика прижимаются к краям
+func (c *canvas) x(t time.Time) int {
+	ratio := float64(t.Sub(c.from)) / float64(c.to.Sub(c.from))
+
+	return plotLeft + int(math.Max(0, math.Min(1, ratio))*(plotRight-plotLeft))
+}
+
+func (c *canvas) text(x, y int, s string, clr color.Color) {
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(clr),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func (c *canvas) rect(x0, y0, x1, y1 int, clr color.Color) {
+	draw.Draw(c.img, image.Rect(x0, y0, x1, y1), image.NewUniform(clr), image.Point{}, draw.Src)
+}
+
+func (c *canvas) hline(x0, x1, y int, clr color.Color) {
+	c.rect(x0, y, x1+1, y+1, clr)
+}
+
+func (c *canvas) vline(x, y0, y1 int, clr color.Color) {
+	c.rect(x, y0, x+1, y1+1, clr)
+}
+
+// line отрезок толщиной 2 пикселя по алгоритму Брезенхэма
+func (c *canvas) line(x0, y0, x1, y1 int, clr color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+
+	for {
+		c.img.Set(x0, y0, clr)
+		c.img.Set(x0, y0+1, clr)
+		c.img.Set(x0+1, y0, clr)
+
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// niceStep округляет шаг шкалы вверх до 1, 2 или 5, умноженных на степень десяти
+func niceStep(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+
+	pow := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*pow {
+			return m * pow
+		}
+	}
+
+	return 10 * pow
+}
+
+func textWidth(s string) int {
+	return font.MeasureString(face, s).Ceil()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
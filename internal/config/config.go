@@ -5,20 +5,38 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 )
 
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+
+	// WebhookPath путь апи сервера, на который телеграм отправляет обновления в режиме webhook
+	WebhookPath = "/telegram/webhook"
+)
+
+// webhookSecret допустимое значение секрета webhook по требованиям телеграма
+var webhookSecret = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 type (
 	// Config структура повторяет данные yaml конфига
 	Config struct {
-		Env             string   `yaml:"env" env-default:"prod"`
-		LogFile         string   `yaml:"log_file" env-default:"prod.log"`
-		BotToken        string   `yaml:"bot_token" env-required:"true"`
-		Database        Database `yaml:"database" env-required:"true"`
-		Jaeger          Jaeger   `yaml:"jaeger" env-required:"true"`
-		DefaultTimePing int64    `yaml:"default_time_ping" env-default:"300"`
-		AccessUserList  []int64  `yaml:"access_user_list"`
-		BaseApiUrl      string   `yaml:"base_api_url" env-required:"true"`
-		BaseApiProtocol string   `yaml:"base_api_protocol" env-default:"http://"`
+		Env             string       `yaml:"env" env-default:"prod"`
+		LogFile         string       `yaml:"log_file" env-default:"prod.log"`
+		BotToken        string       `yaml:"bot_token" env-required:"true"`
+		Database        Database     `yaml:"database" env-required:"true"`
+		Jaeger          Jaeger       `yaml:"jaeger" env-required:"true"`
+		DefaultTimePing int64        `yaml:"default_time_ping" env-default:"300"`
+		AccessUserList  []int64      `yaml:"access_user_list"` // переносится в бд при первом запуске, дальше доступом управляет /admin
+		AdminUserList   []int64      `yaml:"admin_user_list"`  // администраторы бота, синхронизируются в бд при запуске
+		AdminApiToken   string       `yaml:"admin_api_token"`  // токен для апи /admin в заголовке X-Admin-Token, пустой - апи администратора отключено
+		BaseApiUrl      string       `yaml:"base_api_url" env-required:"true"`
+		BaseApiProtocol string       `yaml:"base_api_protocol" env-default:"http://"`
+		Notification    Notification `yaml:"notification"`
+		Telegram        Telegram     `yaml:"telegram"`
 	}
 
 	Database struct {
@@ -51,6 +69,31 @@ type (
 		Db       int    `yaml:"db" env-default:"0"`
 	}
 
+	// Notification ограничения очереди исходящих сообщений бота
+	Notification struct {
+		GlobalRate     int           `yaml:"global_rate" env-default:"25"`     // сообщений в секунду на всего бота
+		ChatInterval   time.Duration `yaml:"chat_interval" env-default:"1s"`   // минимальный интервал между сообщениями в один чат
+		CoalesceWindow time.Duration `yaml:"coalesce_window" env-default:"3s"` // сколько ждать накопления уведомлений перед отправкой сводки
+		MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`     // кол-во попыток отправки при временных ошибках
+		RepeatAlert    time.Duration `yaml:"repeat_alert" env-default:"30m"`   // как часто повторять уведомление пока ссылка не работает, 0 - только при смене статуса
+	}
+
+	// Telegram способ получения обновлений от телеграма
+	Telegram struct {
+		Mode          string        `yaml:"mode" env-default:"polling"` // polling - long polling, webhook - обновления приходят на апи сервер
+		Webhook       Webhook       `yaml:"webhook"`
+		DialogTimeout time.Duration `yaml:"dialog_timeout" env-default:"15m"` // сколько диалог команды ждет ответа, после этого он отменяется с уведомлением
+		Workers       int           `yaml:"workers" env-default:"8"`          // сколько обновлений разных чатов обрабатывается одновременно
+		MaxPending    int           `yaml:"max_pending" env-default:"100"`    // сколько обновлений может ждать обработки, дальше получение новых приостанавливается
+		RateLimit     int           `yaml:"rate_limit" env-default:"30"`      // сколько сообщений в минуту принимается от одного пользователя, 0 - без ограничения
+	}
+
+	Webhook struct {
+		Url            string `yaml:"url"`                              // публичный адрес апи сервера, пример https://ping.example.com
+		Secret         string `yaml:"secret"`                           // значение заголовка X-Telegram-Bot-Api-Secret-Token, символы A-Z, a-z, 0-9, _ и -
+		MaxConnections int    `yaml:"max_connections" env-default:"40"` // сколько одновременных запросов может отправлять телеграм
+	}
+
 	Jaeger struct {
 		Url  string `yaml:"url" env-required:"true"`
 		Name string `yaml:"name" env-required:"true"`
@@ -74,9 +117,23 @@ func MustLoadConfig() *Config {
 		log.Fatalf("cannot read config: %s, error: %s", configPath, err)
 	}
 
+	if cfg.Telegram.IsWebhook() && (cfg.Telegram.Webhook.Url == "" || !webhookSecret.MatchString(cfg.Telegram.Webhook.Secret)) {
+		log.Fatalf("telegram webhook mode requires webhook url and secret of 1-256 characters A-Z, a-z, 0-9, _ and -")
+	}
+
 	return &cfg
 }
 
 func (c *Config) FullApiPath() string {
 	return fmt.Sprintf("%s%s", c.BaseApiProtocol, c.BaseApiUrl)
 }
+
+// IsWebhook получать обновления через webhook вместо long polling
+func (t Telegram) IsWebhook() bool {
+	return t.Mode == TelegramModeWebhook
+}
+
+// WebhookUrl полный адрес, который передается телеграму в setWebhook
+func (t Telegram) WebhookUrl() string {
+	return strings.TrimRight(t.Webhook.Url, "/") + WebhookPath
+}
@@ -0,0 +1,511 @@
+package i18n
+
+// en сообщения на английском
+var en = map[string]string{
+	"language.name": "English",
+
+	"duration.zero":    "0s",
+	"duration.days":    "%dd",
+	"duration.hours":   "%dh",
+	"duration.minutes": "%dm",
+	"duration.seconds": "%ds",
+
+	// общие
+	"common.error":          "Something went wrong, please try again later",
+	"common.error_list":     "Failed to load the list, please try again later",
+	"common.error_state":    "failed to save the current step",
+	"common.error_restart":  "Something went wrong, please run the command again",
+	"common.url_not_found":  "URL not found, it may have been removed",
+	"common.url_not_exists": "This URL does not exist",
+	"common.url_exists":     "This URL already exists",
+	"common.empty_list":     "You have no URLs yet",
+	"common.invalid_time":   "invalid duration, examples: 100ms|10s|1h|1s500ms",
+	"common.error_check":    "Failed to check the URL, please try again",
+	"common.error_save":     "Failed to save, please try again later",
+	"common.error_update":   "Failed to update the URL, please try again later",
+	"common.panic":          "Something went wrong while running the command, sorry. We already know about the error, please try again later",
+	"common.rate_limited":   "Too many messages in a row, try again in %s",
+
+	// пространство пользователя
+	"member.read_only":    "You are a viewer in this workspace, only the owner and editors can change URLs",
+	"member.no_workspace": "You are not registered or not a member of any workspace, run /%s",
+
+	// /add_url
+	"add_url.ask_url":             "Enter the URL",
+	"add_url.ask_connection_time": "Enter the maximum response timeout, examples: 100ms|10s|1h|1s500ms",
+	"add_url.ask_ping_time":       "Enter how often the URL should be checked (at least 30 seconds), examples: 30m20s|1h",
+	"add_url.invalid_url":         "Invalid URL, please try again",
+	"add_url.success":             "URL added",
+
+	// кнопки под уведомлениями
+	"alert.button_ack":    "✅ Acknowledge",
+	"alert.button_snooze": "💤 Snooze for %s",
+	"alert.button_check":  "🔄 Check",
+	"alert.button_stats":  "📊 Statistics",
+	"alert.snoozed":       "Alerts for <code>%s</code> are snoozed until %s",
+	"alert.snooze_muted":  "Alerts for <code>%s</code> are already muted for longer, no need to snooze",
+	"alert.acknowledged":  "%s\n\n✅ Acknowledged by <b>%s</b> at %s",
+	"alert.check_down":    "⚠️ <code>%s</code> is down\n\n<u>%s</u>",
+	"alert.check_up":      "✅ <code>%s</code> is up\n\nStatus code - <code>%d</code>\nResponse time - <code>%s</code>",
+
+	// /api_key_refresh
+	"api_key.error":   "Failed to generate a token, please try again later",
+	"api_key.success": "api-key: <code>%s</code> \n\n link: <code>%s?api-key=%s</code>\n\nThe key gives access to the current workspace, to access another workspace switch to it with /%s and refresh the key",
+
+	// /connect_chat
+	"connect_chat.usage":     "Run /%s in a group chat or specify a channel: <code>/%s @channel</code>. The bot must be added to the chat and allowed to send messages",
+	"connect_chat.not_found": "Chat not found, make sure the bot has been added to it",
+	"connect_chat.not_admin": "Only an administrator of the group chat or channel can connect it",
+	"connect_chat.error":     "Failed to connect the chat, please try again later",
+	"connect_chat.success":   "Chat <b>%s</b> is connected, workspace alerts will be sent to it. The owner can disconnect it with /%s",
+
+	// /critical_url
+	"critical_url.ask_url": "Choose a URL to mark as critical or unmark, or enter the URL",
+	"critical_url.off":     "<code>%s</code> is no longer critical",
+	"critical_url.on":      "<code>%s</code> is marked as critical, its alerts are delivered during quiet hours too",
+
+	// /edit_url
+	"edit_url.ask_pick":               "Choose a URL to edit or enter the URL",
+	"edit_url.ask_url":                "Enter the new URL, the check history will be moved to it",
+	"edit_url.ask_connection_time":    "Enter the maximum response timeout, examples: 100ms|10s|1s500ms",
+	"edit_url.ask_ping_time":          "Enter how often the URL should be checked (at least 30s), examples: 30s|5m|1h",
+	"edit_url.ask_tags":               "Send tags separated by spaces or commas, e.g. <code>env:prod team:payments</code>. They replace the current tags, <code>-</code> removes all tags",
+	"edit_url.fields":                 "🌐 <code>%s</code> \n⏳ Timeout - <code>%s</code> \n🕤 Check interval - <code>%s</code>\n🔁 Alert repeat - <code>%s</code>\n%s\nWhat would you like to change?",
+	"edit_url.button_url":             "🌐 URL",
+	"edit_url.button_connection_time": "⏳ Timeout",
+	"edit_url.button_ping_time":       "🕤 Interval",
+	"edit_url.button_tags":            "🏷 Tags",
+	"edit_url.button_repeat_alert":    "🔁 Alert repeat",
+	"edit_url.ask_repeat_alert":       "Enter how often to repeat the alert while the URL is down (at least 1m), examples: 15m|1h. <code>0</code> - alert only when the status changes, <code>-</code> - default interval",
+	"edit_url.repeat_default":         "default",
+	"edit_url.repeat_off":             "off",
+	"edit_url.invalid_value":          "%s, please try again",
+	"edit_url.invalid_field":          "Choose what to change using the buttons",
+	"edit_url.url_exists":             "This URL already exists, enter another one",
+	"edit_url.success":                "URL updated\n\n🌐 <code>%s</code> \n⏳ Timeout - <code>%s</code> \n🕤 Check interval - <code>%s</code>\n🔁 Alert repeat - <code>%s</code>\n%s",
+
+	// /incidents
+	"incidents.empty":             "There have been no incidents",
+	"incidents.title":             "🚨 Recent incidents\n\n",
+	"incidents.button_timeline":   "%d. Timeline",
+	"incidents.not_found":         "Incident not found, the URL may have been removed",
+	"incidents.timeline":          "\n\n📜 Timeline\n",
+	"incidents.started":           "🕒 Started - <code>%s</code>\n",
+	"incidents.open":              "🔥 Ongoing\n",
+	"incidents.ended":             "🏁 Ended - <code>%s</code>\n",
+	"incidents.duration":          "⏱ Duration - <code>%s</code>",
+	"incidents.event_down":        "⛔️ Down",
+	"incidents.event_degraded":    "🐢 Degraded",
+	"incidents.event_recovery":    "✅ Recovered",
+	"incidents.event_acknowledge": "👀 Acknowledged",
+
+	// /list_url
+	"list_url.item": "🌐 <code>%s</code> (id <code>%d</code>)\n⏳ Timeout - <code>%s</code> \n🕤 Check interval - <code>%s</code>\n%s\n",
+
+	// отключение уведомлений
+	"mute.forever":          "indefinitely",
+	"mute.until":            "until %s (%s left)",
+	"mute_all.invalid_time": "Invalid duration, examples: /mute_all 30m | /mute_all 2h | /mute_all (indefinitely)",
+	"mute.error":            "Failed to mute alerts, please try again later",
+	"mute_all.success":      "Alerts are muted %s",
+	"unmute_all.error":      "Failed to unmute alerts, please try again later",
+	"unmute_all.success":    "Alerts are unmuted",
+	"mute_list.empty":       "No muted alerts",
+	"mute_list.all":         "🔕 All alerts are muted %s\n\n",
+	"mute_list.url":         "🔕 <code>%s</code> - %s\n",
+
+	// /mute_url
+	"mute_url.ask_url":      "Choose a URL to mute or enter the URL",
+	"mute_url.ask_time":     "How long should alerts be muted, examples: 30m|2h|1h30m, 0 - indefinitely",
+	"mute_url.invalid_time": "invalid duration, examples: 30m|2h|1h30m, 0 - indefinitely",
+	"mute_url.success":      "Alerts for <code>%s</code> are muted %s",
+
+	// роли участников пространства
+	"role.owner":  "owner",
+	"role.editor": "editor",
+	"role.viewer": "viewer",
+
+	// /workspace
+	"workspace.ask_name":             "Enter a name for the new workspace",
+	"workspace.invalid_name":         "The name must be 1 to %d characters long, please try again",
+	"workspace.error":                "Failed to load the workspace, please try again later",
+	"workspace.error_create":         "Failed to create the workspace, please try again later",
+	"workspace.error_switch":         "Failed to switch the workspace, you may have been removed from it",
+	"workspace.error_invite":         "Failed to create an invite, please try again later",
+	"workspace.error_remove":         "Failed to remove the member, please try again later",
+	"workspace.error_unlink":         "Failed to disconnect the chat, please try again later",
+	"workspace.owner_only":           "Only the workspace owner can manage members and chats",
+	"workspace.member_not_found":     "Member not found, the owner cannot be removed",
+	"workspace.invite":               "Invite with the %s role is valid until %s, forward the link to the member:\n\nhttps://t.me/%s?start=%s",
+	"workspace.current":              "🏢 Workspace <b>%s</b>, your role - %s\n\n",
+	"workspace.members":              "👥 %d member\n|👥 %d members\n",
+	"workspace.chats":                "\n💬 Alert chats\n",
+	"workspace.no_chats":             "none connected, connect a chat or channel with /%s\n",
+	"workspace.button_remove":        "❌ Remove %s",
+	"workspace.button_unlink":        "🔌 Disconnect %s",
+	"workspace.button_invite_editor": "✉️ Invite an editor",
+	"workspace.button_invite_viewer": "✉️ Invite a viewer",
+	"workspace.button_new":           "➕ New workspace",
+
+	// /start
+	"registration.exists":           "You are already registered",
+	"registration.success":          "You have successfully registered",
+	"registration.ask_timezone":     "Enter your time zone, examples: Europe/London|America/New_York|+3|UTC-5, 0 - keep UTC",
+	"registration.invite_not_found": "The invite was not found or has expired, ask the workspace owner for a new one",
+	"registration.error_invite":     "Failed to accept the invite, please try again later",
+	"registration.invite_accepted":  "You have joined the <b>%s</b> workspace as %s, it is now your current workspace. Switch workspaces with /%s",
+	"registration.timezone_saved":   "Time zone <code>%s</code> saved, change it and set up quiet hours with /%s",
+
+	// короткие названия дней недели
+	"weekday.sun": "sun",
+	"weekday.mon": "mon",
+	"weekday.tue": "tue",
+	"weekday.wed": "wed",
+	"weekday.thu": "thu",
+	"weekday.fri": "fri",
+	"weekday.sat": "sat",
+
+	// /settings
+	"settings.title":                  "⚙️ Settings\n\n",
+	"settings.timezone":               "🕒 Time zone - <code>%s</code>\n",
+	"settings.language":               "🌐 Language - %s\n",
+	"settings.language_auto_current":  "same as Telegram (%s)",
+	"settings.quiet_hours":            "🌙 Quiet hours - ",
+	"settings.quiet_hours_empty":      "not set\n",
+	"settings.button_timezone":        "🕒 Time zone",
+	"settings.button_quiet_hours":     "🌙 Quiet hours",
+	"settings.button_language":        "🌐 Language",
+	"settings.language_auto":          "Same as Telegram",
+	"settings.ask_language":           "Choose the bot language",
+	"settings.ask_field":              "Choose the setting to change",
+	"settings.ask_timezone":           "Enter your time zone, examples: Europe/London|America/New_York|+3|UTC-5",
+	"settings.ask_quiet_hours":        "Enter quiet hours by day of the week, one interval per line, examples:\n<code>mon-fri 23:00-07:00</code>\n<code>sat,sun 00:00-10:00</code>\n<code>all 22:00-08:00</code>\n\nDuring quiet hours alerts for non-critical URLs are delivered as a single message once they end. 0 - disable quiet hours",
+	"settings.invalid_timezone":       "Unknown time zone, please try again. Enter your time zone, examples: Europe/London|America/New_York|+3|UTC-5",
+	"settings.invalid_quiet_hours":    "Error: %s, please try again",
+	"settings.error":                  "Failed to load settings, please try again later",
+	"settings.error_timezone":         "Failed to save the time zone, please try again later",
+	"settings.error_quiet_hours":      "Failed to save quiet hours, please try again later",
+	"settings.error_language":         "Failed to save the language, please try again later",
+	"settings.error_field":            "unknown setting: %s, available are timezone and quiet",
+	"settings.error_empty_timezone":   "time zone is not specified",
+	"settings.error_unknown_timezone": "unknown time zone: %s",
+	"settings.error_quiet_line":       "invalid line format: %s",
+	"settings.error_quiet_interval":   "invalid interval: %s",
+	"settings.error_quiet_empty":      "empty interval: %s",
+	"settings.error_weekday":          "unknown day of the week: %s",
+	"settings.error_time":             "invalid time: %s",
+
+	// статистика
+	"statistic.error":        "Failed to load statistics",
+	"statistic_url.ask_url":  "Choose a URL to show statistics for or enter the URL",
+	"statistic.summary":      "🌐 <code>%s</code> \n🔄 Checks - <code>%d</code> \n👌 Successful checks - <code>%d</code> \n⛔️ Failed checks - <code>%d</code> \n⏳ Max response time - <code>%s</code> \n⏳ Min response time - <code>%s</code> \n🕤 Average response time - <code>%s</code>\n",
+	"statistic.no_incidents": "🚨 No incidents\n\n",
+	"statistic.incidents":    "🚨 Incidents - <code>%d</code> \n🛠 Mean time to recovery (MTTR) - <code>%s</code> \n📈 Mean time between failures (MTBF) - <code>%s</code>\n\n",
+	"statistic.errors":       "Errors\n\n",
+	"statistic.group":        "🏷 Tag <code>%s</code>, links - <code>%d</code> \n🔄 Checks - <code>%d</code> \n👌 Successful checks - <code>%d</code> \n⛔️ Failed checks - <code>%d</code> \n⏳ Max response time - <code>%s</code> \n⏳ Min response time - <code>%s</code> \n🕤 Average response time - <code>%s</code>\n",
+	"statistic.group_errors": "Most frequent errors\n\n",
+	"statistic.error_item":   "Occurred %d time\n<code>%s</code> \n\n|Occurred %d times\n<code>%s</code> \n\n",
+
+	// выбор ссылки кнопками
+	"url_picker.back":    "◀️ Back",
+	"url_picker.next":    "Next ▶️ %d/%d",
+	"url_picker.confirm": "✅ Yes",
+	"url_picker.cancel":  "✖️ Cancel",
+
+	// постраничный вывод длинных списков
+	"pager.prev": "◀",
+	"pager.page": "page %d/%d",
+	"pager.next": "▶",
+
+	// пошаговые диалоги
+	"dialog.back":             "↩️ Previous question",
+	"dialog.cancel":           "✖️ Cancel",
+	"dialog.cancelled":        "Cancelled",
+	"dialog.expired":          "The dialog has expired, start again: /%s",
+	"dialog.option_not_found": "This option is no longer available, choose another one",
+	"dialog.timeout":          "⌛ The /%s command was cancelled: no answer for %s. Start again when you are ready",
+
+	// /remove_url
+	"remove_url.ask_url":   "Choose a URL to remove or enter the URL",
+	"remove_url.not_found": "URL not found, it may have already been removed",
+	"remove_url.error":     "Failed to remove the URL, please try again later",
+	"remove_url.success":   "<code>%s</code> removed",
+	"remove_url.cancelled": "Removal cancelled",
+	"remove_url.confirm":   "Remove <code>%s</code>? Its incidents will be removed too",
+
+	// /unmute_url
+	"unmute_url.ask_url": "Choose a URL to unmute or enter the URL",
+	"unmute_url.empty":   "There are no muted URLs",
+	"unmute_url.success": "Alerts for <code>%s</code> are unmuted",
+
+	// шаблоны уведомлений
+	"template.kind_down":             "Down",
+	"template.kind_recovery":         "Recovery",
+	"template.kind_degraded":         "Degraded",
+	"template.default_down":          "<code>⚠️{{.Url}}</code> \n \n <u>{{.Error}}</u>{{if .Duration}}\n\nDown for {{duration .Duration}}{{end}}",
+	"template.default_recovery":      "✅ <code>{{.Url}}</code> is up again\n\nIt was down for {{duration .Duration}}",
+	"template.default_degraded":      "🐢 <code>{{.Url}}</code> is degraded ({{.ErrorClass}})\n\nStatus code - <code>{{.StatusCode}}</code>\nResponse time - <code>{{number .Latency 4}}</code>{{if .Duration}}\n\nDegraded for {{duration .Duration}}{{end}}",
+	"template.variables":             "<code>{{.Url}}</code> - URL\n<code>{{.Name}}</code> - name (URL host)\n<code>{{.Tags}}</code> - tags, example <code>{{join .Tags \", \"}}</code>\n<code>{{.StatusCode}}</code> - status code\n<code>{{.ErrorClass}}</code> - problem type: timeout, dns, connection, tls, http, slow, unknown\n<code>{{.Error}}</code> - error text\n<code>{{.Latency}}</code> - response time in seconds, example <code>{{number .Latency 2}}</code>\n<code>{{.Duration}}</code> - how long the problem lasted, in a repeated alert - how long the link has been down, example <code>{{duration .Duration}}</code>\n<code>{{.Links.Url}}</code>, <code>{{.Links.Statistic}}</code> - links to the site and to statistics in the API",
+	"template.error_empty":           "the template is empty",
+	"template.error_syntax":          "template error: %s",
+	"template.error_no_text":         "the template has no text",
+	"template.error_too_long":        "the alert is too long: %d characters, the maximum is %d",
+	"template.error_html_char":       "character %s must be replaced with %s",
+	"template.error_html_tag":        "tag <%s> is not supported by Telegram",
+	"template.error_html_unexpected": "closing tag </%s> has no matching opening tag or is out of order",
+	"template.error_html_unclosed":   "tag <%s> is not closed",
+
+	// /template
+	"template.ask_kind":         "Choose the alert whose template you want to change",
+	"template_preview.ask_kind": "Choose an alert to preview",
+	"template.error":            "Failed to load the template, please try again later",
+	"template.error_save":       "Failed to save the template, please try again later",
+	"template.error_kind":       "Unknown notification: %s, choose it with a button",
+	"template.invalid":          "Template error: %s",
+	"template.invalid_retry":    "Template error: %s\n\nFix the template and send it again",
+	"template.saved":            "Template saved",
+	"template.saved_preview":    "Template saved, the alert will look like this:\n\n%s",
+	"template.current":          "Current «%s» template",
+	"template.current_default":  "Current «%s» template (default)",
+	"template.variables_title":  "Available variables:\n",
+	"template.ask_body":         "\n\nSend a new template, Telegram HTML markup is supported. 0 - restore the default template",
+
+	// уведомления вне команд
+	"mute.released_url": "🔔 The mute has expired, alerts for <code>%s</code> are back on",
+	"mute.released_all": "🔔 The mute has expired, all alerts are back on",
+	"digest.title":      "🌙 %d alert arrived during quiet hours\n\n|🌙 %d alerts arrived during quiet hours\n\n",
+	"digest.more":       "... and %d more",
+
+	// http api
+	"api.forbidden":             "Forbidden: %s",
+	"api.access_revoked":        "Access to the service has been revoked, contact the administrator",
+	"api.url_not_found":         "Link not found",
+	"api.url_exists":            "Link already exists",
+	"api.ping_list_error":       "Failed to get the list of links",
+	"api.ping_delete_error":     "Failed to delete the link",
+	"api.ping_delete_read_only": "Your role does not allow deleting links",
+	"api.ping_patch_error":      "Failed to update the link",
+	"api.ping_patch_read_only":  "Your role does not allow editing links",
+	"api.statistic_error":       "Failed to get statistics",
+	"api.incident_error":        "Failed to get the incident",
+	"api.incident_not_found":    "Incident not found",
+	"api.incident_list_error":   "Failed to get the list of incidents",
+	"api.invalid_limit":         "limit must be a number from 1 to %d",
+	"api.ping_import_error":     "Failed to import links",
+	"api.ping_import_read_only": "Your role does not allow importing links",
+	"api.ping_export_error":     "Failed to export links",
+	"api.import_no_file":        "No import file, send it in the file field or in the request body",
+	"api.import_too_large":      "The file is too large, maximum %d KB",
+	"api.chart_period":          "period must be one of: 24h, 7d, 30d",
+	"api.chart_error":           "Failed to build the chart",
+	"api.status_error":          "Failed to get the state of links",
+	"api.ping_check_error":      "Failed to check the link",
+	"api.invalid_tag":           "Invalid tag: %s",
+	"api.tag_required":          "Tag is required",
+	"api.admin_forbidden":       "Administrators only",
+	"api.admin_token_invalid":   "Invalid admin token in the X-Admin-Token header",
+	"api.admin_users_error":     "Failed to get the user list",
+	"api.admin_user_not_found":  "User not found",
+	"api.admin_block_error":     "Failed to change the user block",
+	"api.admin_broadcast_error": "Failed to send the announcement",
+	"api.admin_stats_error":     "Failed to get the application stats",
+
+	// изменение ссылки
+	"patch.error_invalid":          "invalid link data",
+	"patch.error_empty":            "no fields to update were given",
+	"patch.error_url":              "invalid link data: invalid url %q",
+	"patch.error_connection_time":  "invalid link data: invalid response timeout %q, examples: 100ms|10s|1s500ms",
+	"patch.error_ping_time":        "invalid link data: invalid check interval %q, examples: 30s|5m|1h",
+	"patch.error_min_ping_time":    "invalid link data: check interval must be at least %s",
+	"patch.error_repeat_alert":     "invalid link data: invalid alert repeat interval %q, examples: 0|15m|1h",
+	"patch.error_min_repeat_alert": "invalid link data: alert repeat interval must be 0 or at least %s",
+	"patch.error_tags_count":       "invalid link data: a link can have at most %d tags",
+	"patch.error_tag":              "invalid link data: invalid tag %q, a tag is up to %d characters of latin letters, digits and _ . : / -",
+
+	// описание команд для /help и меню телеграма
+	"start.description":            "Sign up and join a workspace",
+	"start.help":                   "Signs you up and creates a personal workspace, after that the bot asks for your time zone. If the bot is invite-only, signing up requires a code from an administrator.\n\nExamples:\n<code>/start</code> - sign up\n<code>/start &lt;code&gt;</code> - sign up with an invite code or join a workspace by invite, the owner creates the invite link in /workspace",
+	"add_url.description":          "Add a link to monitor",
+	"add_url.help":                 "Adds a link to the current workspace. The bot asks for the address, the response timeout and the check interval one by one, the answers can be passed right in the command - the bot asks only for the missing ones.\n\nExamples:\n<code>/add_url https://example.com 10s 1m</code>\n<code>/add_url https://example.com --timeout 5s --interval 5m --tag prod</code>\n\n<code>--timeout</code> - timeout, <code>--interval</code> - interval, at least 30s, <code>--tag</code> - tags, can be repeated",
+	"remove_url.description":       "Remove a link",
+	"remove_url.help":              "Removes a link together with its incidents. Pick the link with a button or send its address, then confirm the removal.\n\nExamples: <code>/remove_url</code>, <code>/remove_url 42</code> - by link id, <code>/remove_url https://example.com</code>",
+	"edit_url.description":         "Change address, timeout, interval, alert repeat or tags",
+	"edit_url.help":                "Changes a link without losing its history: pick the link, then the field, and send the new value. The link, the field and the value can be passed right in the command.\n\nExample values:\n<code>https://example.com/health</code> - address\n<code>5s</code> - timeout\n<code>5m</code> - interval\n<code>1h</code> - repeat the alert while the link is down, <code>0</code> - only on status change, <code>-</code> - default\n<code>env:prod team:payments</code> - tags, <code>-</code> - remove all tags\n\nExample: <code>/edit_url 42 ping_time 5m</code>, fields: url, connection_time, ping_time, repeat_alert, tags",
+	"list_url.description":         "List links",
+	"list_url.help":                "Shows the links of the current workspace with their timeout, check interval and tags. If a tag is given, only links with it are shown.\n\nExamples:\n<code>/list_url</code>\n<code>/list_url env:prod</code>",
+	"critical_url.description":     "Mark a link as critical",
+	"critical_url.help":            "Turns the critical mark on or off. Alerts for critical links are delivered during quiet hours too.\n\nExamples: <code>/critical_url</code>, then pick the link, or <code>/critical_url 42</code>",
+	"mute_all.description":         "Mute all alerts",
+	"mute_all.help":                "Mutes all alerts for the given time or until you turn them back on.\n\nExamples:\n<code>/mute_all 30m</code>\n<code>/mute_all 2h</code>\n<code>/mute_all</code> - indefinitely",
+	"unmute_all.description":       "Unmute all alerts",
+	"unmute_all.help":              "Turns back on the alerts muted with /mute_all.\n\nExample: <code>/unmute_all</code>",
+	"mute_url.description":         "Mute alerts for a link",
+	"mute_url.help":                "Mutes alerts for one link: pick the link and send the duration.\n\nExample durations:\n<code>30m</code>\n<code>1h30m</code>\n<code>0</code> - indefinitely\n\nIn one command: <code>/mute_url 42 1h</code>",
+	"unmute_url.description":       "Unmute alerts for a link",
+	"unmute_url.help":              "Turns alerts for a link back on, pick it from the list of muted links.\n\nExamples: <code>/unmute_url</code>, <code>/unmute_url 42</code>",
+	"mute_list.description":        "Muted alerts",
+	"mute_list.help":               "Shows muted alerts and how long until they are turned back on.\n\nExample: <code>/mute_list</code>",
+	"statistic.description":        "Current statistics for links",
+	"statistic.help":               "Shows check statistics for all links of the workspace for the current period. If a tag is given, the combined statistics of all links with it come first, then each of them.\n\nExamples:\n<code>/statistic</code>\n<code>/statistic team:payments</code>",
+	"statistic_all.description":    "All-time statistics",
+	"statistic_all.help":           "Shows all-time check statistics for all links of the workspace, including incidents, MTTR and MTBF.\n\nExample: <code>/statistic_all</code>",
+	"statistic_url.description":    "Statistics for one link",
+	"statistic_url.help":           "Shows statistics and the list of errors for the chosen link.\n\nExamples: <code>/statistic_url</code>, then <code>https://example.com</code>, or <code>/statistic_url 42</code>",
+	"incidents.description":        "Recent incidents",
+	"incidents.help":               "Shows recent incidents of the workspace, the button under an incident shows its timeline.\n\nExample: <code>/incidents</code>",
+	"template.description":         "Edit an alert template",
+	"template.help":                "Edits the down, recovery or degraded alert template. Telegram HTML markup and variables are supported, the bot lists the variables after you pick a template.\n\nExample template:\n<code>⚠️ {{.Url}} - {{.Error}}</code>\n\n<code>0</code> - restore the default template",
+	"template_preview.description": "Preview an alert",
+	"template_preview.help":        "Shows what an alert looks like with the current template using sample data.\n\nExample: <code>/template_preview</code>, then pick the alert",
+	"settings.description":         "Time zone, quiet hours and language",
+	"settings.help":                "Shows and changes your settings: time zone, quiet hours and bot language.\n\nA setting can be changed right away: <code>/settings timezone +3</code>\n\nExample quiet hours:\n<code>mon-fri 23:00-07:00</code>\n<code>all 22:00-08:00</code>",
+	"api_key_refresh.description":  "New API key",
+	"api_key_refresh.help":         "Creates a new API key for the current workspace, the old key stops working.\n\nExample: <code>/api_key_refresh</code>",
+	"workspace.description":        "Workspaces and members",
+	"workspace.help":               "Shows the current workspace, its members and connected chats. Use the buttons to switch or create a workspace, invite and remove members.\n\nExample: <code>/workspace</code>",
+	"connect_chat.description":     "Connect a chat for alerts",
+	"connect_chat.help":            "Connects a group chat or channel, workspace alerts will be sent there. The bot must be added to the chat.\n\nExamples:\n<code>/connect_chat</code> - in a group chat\n<code>/connect_chat @channel</code> - in private messages",
+	"import.description":           "Import links from a file",
+	"import.help":                  "Adds links from a CSV, YAML or JSON file. After the command send the file as a document, every record is validated separately.\n\nModes:\n<code>/import</code> - add new links, skip existing ones\n<code>/import upsert</code> - also update settings of existing links\n<code>/import dry_run</code> - only check the file and show the report\n\nCSV example:\n<code>url,connection_time,ping_time,critical,tags\nhttps://example.com,10s,1m,false,env:prod team:payments</code>\n\nYAML example:\n<code>- url: https://example.com\n  connection_time: 10s\n  ping_time: 1m\n  tags: [env:prod]</code>\n\nJSON example:\n<code>[{\"url\": \"https://example.com\", \"connection_time\": \"10s\", \"ping_time\": \"1m\"}]</code>",
+	"export.description":           "Export links to a file",
+	"export.help":                  "Exports the links of the current workspace to a file that can be loaded back with /import.\n\nExamples:\n<code>/export</code> - pick the format with a button\n<code>/export csv</code>\n<code>/export yaml</code>\n<code>/export json</code>",
+	"chart.description":            "Response time chart of a link",
+	"chart.help":                   "Draws the median and 95th percentile response time of a link and the number of failed checks. Period: 24h (default), 7d or 30d.\n\nExamples:\n<code>/chart</code> - pick a link with a button\n<code>/chart https://example.com</code>\n<code>/chart https://example.com 7d</code>",
+	"status.description":           "Current state of every link",
+	"status.help":                  "Shows what is working right now and what is not: the status of each link, how long it has lasted, the response time and the error of the last check. Down and degraded links come first.\n\n🔴 down\n🟡 degraded\n⚪ not checked yet\n⏸ notifications muted\n🟢 up\n\nIf a tag is given, only links with it are shown: <code>/status env:prod</code>",
+	"check_now.description":        "Check a link right now",
+	"check_now.help":               "Checks a link immediately instead of waiting for the next scheduled check and shows the status, request phase timings, main response headers and the error. The result is saved to statistics and updates the state of the link.\n\nExamples:\n<code>/check_now</code> - pick a link with a button\n<code>/check_now https://example.com</code>",
+	"cancel.description":           "Cancel the current action",
+	"cancel.help":                  "Aborts an unfinished dialog of any command, such as adding a URL, and tells what was cancelled. A dialog left without an answer is cancelled automatically and the bot sends a message about it.\n\nExample: <code>/cancel</code>",
+	"admin.description":            "Bot management for administrators",
+	"admin.help":                   "Administrator commands:\n<code>/admin users</code> - users and their monitor counts\n<code>/admin block 123</code> - revoke a user's access to the bot and API\n<code>/admin unblock 123</code> - restore access\n<code>/admin access</code> - bot access mode, <code>/admin access open|invite|allowlist</code> - change it\n<code>/admin allow 123</code> - add a Telegram user to the allowlist\n<code>/admin disallow 123</code> - remove from the allowlist\n<code>/admin invite [uses] [ttl]</code> - invite code, for example <code>/admin invite 5 72h</code>\n<code>/admin invites</code> - active invite codes\n<code>/admin revoke code</code> - revoke a code\n<code>/admin broadcast text</code> - send an announcement to all users\n<code>/admin stats</code> - application stats",
+	"help.description":             "Commands and help",
+	"help.help":                    "Lists the commands, with a command name shows detailed help for it.\n\nExamples:\n<code>/help</code>\n<code>/help add_url</code>",
+
+	// /help
+	"help.title":   "📖 Commands\n\n",
+	"help.item":    "/%s - %s\n",
+	"help.footer":  "\nMore about a command: <code>/help add_url</code>",
+	"help.command": "<b>/%s</b> - %s\n\n%s",
+	"help.flags":   "\n\nNamed arguments: %s, the bot asks for missing values in the dialog",
+	"help.unknown": "Command <code>%s</code> not found, see the list: /help",
+
+	// импорт и экспорт ссылок
+	"import.usage":              "Unknown mode, examples: <code>/import</code> | <code>/import upsert</code> | <code>/import dry_run</code>",
+	"import.ask_file":           "Send a CSV, YAML or JSON file as a document, up to %d links. The file format is described in <code>/help import</code>",
+	"import.unknown_format":     "Could not detect the file format, the extension must be .csv, .yaml, .yml or .json",
+	"import.too_large":          "The file is too large, maximum %d KB",
+	"import.error_no_file":      "Send the file as a document or cancel the import with the button under the question",
+	"import.error_download":     "Could not download the file, please try the import again later",
+	"import.invalid_file":       "Could not parse the file: %s\n\nFix the file and send it again",
+	"import.error":              "An error occurred during import, try again later",
+	"import.report_title":       "📥 Import finished\n\n",
+	"import.report_dry_run":     "🧪 File check, nothing was saved\n\n",
+	"import.report_summary":     "➕ Created - <code>%d</code>\n✏️ Updated - <code>%d</code>\n➖ Unchanged - <code>%d</code>\n⏭ Skipped - <code>%d</code>\n⛔️ Invalid - <code>%d</code>\n\n",
+	"import.report_row":         "Line %d <code>%s</code>: %s\n",
+	"import.report_more":        "... and %d more\n",
+	"import.report_apply":       "\nTo apply the changes run /%s without dry_run and send the file again",
+	"import.error_url_required": "url is missing",
+	"import.error_required":     "connection_time and ping_time are required for a new link",
+	"import.error_duplicate":    "the link already appeared on line %d",
+	"import.error_exists":       "the link already exists with other settings, use upsert mode to update it",
+	"import.error_save":         "could not save the link",
+	"transfer.error_format":     "unknown file format, csv, yaml and json are supported",
+	"transfer.error_empty":      "the file contains no links",
+	"transfer.error_too_many":   "the file contains more than %d links",
+	"transfer.error_csv":        "csv parse error: %s",
+	"transfer.error_csv_header": "the csv header has no url column",
+	"transfer.error_yaml":       "yaml parse error, the file must contain a list of links: %s",
+	"transfer.error_json":       "json parse error, the file must contain an array of links: %s",
+	"transfer.error_row":        "invalid record: %s",
+	"transfer.error_critical":   "invalid critical value %q, examples: true|false",
+	"export.ask_format":         "Choose the file format",
+	"export.error":              "An error occurred during export, try again later",
+	"export.success":            "📦 Exported %d link|📦 Exported %d links",
+
+	// график времени ответа
+	"chart.ask_url": "Choose a link to chart",
+	"chart.usage":   "Usage: /chart <link> [24h|7d|30d]",
+	"chart.no_data": "No checks of %s in the last %s",
+	"chart.error":   "Failed to build the chart, please try again later",
+	"chart.caption": "📈 %s for the last %s\nChecks: %d, failed: %d, uptime: %s%%",
+
+	// текущее состояние ссылок
+	"status.title":         "📋 State of links: %d\n",
+	"status.title_tag":     "📋 State of links tagged <code>%s</code>: %d\n",
+	"status.refresh":       "🔄 Refresh",
+	"status.since":         "%s for %s",
+	"status.latency":       ", response %s s",
+	"status.code":          ", code %d",
+	"status.name_up":       "up",
+	"status.name_down":     "down",
+	"status.name_degraded": "degraded",
+	"status.name_pending":  "not checked yet",
+	"status.name_paused":   "notifications muted",
+
+	// внеплановая проверка ссылки
+	"check_now.ask_url":           "Choose a link to check",
+	"check_now.again":             "🔁 Check again",
+	"check_now.title":             "🔎 Check of %s\n",
+	"check_now.latency":           "\nResponse time: %s s",
+	"check_now.timing_dns":        "DNS %s s",
+	"check_now.timing_connect":    "connect %s s",
+	"check_now.timing_tls":        "TLS %s s",
+	"check_now.timing_first_byte": "first byte %s s",
+	"check_now.headers":           "\n\nHeaders:",
+	"check_now.error":             "\n\nError: <i>%s</i>",
+
+	// /cancel
+	"cancel.done":    "Cancelled:\n",
+	"cancel.item":    "/%s - %s",
+	"cancel.nothing": "There is nothing to cancel",
+
+	// теги ссылок
+	"tag.list":          "🏷 Tags: %s\n",
+	"tag.empty_list":    "No links tagged <code>%s</code>",
+	"tag.error_invalid": "Invalid tag %q, a tag is up to %d characters of latin letters, digits and _ . : / -",
+
+	// /admin
+	"admin.users_title":            "👥 Users: %d\n",
+	"admin.user_item":              "\n<code>%d</code> %s - %s%s",
+	"admin.monitors":               "%d monitor|%d monitors",
+	"admin.mark_admin":             " 👑",
+	"admin.mark_blocked":           " ⛔ blocked",
+	"admin.error_user_id":          "Specify a user id, example: <code>/admin block 123</code>",
+	"admin.user_not_found":         "User <code>%d</code> not found",
+	"admin.error_block_admin":      "User %d is an administrator and cannot be blocked",
+	"admin.blocked":                "User <code>%d</code> is blocked",
+	"admin.unblocked":              "User <code>%d</code> is unblocked",
+	"admin.broadcast_message":      "📢 <b>Announcement</b>\n\n%s",
+	"admin.broadcast_success":      "Announcement queued for %d user|Announcement queued for %d users",
+	"admin.error_broadcast_empty":  "Specify the announcement text: /admin broadcast text",
+	"admin.error_broadcast_length": "The announcement is too long: %d characters, at most %d",
+	"admin.stats":                  "📊 Application stats\n\nScheduled checks: <code>%d</code>\nResults waiting to be saved: <code>%d</code>\nFailed statistic inserts: <code>%d</code>\nChats in the message queue: <code>%d</code>\nQueue lag: <code>%s</code>\nGoroutines: <code>%d</code>\nUptime: <code>%s</code>",
+	"admin.stats_commands":         "\n\nCommands since start:",
+	"admin.stats_command":          "\n/%s - runs <code>%d</code>, errors <code>%d</code>, average <code>%s s</code>, max <code>%s s</code>",
+
+	// доступ к боту
+	"access.invite_required":   "The bot is invite-only. Signing up requires a code from an administrator: <code>/start code</code>",
+	"access.invite_invalid":    "The invite code was not found, has expired or has already been used, ask an administrator for a new one",
+	"access.error_mode":        "Unknown access mode %q, allowed: open, invite, allowlist",
+	"access.mode":              "🔐 Access mode: %s\nIn the allowlist: <code>%d</code>",
+	"access.mode_open":         "<b>open</b> - anyone can use the bot",
+	"access.mode_invite":       "<b>invite</b> - signing up requires an invite code",
+	"access.mode_allowlist":    "<b>allowlist</b> - only users from the allowlist",
+	"access.allowed":           "User <code>%d</code> is added to the allowlist",
+	"access.disallowed":        "User <code>%d</code> is removed from the allowlist",
+	"access.not_allowed":       "User <code>%d</code> is not in the allowlist",
+	"access.error_invite_args": "Specify the number of uses from 1 to %d and a validity period of at most %s, example: <code>/admin invite 5 72h</code>",
+	"access.code_created":      "Invite code <code>%s</code> for %d uses is valid until %s, forward the link:\n\nhttps://t.me/%s?start=%s",
+	"access.codes_title":       "🎟 Active invite codes: %d\n",
+	"access.code_item":         "\n<code>%s</code> - used %d of %d, until %s",
+	"access.error_code":        "Specify the invite code, example: <code>/admin revoke code</code>",
+	"access.code_not_found":    "Invite code <code>%s</code> not found",
+	"access.code_revoked":      "Invite code <code>%s</code> is revoked",
+
+	// аргументы команд вместо диалога
+	"arguments.error_value":   "No value given for argument --%s",
+	"arguments.error_quote":   "Unclosed quote in command arguments",
+	"arguments.error_unknown": "Unknown argument --%s for /%s, available: %s",
+	"arguments.error_extra":   "Extra command arguments: %s",
+}
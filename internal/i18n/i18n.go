@@ -0,0 +1,243 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Ru Locale = "ru"
+	En Locale = "en"
+
+	Default = Ru
+
+	// Fallback язык для пользователей, язык телеграма которых не поддерживается
+	Fallback = En
+)
+
+// Locales поддерживаемые языки в порядке вывода пользователю
+var Locales = []Locale{Ru, En}
+
+// catalogues сообщения по языкам, ключ - идентификатор сообщения, значение - строка формата для fmt.Sprintf,
+// формы множественного числа разделяются символом "|", см. Locale.Plural
+var catalogues = map[Locale]map[string]string{
+	Ru: ru,
+	En: en,
+}
+
+// russianSpeaking языки пользователей, которым по умолчанию удобнее русский интерфейс
+var russianSpeaking = []string{"ru", "uk", "be", "kk"}
+
+type (
+	Locale string
+
+	// Error ошибка для пользователя, текст которой переводится по ключу каталога, см. Locale.Error
+	Error struct {
+		Key  string
+		Args []any
+		err  error
+	}
+
+	localeContextKey struct{}
+)
+
+// Parse язык по коду из телеграма или заголовка Accept-Language (ru, en-US, uk), пустой код - язык по умолчанию
+func Parse(code string) Locale {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return Default
+	}
+
+	code, _, _ = strings.Cut(code, ",")
+	code, _, _ = strings.Cut(code, ";")
+	code, _, _ = strings.Cut(code, "-")
+	code, _, _ = strings.Cut(code, "_")
+
+	for _, lang := range russianSpeaking {
+		if code == lang {
+			return Ru
+		}
+	}
+
+	return Fallback
+}
+
+// Codes коды языков телеграма, для которых используется не Fallback язык, язык по коду можно получить через Parse
+func Codes() []string {
+	return append([]string(nil), russianSpeaking...)
+}
+
+// Resolve язык выбранный пользователем в настройках, если не выбран - язык телеграма
+func Resolve(userLanguage, telegramLanguage string) Locale {
+	if l := Locale(userLanguage); l.IsValid() {
+		return l
+	}
+
+	return Parse(telegramLanguage)
+}
+
+// WithLocale сохраняет язык пользователя в контексте
+func WithLocale(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, l)
+}
+
+// FromContext язык пользователя из контекста, см. WithLocale
+func FromContext(ctx context.Context) Locale {
+	if l, ok := ctx.Value(localeContextKey{}).(Locale); ok {
+		return l
+	}
+
+	return Default
+}
+
+// NewError ошибка с переводимым текстом
+func NewError(key string, args ...any) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+// WrapError ошибка с переводимым текстом, которая оборачивает err для errors.Is
+func WrapError(err error, key string, args ...any) *Error {
+	return &Error{Key: key, Args: args, err: err}
+}
+
+func (e *Error) Error() string {
+	return Default.T(e.Key, e.Args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// IsValid поддерживается ли язык
+func (l Locale) IsValid() bool {
+	_, ok := catalogues[l]
+
+	return ok
+}
+
+// Name название языка на нем самом
+func (l Locale) Name() string {
+	return l.T("language.name")
+}
+
+// T сообщение по ключу, если перевода нет используется язык по умолчанию, а затем сам ключ
+func (l Locale) T(key string, args ...any) string {
+	format := l.lookup(key)
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// Plural выбирает форму множественного числа для n и подставляет в нее n и остальные аргументы
+func (l Locale) Plural(key string, n int, args ...any) string {
+	forms := strings.Split(l.lookup(key), "|")
+
+	form := forms[len(forms)-1]
+	if i := l.pluralForm(n); i < len(forms) {
+		form = forms[i]
+	}
+
+	return fmt.Sprintf(form, append([]any{n}, args...)...)
+}
+
+// Number число с указанным кол-вом знаков после запятой и разделителем принятым в языке
+func (l Locale) Number(f float64, precision int) string {
+	str := strconv.FormatFloat(f, 'f', precision, 64)
+	if l == Ru {
+		str = strings.Replace(str, ".", ",", 1)
+	}
+
+	return str
+}
+
+// Duration длительность вида "1 ч 2 мин 3 с", секунды выводятся только для длительностей меньше часа
+func (l Locale) Duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return l.T("duration.zero")
+	}
+
+	days := int(d / (time.Hour * 24))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+	seconds := int(d/time.Second) % 60
+
+	parts := make([]string, 0, 3)
+	if days > 0 {
+		parts = append(parts, l.T("duration.days", days))
+	}
+
+	if hours > 0 {
+		parts = append(parts, l.T("duration.hours", hours))
+	}
+
+	if minutes > 0 {
+		parts = append(parts, l.T("duration.minutes", minutes))
+	}
+
+	if seconds > 0 && d < time.Hour {
+		parts = append(parts, l.T("duration.seconds", seconds))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Seconds длительность переданная в секундах, см. Duration
+func (l Locale) Seconds(seconds float64) string {
+	return l.Duration(time.Duration(math.Round(seconds)) * time.Second)
+}
+
+// Error текст ошибки для пользователя. Текст внутренней ошибки может содержать детали базы или сети,
+// поэтому для непереводимой ошибки возвращается общее сообщение, саму ошибку вызывающий код пишет в лог
+func (l Locale) Error(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return l.T(e.Key, e.Args...)
+	}
+
+	return l.T("common.error")
+}
+
+func (l Locale) lookup(key string) string {
+	if format, ok := catalogues[l][key]; ok {
+		return format
+	}
+
+	if format, ok := catalogues[Default][key]; ok {
+		return format
+	}
+
+	return key
+}
+
+// pluralForm индекс формы множественного числа: для русского 0 - одна, 1 - несколько (2-4), 2 - много,
+// для английского 0 - одна, 1 - остальные
+func (l Locale) pluralForm(n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	if l != Ru {
+		if n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 10 || n%100 >= 20):
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,511 @@
+package i18n
+
+// ru сообщения на русском, язык по умолчанию, должен содержать все ключи
+var ru = map[string]string{
+	"language.name": "Русский",
+
+	"duration.zero":    "0 с",
+	"duration.days":    "%d д",
+	"duration.hours":   "%d ч",
+	"duration.minutes": "%d мин",
+	"duration.seconds": "%d с",
+
+	// общие
+	"common.error":          "Произошла ошибка, повторите позже",
+	"common.error_list":     "Произошла ошибка при получении списка, повторите позже",
+	"common.error_state":    "ошибка при сохранении текущего шага",
+	"common.error_restart":  "Произошла ошибка, выполните команду заново",
+	"common.url_not_found":  "Ссылка не найдена, возможно она была удалена",
+	"common.url_not_exists": "Данная ссылка не существует",
+	"common.url_exists":     "Данная ссылка уже существует",
+	"common.empty_list":     "У вас еще нет записей",
+	"common.invalid_time":   "указано неверное время, примеры: 100ms|10s|1h|1s500ms",
+	"common.error_check":    "Ошибка при проверке ссылки, повторите ввод",
+	"common.error_save":     "Произошла ошибка при сохранении, повторите позже",
+	"common.error_update":   "Произошла ошибка при изменении ссылки, повторите позже",
+	"common.panic":          "Что-то пошло не так при выполнении команды, извините. Мы уже знаем об ошибке, попробуйте повторить позже",
+	"common.rate_limited":   "Слишком много сообщений подряд, повторите через %s",
+
+	// пространство пользователя
+	"member.read_only":    "У вас роль наблюдателя в этом пространстве, изменять ссылки могут только владелец и редакторы",
+	"member.no_workspace": "Вы не зарегистрированы или не состоите ни в одном пространстве, выполните /%s",
+
+	// /add_url
+	"add_url.ask_url":             "Укажите url адрес",
+	"add_url.ask_connection_time": "Укажите максимально время ожидания ответа, примеры: 100ms|10s|1h|1s500ms",
+	"add_url.ask_ping_time":       "Укажите время с какой периодичностью необходимо опрашивать ссылку в секундах (минимально 30), примеры: 30m20s|1h",
+	"add_url.invalid_url":         "Не валидная ссылка, повторите ввод",
+	"add_url.success":             "запись успешно добавлена",
+
+	// кнопки под уведомлениями
+	"alert.button_ack":    "✅ Принять",
+	"alert.button_snooze": "💤 Отложить на %s",
+	"alert.button_check":  "🔄 Проверить",
+	"alert.button_stats":  "📊 Статистика",
+	"alert.snoozed":       "Уведомления по <code>%s</code> отложены до %s",
+	"alert.snooze_muted":  "Уведомления по <code>%s</code> уже отключены на больший срок, откладывание не требуется",
+	"alert.acknowledged":  "%s\n\n✅ Принято: <b>%s</b> в %s",
+	"alert.check_down":    "⚠️ <code>%s</code> недоступна\n\n<u>%s</u>",
+	"alert.check_up":      "✅ <code>%s</code> доступна\n\nКод ответа - <code>%d</code>\nВремя ответа - <code>%s</code>",
+
+	// /api_key_refresh
+	"api_key.error":   "Произошла ошибка при генерации токена, повторите позже",
+	"api_key.success": "api-key: <code>%s</code> \n\n ссылка: <code>%s?api-key=%s</code>\n\nКлюч дает доступ к текущему пространству, для другого пространства переключитесь на него командой /%s и обновите ключ",
+
+	// /connect_chat
+	"connect_chat.usage":     "Выполните /%s в групповом чате или укажите канал: <code>/%s @channel</code>. Бот должен быть добавлен в чат и иметь право отправлять сообщения",
+	"connect_chat.not_found": "Чат не найден, проверьте что бот добавлен в него",
+	"connect_chat.not_admin": "Подключить групповой чат или канал может только его администратор",
+	"connect_chat.error":     "Произошла ошибка при подключении чата, повторите позже",
+	"connect_chat.success":   "Чат <b>%s</b> подключен, уведомления пространства будут приходить в него. Отключить чат может владелец командой /%s",
+
+	// /critical_url
+	"critical_url.ask_url": "Выберите ссылку, которую необходимо пометить критичной или снять отметку, или укажите url адрес",
+	"critical_url.off":     "Ссылка <code>%s</code> больше не критичная",
+	"critical_url.on":      "Ссылка <code>%s</code> помечена критичной, уведомления по ней приходят и в тихие часы",
+
+	// /edit_url
+	"edit_url.ask_pick":               "Выберите ссылку которую необходимо изменить или укажите url адрес",
+	"edit_url.ask_url":                "Укажите новый url адрес, история опросов будет перенесена на него",
+	"edit_url.ask_connection_time":    "Укажите максимально время ожидания ответа, примеры: 100ms|10s|1s500ms",
+	"edit_url.ask_ping_time":          "Укажите с какой периодичностью необходимо опрашивать ссылку (минимально 30s), примеры: 30s|5m|1h",
+	"edit_url.ask_tags":               "Укажите теги через пробел или запятую, например <code>env:prod team:payments</code>. Теги заменят текущие, <code>-</code> - убрать все теги",
+	"edit_url.fields":                 "🌐 <code>%s</code> \n⏳ Время ожидания - <code>%s</code> \n🕤 Время периодичности - <code>%s</code>\n🔁 Повтор уведомления - <code>%s</code>\n%s\nЧто необходимо изменить?",
+	"edit_url.button_url":             "🌐 Адрес",
+	"edit_url.button_connection_time": "⏳ Время ожидания",
+	"edit_url.button_ping_time":       "🕤 Периодичность",
+	"edit_url.button_tags":            "🏷 Теги",
+	"edit_url.button_repeat_alert":    "🔁 Повтор уведомления",
+	"edit_url.ask_repeat_alert":       "Укажите как часто повторять уведомление пока ссылка не работает (минимально 1m), примеры: 15m|1h. <code>0</code> - уведомлять только при смене статуса, <code>-</code> - интервал по умолчанию",
+	"edit_url.repeat_default":         "по умолчанию",
+	"edit_url.repeat_off":             "не повторять",
+	"edit_url.invalid_value":          "%s, повторите ввод",
+	"edit_url.invalid_field":          "Выберите что необходимо изменить кнопкой",
+	"edit_url.url_exists":             "Данная ссылка уже существует, укажите другой адрес",
+	"edit_url.success":                "Ссылка изменена\n\n🌐 <code>%s</code> \n⏳ Время ожидания - <code>%s</code> \n🕤 Время периодичности - <code>%s</code>\n🔁 Повтор уведомления - <code>%s</code>\n%s",
+
+	// /incidents
+	"incidents.empty":             "Инцидентов не было",
+	"incidents.title":             "🚨 Последние инциденты\n\n",
+	"incidents.button_timeline":   "%d. Хронология",
+	"incidents.not_found":         "Инцидент не найден, возможно ссылка была удалена",
+	"incidents.timeline":          "\n\n📜 Хронология\n",
+	"incidents.started":           "🕒 Начало - <code>%s</code>\n",
+	"incidents.open":              "🔥 Продолжается\n",
+	"incidents.ended":             "🏁 Окончание - <code>%s</code>\n",
+	"incidents.duration":          "⏱ Длительность - <code>%s</code>",
+	"incidents.event_down":        "⛔️ Недоступна",
+	"incidents.event_degraded":    "🐢 Работает с проблемами",
+	"incidents.event_recovery":    "✅ Восстановлена",
+	"incidents.event_acknowledge": "👀 Принято",
+
+	// /list_url
+	"list_url.item": "🌐 <code>%s</code> (id <code>%d</code>)\n⏳ Время ожидания - <code>%s</code> \n🕤 Время периодичности - <code>%s</code>\n%s\n",
+
+	// отключение уведомлений
+	"mute.forever":          "бессрочно",
+	"mute.until":            "до %s (осталось %s)",
+	"mute_all.invalid_time": "Указано неверное время, примеры: /mute_all 30m | /mute_all 2h | /mute_all (бессрочно)",
+	"mute.error":            "Произошла ошибка при отключении уведомлений, повторите позже",
+	"mute_all.success":      "Уведомления отключены %s",
+	"unmute_all.error":      "Произошла ошибка при включении уведомлений, повторите позже",
+	"unmute_all.success":    "Уведомления включены",
+	"mute_list.empty":       "Нет отключенных уведомлений",
+	"mute_list.all":         "🔕 Все уведомления отключены %s\n\n",
+	"mute_list.url":         "🔕 <code>%s</code> - %s\n",
+
+	// /mute_url
+	"mute_url.ask_url":      "Выберите ссылку по которой необходимо отключить уведомления или укажите url адрес",
+	"mute_url.ask_time":     "Укажите на какое время отключить уведомления, примеры: 30m|2h|1h30m, 0 - бессрочно",
+	"mute_url.invalid_time": "указано неверное время, примеры: 30m|2h|1h30m, 0 - бессрочно",
+	"mute_url.success":      "Уведомления по <code>%s</code> отключены %s",
+
+	// роли участников пространства
+	"role.owner":  "владелец",
+	"role.editor": "редактор",
+	"role.viewer": "наблюдатель",
+
+	// /workspace
+	"workspace.ask_name":             "Укажите название нового пространства",
+	"workspace.invalid_name":         "Название должно быть от 1 до %d символов, повторите ввод",
+	"workspace.error":                "Произошла ошибка при получении пространства, повторите позже",
+	"workspace.error_create":         "Произошла ошибка при создании пространства, повторите позже",
+	"workspace.error_switch":         "Не удалось переключить пространство, возможно вас исключили из него",
+	"workspace.error_invite":         "Произошла ошибка при создании приглашения, повторите позже",
+	"workspace.error_remove":         "Произошла ошибка при исключении участника, повторите позже",
+	"workspace.error_unlink":         "Произошла ошибка при отключении чата, повторите позже",
+	"workspace.owner_only":           "Управлять участниками и чатами может только владелец пространства",
+	"workspace.member_not_found":     "Участник не найден, владельца исключить нельзя",
+	"workspace.invite":               "Приглашение с ролью %s действует до %s, перешлите ссылку участнику:\n\nhttps://t.me/%s?start=%s",
+	"workspace.current":              "🏢 Пространство <b>%s</b>, ваша роль - %s\n\n",
+	"workspace.members":              "👥 %d участник\n|👥 %d участника\n|👥 %d участников\n",
+	"workspace.chats":                "\n💬 Чаты для уведомлений\n",
+	"workspace.no_chats":             "не подключены, подключить чат или канал можно командой /%s\n",
+	"workspace.button_remove":        "❌ Исключить %s",
+	"workspace.button_unlink":        "🔌 Отключить %s",
+	"workspace.button_invite_editor": "✉️ Пригласить редактора",
+	"workspace.button_invite_viewer": "✉️ Пригласить наблюдателя",
+	"workspace.button_new":           "➕ Новое пространство",
+
+	// /start
+	"registration.exists":           "Вы уже зарегестрированы",
+	"registration.success":          "Вы успешно зарегестрировались",
+	"registration.ask_timezone":     "Укажите ваш часовой пояс, примеры: Europe/Moscow|Asia/Yekaterinburg|+3|UTC-5, 0 - оставить UTC",
+	"registration.invite_not_found": "Приглашение не найдено или истекло, попросите владельца пространства создать новое",
+	"registration.error_invite":     "Произошла ошибка при принятии приглашения, повторите позже",
+	"registration.invite_accepted":  "Вы вступили в пространство <b>%s</b> с ролью %s, оно выбрано текущим. Переключить пространство можно командой /%s",
+	"registration.timezone_saved":   "Часовой пояс <code>%s</code> сохранен, изменить его и настроить тихие часы можно командой /%s",
+
+	// короткие названия дней недели
+	"weekday.sun": "вс",
+	"weekday.mon": "пн",
+	"weekday.tue": "вт",
+	"weekday.wed": "ср",
+	"weekday.thu": "чт",
+	"weekday.fri": "пт",
+	"weekday.sat": "сб",
+
+	// /settings
+	"settings.title":                  "⚙️ Настройки\n\n",
+	"settings.timezone":               "🕒 Часовой пояс - <code>%s</code>\n",
+	"settings.language":               "🌐 Язык - %s\n",
+	"settings.language_auto_current":  "как в Telegram (%s)",
+	"settings.quiet_hours":            "🌙 Тихие часы - ",
+	"settings.quiet_hours_empty":      "не заданы\n",
+	"settings.button_timezone":        "🕒 Часовой пояс",
+	"settings.button_quiet_hours":     "🌙 Тихие часы",
+	"settings.button_language":        "🌐 Язык",
+	"settings.language_auto":          "Как в Telegram",
+	"settings.ask_language":           "Выберите язык бота",
+	"settings.ask_field":              "Выберите настройку, которую необходимо изменить",
+	"settings.ask_timezone":           "Укажите ваш часовой пояс, примеры: Europe/Moscow|Asia/Yekaterinburg|+3|UTC-5",
+	"settings.ask_quiet_hours":        "Укажите тихие часы по дням недели, каждый интервал с новой строки, примеры:\n<code>пн-пт 23:00-07:00</code>\n<code>сб,вс 00:00-10:00</code>\n<code>все 22:00-08:00</code>\n\nВ тихие часы уведомления по некритичным ссылкам придут одним сообщением после их окончания. 0 - отключить тихие часы",
+	"settings.invalid_timezone":       "Неизвестный часовой пояс, повторите ввод. Укажите ваш часовой пояс, примеры: Europe/Moscow|Asia/Yekaterinburg|+3|UTC-5",
+	"settings.invalid_quiet_hours":    "Ошибка: %s, повторите ввод",
+	"settings.error":                  "Произошла ошибка при получении настроек, повторите позже",
+	"settings.error_timezone":         "Произошла ошибка при сохранении часового пояса, повторите позже",
+	"settings.error_quiet_hours":      "Произошла ошибка при сохранении тихих часов, повторите позже",
+	"settings.error_language":         "Произошла ошибка при сохранении языка, повторите позже",
+	"settings.error_field":            "неизвестная настройка: %s, доступны timezone и quiet",
+	"settings.error_empty_timezone":   "не указан часовой пояс",
+	"settings.error_unknown_timezone": "неизвестный часовой пояс: %s",
+	"settings.error_quiet_line":       "не верный формат строки: %s",
+	"settings.error_quiet_interval":   "не верный интервал: %s",
+	"settings.error_quiet_empty":      "пустой интервал: %s",
+	"settings.error_weekday":          "неизвестный день недели: %s",
+	"settings.error_time":             "не верное время: %s",
+
+	// статистика
+	"statistic.error":        "Произошла ошибка при получении статистики",
+	"statistic_url.ask_url":  "Выберите ссылку по которой необходимо вывести статистику или укажите url адрес",
+	"statistic.summary":      "🌐 <code>%s</code> \n🔄 Коли-во соединений - <code>%d</code> \n👌 Коли-во успешных соединений - <code>%d</code> \n⛔️ Коли-во прерваных соединений - <code>%d</code> \n⏳ Макс-ое время ожидания - <code>%s</code> \n⏳ Мин-ое время ожидания - <code>%s</code> \n🕤 Среднее время ожидания - <code>%s</code>\n",
+	"statistic.no_incidents": "🚨 Инцидентов не было\n\n",
+	"statistic.incidents":    "🚨 Коли-во инцидентов - <code>%d</code> \n🛠 Среднее время восстановления (MTTR) - <code>%s</code> \n📈 Среднее время между инцидентами (MTBF) - <code>%s</code>\n\n",
+	"statistic.errors":       "Список ошибок\n\n",
+	"statistic.group":        "🏷 Тег <code>%s</code>, ссылок - <code>%d</code> \n🔄 Коли-во соединений - <code>%d</code> \n👌 Коли-во успешных соединений - <code>%d</code> \n⛔️ Коли-во прерваных соединений - <code>%d</code> \n⏳ Макс-ое время ожидания - <code>%s</code> \n⏳ Мин-ое время ожидания - <code>%s</code> \n🕤 Среднее время ожидания - <code>%s</code>\n",
+	"statistic.group_errors": "Самые частые ошибки\n\n",
+	"statistic.error_item":   "Повторилась %d раз\n<code>%s</code> \n\n|Повторилась %d раза\n<code>%s</code> \n\n|Повторилась %d раз\n<code>%s</code> \n\n",
+
+	// выбор ссылки кнопками
+	"url_picker.back":    "◀️ Назад",
+	"url_picker.next":    "Вперед ▶️ %d/%d",
+	"url_picker.confirm": "✅ Да",
+	"url_picker.cancel":  "✖️ Отмена",
+
+	// постраничный вывод длинных списков
+	"pager.prev": "◀",
+	"pager.page": "стр. %d/%d",
+	"pager.next": "▶",
+
+	// пошаговые диалоги
+	"dialog.back":             "↩️ К предыдущему вопросу",
+	"dialog.cancel":           "✖️ Отмена",
+	"dialog.cancelled":        "Действие отменено",
+	"dialog.expired":          "Время ответа истекло, начните заново: /%s",
+	"dialog.option_not_found": "Вариант больше недоступен, выберите другой",
+	"dialog.timeout":          "⌛ Команда /%s отменена: не было ответа %s. Начните заново, когда будете готовы",
+
+	// /remove_url
+	"remove_url.ask_url":   "Выберите ссылку которую необходимо удалить или укажите url адрес",
+	"remove_url.not_found": "Ссылка не найдена, возможно она уже удалена",
+	"remove_url.error":     "Произошла ошибка при удалении, повторите позже",
+	"remove_url.success":   "Ссылка <code>%s</code> удалена",
+	"remove_url.cancelled": "Удаление отменено",
+	"remove_url.confirm":   "Удалить ссылку <code>%s</code>? Инциденты по ней тоже будут удалены",
+
+	// /unmute_url
+	"unmute_url.ask_url": "Выберите ссылку по которой необходимо включить уведомления или укажите url адрес",
+	"unmute_url.empty":   "Нет ссылок с отключенными уведомлениями",
+	"unmute_url.success": "Уведомления по <code>%s</code> включены",
+
+	// шаблоны уведомлений
+	"template.kind_down":             "Недоступность",
+	"template.kind_recovery":         "Восстановление",
+	"template.kind_degraded":         "Деградация",
+	"template.default_down":          "<code>⚠️{{.Url}}</code> \n \n <u>{{.Error}}</u>{{if .Duration}}\n\nНе работает уже {{duration .Duration}}{{end}}",
+	"template.default_recovery":      "✅ <code>{{.Url}}</code> снова доступна\n\nБыла недоступна {{duration .Duration}}",
+	"template.default_degraded":      "🐢 <code>{{.Url}}</code> работает с проблемами ({{.ErrorClass}})\n\nКод ответа - <code>{{.StatusCode}}</code>\nВремя ответа - <code>{{number .Latency 4}}</code>{{if .Duration}}\n\nПроблемы уже {{duration .Duration}}{{end}}",
+	"template.variables":             "<code>{{.Url}}</code> - ссылка\n<code>{{.Name}}</code> - название (домен ссылки)\n<code>{{.Tags}}</code> - теги, пример <code>{{join .Tags \", \"}}</code>\n<code>{{.StatusCode}}</code> - код ответа\n<code>{{.ErrorClass}}</code> - тип проблемы: timeout, dns, connection, tls, http, slow, unknown\n<code>{{.Error}}</code> - текст ошибки\n<code>{{.Latency}}</code> - время ответа в секундах, пример <code>{{number .Latency 2}}</code>\n<code>{{.Duration}}</code> - сколько длилась проблема, в повторном уведомлении - сколько ссылка уже не работает, пример <code>{{duration .Duration}}</code>\n<code>{{.Links.Url}}</code>, <code>{{.Links.Statistic}}</code> - ссылки на сайт и статистику в апи",
+	"template.error_empty":           "шаблон пустой",
+	"template.error_syntax":          "ошибка в шаблоне: %s",
+	"template.error_no_text":         "шаблон не содержит текста",
+	"template.error_too_long":        "уведомление по шаблону слишком длинное: %d символов, максимум %d",
+	"template.error_html_char":       "символ %s нужно заменить на %s",
+	"template.error_html_tag":        "тег <%s> не поддерживается телеграмом",
+	"template.error_html_unexpected": "закрывающий тег </%s> без открывающего или не по порядку",
+	"template.error_html_unclosed":   "тег <%s> не закрыт",
+
+	// /template
+	"template.ask_kind":         "Выберите уведомление, шаблон которого необходимо изменить",
+	"template_preview.ask_kind": "Выберите уведомление для предпросмотра",
+	"template.error":            "Произошла ошибка при получении шаблона, повторите позже",
+	"template.error_save":       "Произошла ошибка при сохранении шаблона, повторите позже",
+	"template.error_kind":       "Неизвестное уведомление: %s, выберите его кнопкой",
+	"template.invalid":          "Ошибка в шаблоне: %s",
+	"template.invalid_retry":    "Ошибка в шаблоне: %s\n\nИсправьте шаблон и отправьте еще раз",
+	"template.saved":            "Шаблон сохранен",
+	"template.saved_preview":    "Шаблон сохранен, так будет выглядеть уведомление:\n\n%s",
+	"template.current":          "Текущий шаблон «%s»",
+	"template.current_default":  "Текущий шаблон «%s» (по умолчанию)",
+	"template.variables_title":  "Доступные переменные:\n",
+	"template.ask_body":         "\n\nОтправьте новый шаблон, можно использовать HTML разметку телеграма. 0 - вернуть шаблон по умолчанию",
+
+	// уведомления вне команд
+	"mute.released_url": "🔔 Время отключения истекло, уведомления по <code>%s</code> снова включены",
+	"mute.released_all": "🔔 Время отключения истекло, все уведомления снова включены",
+	"digest.title":      "🌙 За время тихих часов пришло %d уведомление\n\n|🌙 За время тихих часов пришло %d уведомления\n\n|🌙 За время тихих часов пришло %d уведомлений\n\n",
+	"digest.more":       "... и еще %d",
+
+	// http api
+	"api.forbidden":             "Доступ запрещен: %s",
+	"api.access_revoked":        "Доступ к сервису закрыт, обратитесь к администратору",
+	"api.url_not_found":         "Ссылка не найдена",
+	"api.url_exists":            "Ссылка уже существует",
+	"api.ping_list_error":       "Ошибка получения списка ссылок",
+	"api.ping_delete_error":     "Ошибка удаления ссылки",
+	"api.ping_delete_read_only": "Роль не позволяет удалять ссылки",
+	"api.ping_patch_error":      "Ошибка изменения ссылки",
+	"api.ping_patch_read_only":  "Роль не позволяет изменять ссылки",
+	"api.statistic_error":       "Ошибка получения статистики",
+	"api.incident_error":        "Ошибка получения инцидента",
+	"api.incident_not_found":    "Инцидент не найден",
+	"api.incident_list_error":   "Ошибка получения списка инцидентов",
+	"api.invalid_limit":         "limit должен быть числом от 1 до %d",
+	"api.ping_import_error":     "Ошибка импорта ссылок",
+	"api.ping_import_read_only": "Роль не позволяет импортировать ссылки",
+	"api.ping_export_error":     "Ошибка выгрузки ссылок",
+	"api.import_no_file":        "Не передан файл импорта, отправьте его в поле file или в теле запроса",
+	"api.import_too_large":      "Файл слишком большой, максимум %d КБ",
+	"api.chart_period":          "period должен быть одним из: 24h, 7d, 30d",
+	"api.chart_error":           "Ошибка построения графика",
+	"api.status_error":          "Ошибка получения состояния ссылок",
+	"api.ping_check_error":      "Ошибка проверки ссылки",
+	"api.invalid_tag":           "Не верный тег: %s",
+	"api.tag_required":          "Не указан тег",
+	"api.admin_forbidden":       "Доступ только для администраторов",
+	"api.admin_token_invalid":   "Не верный токен администратора в заголовке X-Admin-Token",
+	"api.admin_users_error":     "Ошибка получения списка пользователей",
+	"api.admin_user_not_found":  "Пользователь не найден",
+	"api.admin_block_error":     "Ошибка изменения блокировки пользователя",
+	"api.admin_broadcast_error": "Ошибка отправки объявления",
+	"api.admin_stats_error":     "Ошибка получения состояния приложения",
+
+	// изменение ссылки
+	"patch.error_invalid":          "не верные данные ссылки",
+	"patch.error_empty":            "не указано ни одного поля для изменения",
+	"patch.error_url":              "не верные данные ссылки: не валидная ссылка %q",
+	"patch.error_connection_time":  "не верные данные ссылки: не верное время ожидания ответа %q, примеры: 100ms|10s|1s500ms",
+	"patch.error_ping_time":        "не верные данные ссылки: не верная периодичность опроса %q, примеры: 30s|5m|1h",
+	"patch.error_min_ping_time":    "не верные данные ссылки: периодичность опроса должна быть не меньше %s",
+	"patch.error_repeat_alert":     "не верные данные ссылки: не верный интервал повтора уведомления %q, примеры: 0|15m|1h",
+	"patch.error_min_repeat_alert": "не верные данные ссылки: интервал повтора уведомления должен быть 0 или не меньше %s",
+	"patch.error_tags_count":       "не верные данные ссылки: у ссылки может быть не больше %d тегов",
+	"patch.error_tag":              "не верные данные ссылки: не верный тег %q, тег до %d символов из латиницы, цифр и _ . : / -",
+
+	// описание команд для /help и меню телеграма
+	"start.description":            "Регистрация и вступление в пространство",
+	"start.help":                   "Регистрирует вас в боте и создает личное пространство, после регистрации бот спросит часовой пояс. Если бот работает по приглашениям, для регистрации нужен код от администратора.\n\nПримеры:\n<code>/start</code> - регистрация\n<code>/start &lt;код&gt;</code> - регистрация по коду приглашения или вступление в пространство по приглашению, ссылку с кодом создает владелец в /workspace",
+	"add_url.description":          "Добавить ссылку для проверки",
+	"add_url.help":                 "Добавляет ссылку в текущее пространство. Бот по очереди спросит адрес, максимальное время ожидания ответа и периодичность опроса, ответы можно передать сразу в команде - бот спросит только недостающие.\n\nПримеры:\n<code>/add_url https://example.com 10s 1m</code>\n<code>/add_url https://example.com --timeout 5s --interval 5m --tag prod</code>\n\n<code>--timeout</code> - время ожидания, <code>--interval</code> - периодичность, минимально 30s, <code>--tag</code> - теги, можно указать несколько раз",
+	"remove_url.description":       "Удалить ссылку",
+	"remove_url.help":              "Удаляет ссылку вместе с ее инцидентами. Выберите ссылку кнопкой или отправьте ее адрес, удаление нужно подтвердить.\n\nПримеры: <code>/remove_url</code>, <code>/remove_url 42</code> - по id ссылки, <code>/remove_url https://example.com</code>",
+	"edit_url.description":         "Изменить адрес, время ожидания, периодичность, повтор уведомлений или теги",
+	"edit_url.help":                "Изменяет ссылку без потери истории: выберите ссылку, затем поле и отправьте новое значение. Ссылку, поле и значение можно передать сразу в команде.\n\nПримеры значений:\n<code>https://example.com/health</code> - адрес\n<code>5s</code> - время ожидания\n<code>5m</code> - периодичность\n<code>1h</code> - повтор уведомления пока ссылка не работает, <code>0</code> - только при смене статуса, <code>-</code> - по умолчанию\n<code>env:prod team:payments</code> - теги, <code>-</code> - убрать все теги\n\nПример: <code>/edit_url 42 ping_time 5m</code>, поля: url, connection_time, ping_time, repeat_alert, tags",
+	"list_url.description":         "Список ссылок",
+	"list_url.help":                "Выводит ссылки текущего пространства с временем ожидания, периодичностью опроса и тегами. Если указать тег, выводятся только ссылки с ним.\n\nПримеры:\n<code>/list_url</code>\n<code>/list_url env:prod</code>",
+	"critical_url.description":     "Пометить ссылку критичной",
+	"critical_url.help":            "Включает или снимает отметку критичности. Уведомления по критичным ссылкам приходят и в тихие часы.\n\nПримеры: <code>/critical_url</code>, затем выберите ссылку, или <code>/critical_url 42</code>",
+	"mute_all.description":         "Отключить все уведомления",
+	"mute_all.help":                "Отключает все уведомления на указанное время или бессрочно.\n\nПримеры:\n<code>/mute_all 30m</code>\n<code>/mute_all 2h</code>\n<code>/mute_all</code> - бессрочно",
+	"unmute_all.description":       "Включить все уведомления",
+	"unmute_all.help":              "Снова включает уведомления, отключенные командой /mute_all.\n\nПример: <code>/unmute_all</code>",
+	"mute_url.description":         "Отключить уведомления по ссылке",
+	"mute_url.help":                "Отключает уведомления по одной ссылке: выберите ссылку и укажите время.\n\nПримеры времени:\n<code>30m</code>\n<code>1h30m</code>\n<code>0</code> - бессрочно\n\nОдной командой: <code>/mute_url 42 1h</code>",
+	"unmute_url.description":       "Включить уведомления по ссылке",
+	"unmute_url.help":              "Включает уведомления по ссылке, выберите ее из списка отключенных.\n\nПримеры: <code>/unmute_url</code>, <code>/unmute_url 42</code>",
+	"mute_list.description":        "Отключенные уведомления",
+	"mute_list.help":               "Показывает отключенные уведомления и сколько осталось до их включения.\n\nПример: <code>/mute_list</code>",
+	"statistic.description":        "Текущая статистика по ссылкам",
+	"statistic.help":               "Выводит статистику опросов по всем ссылкам пространства за текущий период. Если указать тег, сначала выводится общая статистика по всем ссылкам с ним, затем по каждой из них.\n\nПримеры:\n<code>/statistic</code>\n<code>/statistic team:payments</code>",
+	"statistic_all.description":    "Статистика за все время",
+	"statistic_all.help":           "Выводит статистику опросов по всем ссылкам пространства за все время вместе с инцидентами, MTTR и MTBF.\n\nПример: <code>/statistic_all</code>",
+	"statistic_url.description":    "Статистика по одной ссылке",
+	"statistic_url.help":           "Выводит статистику и список ошибок по выбранной ссылке.\n\nПримеры: <code>/statistic_url</code>, затем <code>https://example.com</code>, или <code>/statistic_url 42</code>",
+	"incidents.description":        "Последние инциденты",
+	"incidents.help":               "Выводит последние инциденты пространства, кнопка под инцидентом показывает его хронологию.\n\nПример: <code>/incidents</code>",
+	"template.description":         "Изменить шаблон уведомления",
+	"template.help":                "Изменяет шаблон уведомления о недоступности, восстановлении или деградации. Поддерживается HTML разметка телеграма и переменные, список которых бот покажет после выбора шаблона.\n\nПример шаблона:\n<code>⚠️ {{.Url}} - {{.Error}}</code>\n\n<code>0</code> - вернуть шаблон по умолчанию",
+	"template_preview.description": "Предпросмотр уведомления",
+	"template_preview.help":        "Показывает как будет выглядеть уведомление по текущему шаблону на примере.\n\nПример: <code>/template_preview</code>, затем выберите уведомление",
+	"settings.description":         "Часовой пояс, тихие часы и язык",
+	"settings.help":                "Показывает и изменяет настройки: часовой пояс, тихие часы и язык бота.\n\nНастройку можно изменить сразу: <code>/settings timezone +3</code>\n\nПримеры тихих часов:\n<code>пн-пт 23:00-07:00</code>\n<code>все 22:00-08:00</code>",
+	"api_key_refresh.description":  "Новый ключ доступа к API",
+	"api_key_refresh.help":         "Создает новый ключ доступа к API для текущего пространства, старый ключ перестает работать.\n\nПример: <code>/api_key_refresh</code>",
+	"workspace.description":        "Пространства и участники",
+	"workspace.help":               "Показывает текущее пространство, участников и подключенные чаты. Кнопками можно переключить или создать пространство, пригласить и исключить участников.\n\nПример: <code>/workspace</code>",
+	"connect_chat.description":     "Подключить чат для уведомлений",
+	"connect_chat.help":            "Подключает групповой чат или канал, уведомления пространства будут приходить в него. Бот должен быть добавлен в чат.\n\nПримеры:\n<code>/connect_chat</code> - в групповом чате\n<code>/connect_chat @channel</code> - в личных сообщениях",
+	"import.description":           "Импорт ссылок из файла",
+	"import.help":                  "Добавляет ссылки из файла CSV, YAML или JSON. После команды отправьте файл документом, каждая запись проверяется отдельно.\n\nРежимы:\n<code>/import</code> - добавить новые ссылки, существующие пропустить\n<code>/import upsert</code> - также обновить настройки существующих\n<code>/import dry_run</code> - только проверить файл и показать отчет\n\nПример CSV:\n<code>url,connection_time,ping_time,critical,tags\nhttps://example.com,10s,1m,false,env:prod team:payments</code>\n\nПример YAML:\n<code>- url: https://example.com\n  connection_time: 10s\n  ping_time: 1m\n  tags: [env:prod]</code>\n\nПример JSON:\n<code>[{\"url\": \"https://example.com\", \"connection_time\": \"10s\", \"ping_time\": \"1m\"}]</code>",
+	"export.description":           "Выгрузить ссылки в файл",
+	"export.help":                  "Выгружает ссылки текущего пространства файлом, который можно загрузить обратно командой /import.\n\nПримеры:\n<code>/export</code> - выбрать формат кнопкой\n<code>/export csv</code>\n<code>/export yaml</code>\n<code>/export json</code>",
+	"chart.description":            "График времени ответа ссылки",
+	"chart.help":                   "Строит график медианы и 95-го перцентиля времени ответа ссылки и кол-ва неудачных опросов. Промежуток: 24h (по умолчанию), 7d или 30d.\n\nПримеры:\n<code>/chart</code> - выбрать ссылку кнопкой\n<code>/chart https://example.com</code>\n<code>/chart https://example.com 7d</code>",
+	"status.description":           "Текущее состояние всех ссылок",
+	"status.help":                  "Показывает что сейчас работает, а что нет: статус каждой ссылки, сколько он длится, время ответа и ошибку последнего опроса. Недоступные и работающие с проблемами ссылки выводятся первыми.\n\n🔴 недоступна\n🟡 работает с проблемами\n⚪ еще не опрашивалась\n⏸ уведомления отключены\n🟢 работает\n\nЕсли указать тег, выводятся только ссылки с ним: <code>/status env:prod</code>",
+	"check_now.description":        "Проверить ссылку прямо сейчас",
+	"check_now.help":               "Опрашивает ссылку сразу, не дожидаясь следующей проверки по расписанию, и показывает статус, время этапов запроса, основные заголовки ответа и ошибку. Результат сохраняется в статистику и обновляет состояние ссылки.\n\nПримеры:\n<code>/check_now</code> - выбрать ссылку кнопкой\n<code>/check_now https://example.com</code>",
+	"cancel.description":           "Отменить начатое действие",
+	"cancel.help":                  "Прерывает начатый диалог любой команды, например добавление ссылки, и сообщает что было отменено. Диалог без ответа отменяется и сам, бот пришлет об этом сообщение.\n\nПример: <code>/cancel</code>",
+	"admin.description":            "Управление ботом для администраторов",
+	"admin.help":                   "Команды администратора:\n<code>/admin users</code> - пользователи и кол-во их ссылок\n<code>/admin block 123</code> - закрыть пользователю доступ к боту и апи\n<code>/admin unblock 123</code> - вернуть доступ\n<code>/admin access</code> - режим доступа к боту, <code>/admin access open|invite|allowlist</code> - изменить его\n<code>/admin allow 123</code> - добавить пользователя телеграма в список доступа\n<code>/admin disallow 123</code> - убрать из списка доступа\n<code>/admin invite [кол-во] [срок]</code> - код приглашения, например <code>/admin invite 5 72h</code>\n<code>/admin invites</code> - действующие коды приглашений\n<code>/admin revoke код</code> - отозвать код\n<code>/admin broadcast текст</code> - отправить объявление всем пользователям\n<code>/admin stats</code> - состояние приложения",
+	"help.description":             "Список команд и справка",
+	"help.help":                    "Выводит список команд, а с названием команды - подробную справку по ней.\n\nПримеры:\n<code>/help</code>\n<code>/help add_url</code>",
+
+	// /help
+	"help.title":   "📖 Команды\n\n",
+	"help.item":    "/%s - %s\n",
+	"help.footer":  "\nПодробнее о команде: <code>/help add_url</code>",
+	"help.command": "<b>/%s</b> - %s\n\n%s",
+	"help.flags":   "\n\nИменованные аргументы: %s, недостающие значения бот спросит в диалоге",
+	"help.unknown": "Команда <code>%s</code> не найдена, список команд: /help",
+
+	// импорт и экспорт ссылок
+	"import.usage":              "Неизвестный режим, примеры: <code>/import</code> | <code>/import upsert</code> | <code>/import dry_run</code>",
+	"import.ask_file":           "Отправьте файл CSV, YAML или JSON документом, не больше %d ссылок. Формат файла описан в <code>/help import</code>",
+	"import.unknown_format":     "Не удалось определить формат файла, расширение должно быть .csv, .yaml, .yml или .json",
+	"import.too_large":          "Файл слишком большой, максимум %d КБ",
+	"import.error_no_file":      "Отправьте файл документом или отмените импорт кнопкой под вопросом",
+	"import.error_download":     "Не удалось скачать файл, повторите импорт позже",
+	"import.invalid_file":       "Не удалось разобрать файл: %s\n\nИсправьте файл и отправьте еще раз",
+	"import.error":              "Произошла ошибка при импорте, повторите позже",
+	"import.report_title":       "📥 Импорт завершен\n\n",
+	"import.report_dry_run":     "🧪 Проверка файла, изменения не сохранены\n\n",
+	"import.report_summary":     "➕ Добавлено - <code>%d</code>\n✏️ Обновлено - <code>%d</code>\n➖ Без изменений - <code>%d</code>\n⏭ Пропущено - <code>%d</code>\n⛔️ С ошибками - <code>%d</code>\n\n",
+	"import.report_row":         "Строка %d <code>%s</code>: %s\n",
+	"import.report_more":        "... и еще %d\n",
+	"import.report_apply":       "\nЧтобы применить изменения, выполните /%s без dry_run и отправьте файл еще раз",
+	"import.error_url_required": "не указан url",
+	"import.error_required":     "для новой ссылки нужно указать connection_time и ping_time",
+	"import.error_duplicate":    "ссылка уже встречалась в строке %d",
+	"import.error_exists":       "ссылка уже существует с другими настройками, для обновления используйте режим upsert",
+	"import.error_save":         "не удалось сохранить ссылку",
+	"transfer.error_format":     "неизвестный формат файла, поддерживаются csv, yaml и json",
+	"transfer.error_empty":      "в файле нет ни одной ссылки",
+	"transfer.error_too_many":   "в файле больше %d ссылок",
+	"transfer.error_csv":        "ошибка разбора csv: %s",
+	"transfer.error_csv_header": "в заголовке csv нет колонки url",
+	"transfer.error_yaml":       "ошибка разбора yaml, файл должен содержать список ссылок: %s",
+	"transfer.error_json":       "ошибка разбора json, файл должен содержать массив ссылок: %s",
+	"transfer.error_row":        "не верная запись: %s",
+	"transfer.error_critical":   "не верное значение critical %q, примеры: true|false",
+	"export.ask_format":         "Выберите формат файла",
+	"export.error":              "Произошла ошибка при выгрузке ссылок, повторите позже",
+	"export.success":            "📦 Выгружена %d ссылка|📦 Выгружено %d ссылки|📦 Выгружено %d ссылок",
+
+	// график времени ответа
+	"chart.ask_url": "Выберите ссылку для построения графика",
+	"chart.usage":   "Используйте: /chart <ссылка> [24h|7d|30d]",
+	"chart.no_data": "По ссылке %s нет опросов за %s",
+	"chart.error":   "Произошла ошибка при построении графика, повторите позже",
+	"chart.caption": "📈 %s за %s\nОпросов: %d, неудачных: %d, доступность: %s%%",
+
+	// текущее состояние ссылок
+	"status.title":         "📋 Состояние ссылок: %d\n",
+	"status.title_tag":     "📋 Состояние ссылок с тегом <code>%s</code>: %d\n",
+	"status.refresh":       "🔄 Обновить",
+	"status.since":         "%s уже %s",
+	"status.latency":       ", ответ %s с",
+	"status.code":          ", код %d",
+	"status.name_up":       "работает",
+	"status.name_down":     "недоступна",
+	"status.name_degraded": "работает с проблемами",
+	"status.name_pending":  "еще не опрашивалась",
+	"status.name_paused":   "уведомления отключены",
+
+	// внеплановая проверка ссылки
+	"check_now.ask_url":           "Выберите ссылку для проверки",
+	"check_now.again":             "🔁 Проверить еще раз",
+	"check_now.title":             "🔎 Проверка %s\n",
+	"check_now.latency":           "\nВремя ответа: %s с",
+	"check_now.timing_dns":        "DNS %s с",
+	"check_now.timing_connect":    "подключение %s с",
+	"check_now.timing_tls":        "TLS %s с",
+	"check_now.timing_first_byte": "первый байт %s с",
+	"check_now.headers":           "\n\nЗаголовки:",
+	"check_now.error":             "\n\nОшибка: <i>%s</i>",
+
+	// /cancel
+	"cancel.done":    "Отменено:\n",
+	"cancel.item":    "/%s - %s",
+	"cancel.nothing": "Нет начатых действий, отменять нечего",
+
+	// теги ссылок
+	"tag.list":          "🏷 Теги: %s\n",
+	"tag.empty_list":    "Нет ссылок с тегом <code>%s</code>",
+	"tag.error_invalid": "Не верный тег %q, тег до %d символов из латиницы, цифр и _ . : / -",
+
+	// /admin
+	"admin.users_title":            "👥 Пользователи: %d\n",
+	"admin.user_item":              "\n<code>%d</code> %s - %s%s",
+	"admin.monitors":               "%d ссылка|%d ссылки|%d ссылок",
+	"admin.mark_admin":             " 👑",
+	"admin.mark_blocked":           " ⛔ заблокирован",
+	"admin.error_user_id":          "Укажите id пользователя, пример: <code>/admin block 123</code>",
+	"admin.user_not_found":         "Пользователь <code>%d</code> не найден",
+	"admin.error_block_admin":      "Пользователь %d администратор, его нельзя заблокировать",
+	"admin.blocked":                "Пользователь <code>%d</code> заблокирован",
+	"admin.unblocked":              "Пользователь <code>%d</code> разблокирован",
+	"admin.broadcast_message":      "📢 <b>Объявление</b>\n\n%s",
+	"admin.broadcast_success":      "Объявление поставлено в очередь %d пользователю|Объявление поставлено в очередь %d пользователям|Объявление поставлено в очередь %d пользователям",
+	"admin.error_broadcast_empty":  "Укажите текст объявления: /admin broadcast текст",
+	"admin.error_broadcast_length": "Объявление слишком длинное: %d символов, максимум %d",
+	"admin.stats":                  "📊 Состояние приложения\n\nСсылок опрашивается по расписанию: <code>%d</code>\nРезультатов ждут записи в статистику: <code>%d</code>\nОшибок записи в статистику: <code>%d</code>\nЧатов в очереди сообщений: <code>%d</code>\nЗадержка очереди: <code>%s</code>\nГорутин: <code>%d</code>\nРаботает: <code>%s</code>",
+	"admin.stats_commands":         "\n\nКоманды с момента запуска:",
+	"admin.stats_command":          "\n/%s - выполнений <code>%d</code>, ошибок <code>%d</code>, в среднем <code>%s с</code>, максимум <code>%s с</code>",
+
+	// доступ к боту
+	"access.invite_required":   "Бот работает только по приглашениям. Для регистрации нужен код от администратора: <code>/start код</code>",
+	"access.invite_invalid":    "Код приглашения не найден, истек или уже использован, попросите администратора создать новый",
+	"access.error_mode":        "Неизвестный режим доступа %q, допустимые: open, invite, allowlist",
+	"access.mode":              "🔐 Режим доступа: %s\nВ списке доступа: <code>%d</code>",
+	"access.mode_open":         "<b>open</b> - бот доступен всем",
+	"access.mode_invite":       "<b>invite</b> - регистрация только по коду приглашения",
+	"access.mode_allowlist":    "<b>allowlist</b> - только пользователи из списка доступа",
+	"access.allowed":           "Пользователь <code>%d</code> добавлен в список доступа",
+	"access.disallowed":        "Пользователь <code>%d</code> убран из списка доступа",
+	"access.not_allowed":       "Пользователя <code>%d</code> нет в списке доступа",
+	"access.error_invite_args": "Укажите кол-во использований от 1 до %d и срок действия до %s, пример: <code>/admin invite 5 72h</code>",
+	"access.code_created":      "Код приглашения <code>%s</code> на %d использований действует до %s, перешлите ссылку:\n\nhttps://t.me/%s?start=%s",
+	"access.codes_title":       "🎟 Действующие коды приглашений: %d\n",
+	"access.code_item":         "\n<code>%s</code> - использован %d из %d, до %s",
+	"access.error_code":        "Укажите код приглашения, пример: <code>/admin revoke код</code>",
+	"access.code_not_found":    "Код приглашения <code>%s</code> не найден",
+	"access.code_revoked":      "Код приглашения <code>%s</code> отозван",
+
+	// аргументы команд вместо диалога
+	"arguments.error_value":   "Для аргумента --%s не указано значение",
+	"arguments.error_quote":   "Не закрыта кавычка в аргументах команды",
+	"arguments.error_unknown": "Неизвестный аргумент --%s у команды /%s, доступные: %s",
+	"arguments.error_extra":   "Лишние аргументы команды: %s",
+}
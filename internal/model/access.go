@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	AccessOpen      AccessMode = "open"      // бот доступен всем пользователям телеграма
+	AccessInvite    AccessMode = "invite"    // регистрация только по коду приглашения, зарегистрированным доступ остается
+	AccessAllowlist AccessMode = "allowlist" // бот доступен только пользователям из списка доступа
+)
+
+type (
+	AccessMode string
+
+	// AccessCode код приглашения для регистрации в боте, используется по ссылке t.me/<bot>?start=<code>
+	AccessCode struct {
+		Code      string    `json:"code"`
+		MaxUses   int       `json:"max_uses"`
+		Uses      int       `json:"uses"`
+		CreatedBy int64     `json:"created_by"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+)
+
+// IsValid известен ли режим доступа
+func (m AccessMode) IsValid() bool {
+	return m == AccessOpen || m == AccessInvite || m == AccessAllowlist
+}
@@ -0,0 +1,45 @@
+package model
+
+type (
+	// AdminUser пользователь бота для администратора
+	AdminUser struct {
+		Id       int64  `json:"id"`
+		Login    string `json:"login"`
+		IsAdmin  bool   `json:"is_admin"`
+		Blocked  bool   `json:"blocked"`
+		Monitors int    `json:"monitors"` // кол-во ссылок добавленных пользователем во всех пространствах
+	}
+
+	AdminUserList []AdminUser
+
+	// SchedulerStats состояние опроса ссылок по расписанию
+	SchedulerStats struct {
+		Monitors      int   `json:"monitors"`       // сколько ссылок опрашивается по расписанию
+		PendingRows   int   `json:"pending_rows"`   // результаты опросов ожидающие записи в статистику
+		FailedInserts int64 `json:"failed_inserts"` // неудачных записей в статистику с момента запуска
+	}
+
+	// QueueStats состояние очереди исходящих сообщений
+	QueueStats struct {
+		Chats int64   `json:"chats"` // чаты, в которые есть неотправленные сообщения
+		Lag   float64 `json:"lag"`   // сколько секунд ждет отправки самое старое готовое сообщение, 0 - задержки нет
+	}
+
+	// CommandStats выполнения команды бота с момента запуска
+	CommandStats struct {
+		Command string  `json:"command"`
+		Runs    int64   `json:"runs"`
+		Errors  int64   `json:"errors"`
+		Average float64 `json:"average"` // среднее время выполнения в секундах
+		Max     float64 `json:"max"`
+	}
+
+	// RuntimeStats состояние приложения для администратора
+	RuntimeStats struct {
+		Scheduler  SchedulerStats `json:"scheduler"`
+		Queue      QueueStats     `json:"queue"`
+		Commands   []CommandStats `json:"commands"`
+		Goroutines int            `json:"goroutines"`
+		Uptime     float64        `json:"uptime"` // секунд с момента запуска
+	}
+)
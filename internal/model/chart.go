@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type (
+	// ChartPoint результат опросов ссылки за один интервал графика, время ответа в секундах
+	ChartPoint struct {
+		Time     time.Time // начало интервала
+		P50      float64   // медиана времени ответа успешных опросов
+		P95      float64   // 95-й перцентиль времени ответа успешных опросов
+		Failures int64     // кол-во неудачных опросов
+		Total    int64     // кол-во всех опросов
+	}
+
+	// ChartPointList точки графика по возрастанию времени, интервалы без опросов отсутствуют
+	ChartPointList []ChartPoint
+)
+
+// HasLatency есть ли в интервале успешные опросы, без них перцентили не имеют смысла
+func (p ChartPoint) HasLatency() bool {
+	return p.Total > p.Failures
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// ProbeHeaders заголовки ответа, которые сохраняются в результате опроса для краткой сводки
+var ProbeHeaders = []string{"Content-Type", "Content-Length", "Server", "Location", "Cache-Control"}
+
+type (
+	// ProbeTimings время этапов запроса в секундах, 0 - этап не выполнялся (например соединение было переиспользовано)
+	ProbeTimings struct {
+		Dns       float64 `json:"dns"`
+		Connect   float64 `json:"connect"`
+		Tls       float64 `json:"tls"`
+		FirstByte float64 `json:"first_byte"` // от начала запроса до первого байта ответа
+	}
+
+	// CheckResult результат опроса ссылки вне расписания
+	CheckResult struct {
+		PingId     int64             `json:"ping_id"`
+		Url        string            `json:"url"`
+		Status     MonitorStatus     `json:"status"`
+		StatusCode int               `json:"status_code"`
+		Latency    float64           `json:"latency"`
+		Timings    ProbeTimings      `json:"timings"`
+		Headers    map[string]string `json:"headers,omitempty"`
+		Error      string            `json:"error,omitempty"`
+		CheckedAt  time.Time         `json:"checked_at"`
+	}
+)
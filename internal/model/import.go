@@ -0,0 +1,56 @@
+package model
+
+const (
+	ImportCreated   ImportStatus = "created"   // ссылка добавлена
+	ImportUpdated   ImportStatus = "updated"   // настройки существующей ссылки изменены, только в режиме upsert
+	ImportUnchanged ImportStatus = "unchanged" // ссылка уже есть с такими же настройками
+	ImportSkipped   ImportStatus = "skipped"   // ссылка уже есть, а режим upsert не включен
+	ImportInvalid   ImportStatus = "invalid"   // запись с ошибкой, не импортируется
+)
+
+type (
+	ImportStatus string
+
+	// ImportOptions режим импорта ссылок
+	ImportOptions struct {
+		DryRun bool // только проверить файл и вернуть отчет, ничего не сохраняя
+		Upsert bool // обновлять настройки ссылок, которые уже есть в пространстве
+	}
+
+	// ImportRow результат импорта одной записи файла
+	ImportRow struct {
+		Line   int          `json:"line"`
+		Url    string       `json:"url"`
+		Status ImportStatus `json:"status"`
+		Error  string       `json:"error,omitempty"`
+	}
+
+	// ImportReport отчет об импорте, при DryRun показывает что было бы сделано
+	ImportReport struct {
+		DryRun    bool        `json:"dry_run"`
+		Created   int         `json:"created"`
+		Updated   int         `json:"updated"`
+		Unchanged int         `json:"unchanged"`
+		Skipped   int         `json:"skipped"`
+		Invalid   int         `json:"invalid"`
+		Rows      []ImportRow `json:"rows"`
+	}
+)
+
+// Add добавляет результат записи в отчет и пересчитывает итоги
+func (r *ImportReport) Add(row ImportRow) {
+	switch row.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	case ImportUnchanged:
+		r.Unchanged++
+	case ImportSkipped:
+		r.Skipped++
+	case ImportInvalid:
+		r.Invalid++
+	}
+
+	r.Rows = append(r.Rows, row)
+}
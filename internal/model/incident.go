@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+const (
+	IncidentEventDown        IncidentEventKind = "down"        // ссылка перестала отвечать
+	IncidentEventDegraded    IncidentEventKind = "degraded"    // ссылка начала работать с проблемами
+	IncidentEventRecovery    IncidentEventKind = "recovery"    // ссылка снова работает
+	IncidentEventAcknowledge IncidentEventKind = "acknowledge" // уведомление приняли в работу
+)
+
+type (
+	IncidentEventKind string
+
+	// Incident период, в течение которого ссылка не работала или работала с проблемами
+	Incident struct {
+		Id         int64           `json:"id"`
+		PingId     int64           `json:"ping_id"`
+		Url        string          `json:"url"`
+		StartedAt  time.Time       `json:"started_at"`
+		EndedAt    *time.Time      `json:"ended_at,omitempty"` // nil - инцидент еще не закрыт
+		Duration   float64         `json:"duration"`           // длительность в секундах, для открытого инцидента - на текущий момент
+		FirstError string          `json:"first_error"`
+		Events     []IncidentEvent `json:"events,omitempty"`
+	}
+
+	// IncidentEvent запись в хронологии инцидента
+	IncidentEvent struct {
+		Kind      IncidentEventKind `json:"kind"`
+		Text      string            `json:"text"`
+		CreatedAt time.Time         `json:"created_at"`
+	}
+
+	IncidentList []Incident // see Incident
+
+	// IncidentStatistic статистика инцидентов по ссылке, время в секундах
+	IncidentStatistic struct {
+		Count int     `json:"count"`
+		Mttr  float64 `json:"mttr"` // среднее время восстановления
+		Mtbf  float64 `json:"mtbf"` // среднее время работы между инцидентами
+	}
+)
+
+// IsOpen инцидент еще не закрыт
+func (i Incident) IsOpen() bool {
+	return i.EndedAt == nil
+}
@@ -1,21 +1,35 @@
 package model
 
+import "time"
+
 type (
 	// Ping моделька для представления записи в тиблице ping
 	Ping struct {
-		Id             int64  `json:"id"`
-		UserId         int64  `json:"-"`
-		Url            string `json:"url"`
-		ConnectionTime string `json:"connection_time"`
-		PingTime       string `json:"ping_time"`
-		User           User   `json:"-"`
+		Id             int64      `json:"id"`
+		UserId         int64      `json:"-"` // кто добавил ссылку
+		WorkspaceId    int64      `json:"workspace_id"`
+		Url            string     `json:"url"`
+		ConnectionTime string     `json:"connection_time"`
+		PingTime       string     `json:"ping_time"`
+		Mute           bool       `json:"mute"`
+		MuteUntil      *time.Time `json:"mute_until,omitempty"` // nil - уведомления отключены бессрочно
+		MutedBy        int64      `json:"-"`                    // кто отключил уведомления, 0 - неизвестно
+		MuteChatId     int64      `json:"-"`                    // чат в котором отключили уведомления, туда сообщается об окончании отключения
+		Critical       bool       `json:"critical"`             // уведомления по критичным ссылкам приходят и в тихие часы
+		Tags           []string   `json:"tags"`                 // теги для группировки ссылок, например env:prod, см. NormalizeTags
+		RepeatAlert    string     `json:"repeat_alert"`         // как часто повторять уведомление пока ссылка не работает, см. RepeatInterval
+		User           User       `json:"-"`
 	}
 
 	// User моделька для представления записи в тиблице users
 	User struct {
-		Id    int64
-		Login string
-		Mute  bool
+		Id        int64
+		Login     string
+		Mute      bool
+		MuteUntil *time.Time // nil - уведомления отключены бессрочно
+		Timezone  string
+		Language  string // язык выбранный в настройках, пустая строка - язык телеграма
+		Workspace Member // для апи - пространство к которому дает доступ ключ, для бота - текущее пространство
 	}
 
 	// PingList моделька для представления списка записей из таблици ping
@@ -30,20 +44,24 @@ type (
 		RealConnectionTime float64
 		StatusCode         int
 		IsCancel           bool
+		IsManual           bool              // опрос запущен вручную, а не по расписанию
+		Timings            ProbeTimings      // время этапов запроса
+		Headers            map[string]string // основные заголовки ответа, см. ProbeHeaders
 	}
 
 	PingResultList []PingResult // see PingResult
 
 	// Statistic данные по пингам
 	Statistic struct {
-		Url               string         `json:"url"`
-		CountPing         int            `json:"count_ping"`
-		CorrectCount      int            `json:"correct_count"`
-		CancelCount       int            `json:"cancel_count"`
-		MaxConnectionTime float64        `json:"max_connection_time"`
-		MinConnectionTime float64        `json:"min_connection_time"`
-		AvgConnectionTime float64        `json:"avg_connection_time"`
-		Errors            []ErrorMessage `json:"errors,omitempty"`
+		Url               string            `json:"url"`
+		CountPing         int               `json:"count_ping"`
+		CorrectCount      int               `json:"correct_count"`
+		CancelCount       int               `json:"cancel_count"`
+		MaxConnectionTime float64           `json:"max_connection_time"`
+		MinConnectionTime float64           `json:"min_connection_time"`
+		AvgConnectionTime float64           `json:"avg_connection_time"`
+		Errors            []ErrorMessage    `json:"errors,omitempty"`
+		Incidents         IncidentStatistic `json:"incidents"`
 	}
 
 	ErrorMessage struct {
@@ -52,4 +70,11 @@ type (
 	}
 
 	StatisticResultList []Statistic
+
+	// GroupStatistic общая статистика по всем ссылкам с тегом, в Statistic не заполняется Url
+	GroupStatistic struct {
+		Tag       string    `json:"tag"`
+		Urls      []string  `json:"urls"`
+		Statistic Statistic `json:"statistic"`
+	}
 )
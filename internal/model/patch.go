@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"net/url"
+	"time"
+)
+
+// MinPingTime минимальная периодичность опроса ссылки
+const MinPingTime = time.Second * 30
+
+var (
+	ErrInvalidPing = i18n.NewError("patch.error_invalid")
+	ErrEmptyPatch  = i18n.NewError("patch.error_empty")
+)
+
+type (
+	// PingPatch изменяемые поля ссылки, nil - поле не меняется
+	PingPatch struct {
+		Url            *string   `json:"url,omitempty"`
+		ConnectionTime *string   `json:"connection_time,omitempty"`
+		PingTime       *string   `json:"ping_time,omitempty"`
+		Tags           *[]string `json:"tags,omitempty"`         // новый список тегов целиком, пустой список убирает все теги
+		RepeatAlert    *string   `json:"repeat_alert,omitempty"` // интервал повтора уведомления, пустая строка - по умолчанию, 0 - не повторять
+	}
+)
+
+// Validate проверяет переданные поля, ошибки оборачивают ErrInvalidPing
+func (p PingPatch) Validate() error {
+	if p.Url == nil && p.ConnectionTime == nil && p.PingTime == nil && p.Tags == nil && p.RepeatAlert == nil {
+		return ErrEmptyPatch
+	}
+
+	if p.Url != nil {
+		u, err := url.Parse(*p.Url)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_url", *p.Url)
+		}
+	}
+
+	if p.ConnectionTime != nil {
+		timeout, err := time.ParseDuration(*p.ConnectionTime)
+		if err != nil || timeout <= 0 {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_connection_time", *p.ConnectionTime)
+		}
+	}
+
+	if p.PingTime != nil {
+		interval, err := time.ParseDuration(*p.PingTime)
+		if err != nil {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_ping_time", *p.PingTime)
+		}
+
+		if interval < MinPingTime {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_min_ping_time", MinPingTime)
+		}
+	}
+
+	if p.RepeatAlert != nil && *p.RepeatAlert != "" {
+		interval, err := time.ParseDuration(*p.RepeatAlert)
+		if err != nil || interval < 0 {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_repeat_alert", *p.RepeatAlert)
+		}
+
+		if interval > 0 && interval < MinRepeatAlert {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_min_repeat_alert", MinRepeatAlert)
+		}
+	}
+
+	if p.Tags != nil {
+		tags := NormalizeTags(*p.Tags)
+		if len(tags) > MaxTags {
+			return i18n.WrapError(ErrInvalidPing, "patch.error_tags_count", MaxTags)
+		}
+
+		for _, tag := range tags {
+			if err := ValidateTag(tag); err != nil {
+				return i18n.WrapError(ErrInvalidPing, "patch.error_tag", tag, MaxTagLength)
+			}
+		}
+	}
+
+	return nil
+}
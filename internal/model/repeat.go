@@ -0,0 +1,34 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// MinRepeatAlert минимальный интервал повторного уведомления о недоступности ссылки
+const MinRepeatAlert = time.Minute
+
+// ParseRepeatAlert значение повтора уведомления из текста, "-" - интервал по умолчанию из конфига
+func ParseRepeatAlert(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "-" {
+		return ""
+	}
+
+	return text
+}
+
+// RepeatInterval как часто повторять уведомление пока ссылка не работает или работает с проблемами:
+// пустое значение - интервал по умолчанию из конфига, 0 - уведомление только при смене статуса
+func (p Ping) RepeatInterval(def time.Duration) time.Duration {
+	if p.RepeatAlert == "" {
+		return def
+	}
+
+	interval, err := time.ParseDuration(p.RepeatAlert)
+	if err != nil {
+		return def
+	}
+
+	return interval
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const DefaultTimezone = "UTC"
+
+type (
+	// QuietHours тихие часы на день недели, время в минутах от начала суток в часовом поясе пользователя,
+	// если Start больше End интервал переходит через полночь и заканчивается на следующий день
+	QuietHours struct {
+		Weekday time.Weekday
+		Start   int
+		End     int
+	}
+
+	QuietHoursList []QuietHours // see QuietHours
+
+	// NotificationSettings настройки доставки уведомлений пользователю
+	NotificationSettings struct {
+		Timezone   string
+		Language   string // язык выбранный в настройках, пустая строка - язык телеграма
+		QuietHours QuietHoursList
+	}
+)
+
+// Location часовой пояс пользователя, если он не задан или не найден - UTC
+func (s NotificationSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil || s.Timezone == "" {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// IsQuiet проверяет попадает ли момент времени в тихие часы пользователя
+func (s NotificationSettings) IsQuiet(t time.Time) bool {
+	local := t.In(s.Location())
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := (local.Weekday() + 6) % 7
+
+	for _, q := range s.QuietHours {
+		switch {
+		case q.Weekday == local.Weekday() && q.Start <= q.End:
+			if minute >= q.Start && minute < q.End {
+				return true
+			}
+		case q.Weekday == local.Weekday():
+			if minute >= q.Start {
+				return true
+			}
+		case q.Weekday == yesterday && q.Start > q.End:
+			if minute < q.End {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (q QuietHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
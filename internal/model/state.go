@@ -0,0 +1,85 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	StatusUp       MonitorStatus = "up"       // ссылка отвечает
+	StatusDown     MonitorStatus = "down"     // ссылка не отвечает
+	StatusDegraded MonitorStatus = "degraded" // ссылка отвечает, но с ошибкой или слишком медленно
+	StatusPaused   MonitorStatus = "paused"   // уведомления по ссылке отключены, опрос продолжается
+	StatusPending  MonitorStatus = "pending"  // ссылку еще не опрашивали
+)
+
+const (
+	AlertDown     AlertKind = "down"     // уведомление о недоступности
+	AlertRecovery AlertKind = "recovery" // уведомление о восстановлении
+	AlertDegraded AlertKind = "degraded" // уведомление о проблемах в работе
+)
+
+// ChannelTelegram уведомления в личные сообщения телеграма
+const ChannelTelegram = "telegram"
+
+type (
+	MonitorStatus string
+	AlertKind     string
+
+	// MonitorState текущее состояние ссылки по результатам последнего опроса
+	MonitorState struct {
+		PingId         int64         `json:"ping_id"`
+		Status         MonitorStatus `json:"status"`
+		Since          time.Time     `json:"since"` // время последней смены статуса
+		LastCheck      time.Time     `json:"last_check"`
+		LastLatency    float64       `json:"last_latency"`
+		LastStatusCode int           `json:"last_status_code"`
+		LastError      string        `json:"last_error,omitempty"`
+		LastAlert      time.Time     `json:"last_alert"` // время последнего уведомления о текущем статусе
+	}
+
+	// MonitorOverview ссылка и ее текущее состояние для обзора /status, у отключенной ссылки статус paused,
+	// а результат последнего опроса сохраняется
+	MonitorOverview struct {
+		MonitorState
+		Url      string   `json:"url"`
+		Critical bool     `json:"critical"`
+		Tags     []string `json:"tags"`
+	}
+
+	// MonitorOverviewList ссылки пространства, проблемные в начале списка
+	MonitorOverviewList []MonitorOverview
+)
+
+// IsProblem ссылка сейчас не работает или работает с проблемами
+func (s MonitorStatus) IsProblem() bool {
+	return s == StatusDown || s == StatusDegraded
+}
+
+// WithTag ссылки с указанным тегом, пустой тег - все ссылки
+func (l MonitorOverviewList) WithTag(tag string) MonitorOverviewList {
+	if tag == "" {
+		return l
+	}
+
+	list := make(MonitorOverviewList, 0, len(l))
+	for _, item := range l {
+		if slices.Contains(item.Tags, tag) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// Count кол-во ссылок с указанным статусом
+func (l MonitorOverviewList) Count(status MonitorStatus) int {
+	var count int
+	for _, item := range l {
+		if item.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
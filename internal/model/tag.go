@@ -0,0 +1,120 @@
+package model
+
+import (
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// MaxTags сколько тегов можно указать у одной ссылки
+	MaxTags = 10
+	// MaxTagLength ограничение длины тега, тег передается в данных кнопок телеграма, а они не длиннее 64 байт
+	MaxTagLength = 32
+)
+
+// tagPattern тег в нижнем регистре из латиницы, цифр и символов _ . : / -, например env:prod или team:payments
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]*$`)
+
+// NormalizeTag приводит тег к виду в котором он хранится
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags приводит теги к виду в котором они хранятся, убирает пустые и повторы, сортирует
+func NormalizeTags(tags []string) []string {
+	list := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			list = append(list, tag)
+		}
+	}
+
+	slices.Sort(list)
+
+	return slices.Compact(list)
+}
+
+// ParseTags теги из текста через пробел или запятую, "-" - без тегов
+func ParseTags(text string) []string {
+	if strings.TrimSpace(text) == "-" {
+		return []string{}
+	}
+
+	return NormalizeTags(strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	}))
+}
+
+// ValidateTag проверяет тег после NormalizeTag
+func ValidateTag(tag string) error {
+	if len(tag) > MaxTagLength || !tagPattern.MatchString(tag) {
+		return i18n.NewError("tag.error_invalid", tag, MaxTagLength)
+	}
+
+	return nil
+}
+
+// ParseTagFilter тег для фильтрации списков, пустая строка - без фильтра
+func ParseTagFilter(text string) (string, error) {
+	tag := NormalizeTag(text)
+	if tag == "" {
+		return "", nil
+	}
+
+	return tag, ValidateTag(tag)
+}
+
+// HasTag у ссылки есть указанный тег
+func (p Ping) HasTag(tag string) bool {
+	return slices.Contains(p.Tags, tag)
+}
+
+// WithTag ссылки с указанным тегом, пустой тег - все ссылки
+func (l PingList) WithTag(tag string) PingList {
+	if tag == "" {
+		return l
+	}
+
+	list := make(PingList, 0, len(l))
+	for _, ping := range l {
+		if ping.HasTag(tag) {
+			list = append(list, ping)
+		}
+	}
+
+	return list
+}
+
+// Urls адреса ссылок списка
+func (l PingList) Urls() []string {
+	urls := make([]string, 0, len(l))
+	for _, ping := range l {
+		urls = append(urls, ping.Url)
+	}
+
+	return urls
+}
+
+// Tags все теги ссылок списка по алфавиту
+func (l PingList) Tags() []string {
+	var tags []string
+	for _, ping := range l {
+		tags = append(tags, ping.Tags...)
+	}
+
+	return NormalizeTags(tags)
+}
+
+// WithUrls статистика только по указанным ссылкам
+func (l StatisticResultList) WithUrls(urls []string) StatisticResultList {
+	list := make(StatisticResultList, 0, len(l))
+	for _, item := range l {
+		if slices.Contains(urls, item.Url) {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	RoleOwner  Role = "owner"  // управляет пространством, участниками и чатами
+	RoleEditor Role = "editor" // добавляет, изменяет и удаляет ссылки
+	RoleViewer Role = "viewer" // только просматривает ссылки, статистику и получает уведомления
+)
+
+type (
+	Role string
+
+	// Workspace рабочее пространство, ссылки и уведомления общие для всех его участников
+	Workspace struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+		Role Role   `json:"role"` // роль текущего пользователя в пространстве
+	}
+
+	// Member участник рабочего пространства
+	Member struct {
+		WorkspaceId int64  `json:"workspace_id"`
+		UserId      int64  `json:"user_id"`
+		Login       string `json:"login"`
+		Role        Role   `json:"role"`
+	}
+
+	// WorkspaceChat групповой чат или канал, в который отправляются уведомления пространства
+	WorkspaceChat struct {
+		WorkspaceId int64  `json:"workspace_id"`
+		ChatId      int64  `json:"chat_id"`
+		Title       string `json:"title"`
+	}
+
+	// Invite приглашение в рабочее пространство, используется по ссылке t.me/<bot>?start=<token>
+	Invite struct {
+		Token       string    `json:"token"`
+		WorkspaceId int64     `json:"workspace_id"`
+		Role        Role      `json:"role"`
+		ExpiresAt   time.Time `json:"expires_at"`
+	}
+
+	// Recipient получатель уведомлений пространства: участник (UserId = ChatId) или подключенный чат (UserId = 0)
+	Recipient struct {
+		ChatId int64
+		UserId int64
+	}
+)
+
+// CanEdit может ли роль изменять ссылки пространства
+func (r Role) CanEdit() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// IsValid известна ли роль
+func (r Role) IsValid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+// IsChat получатель является групповым чатом или каналом
+func (r Recipient) IsChat() bool {
+	return r.UserId == 0
+}
@@ -0,0 +1,308 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	pollInterval     = 200 * time.Millisecond // как часто проверять чаты готовые к отправке
+	readyChatsLimit  = 100                    // сколько чатов обрабатывать за один проход
+	pendingLimit     = 100                    // сколько сообщений одного чата можно объединить в сводку
+	summaryMaxLength = 3500                   // ограничение длины сводки, у телеграма лимит 4096 символов на сообщение
+)
+
+type (
+	// Outbox Интерфейс реалезует хранилище исходящих сообщений
+	Outbox interface {
+		Push(ctx context.Context, chatId int64, payload string, readyAt time.Time) error
+		ReadyChats(ctx context.Context, now time.Time, limit int64) ([]int64, error)
+		Pending(ctx context.Context, chatId int64, limit int64) ([]string, error)
+		Update(ctx context.Context, chatId int64, index int64, payload string) error
+		Remove(ctx context.Context, chatId int64, count int64) error
+		Schedule(ctx context.Context, chatId int64, at time.Time) error
+		Release(ctx context.Context, chatId int64) error
+		Backlog(ctx context.Context) (int64, time.Time, error)
+	}
+
+	// Sender Интерфейс реалезует непосредственную отправку сообщения в телеграм
+	Sender interface {
+		Send(c tgbotapi.Chattable) error
+	}
+
+	// Message исходящее сообщение в том виде, в котором оно хранится в очереди
+	Message struct {
+		ChatId      int64                          `json:"chat_id"`
+		Text        string                         `json:"text"`
+		ParseMode   string                         `json:"parse_mode,omitempty"`
+		ReplyMarkup *tgbotapi.InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+		Coalesce    bool                           `json:"coalesce,omitempty"` // можно ли объединить со следующими уведомлениями в одну сводку
+		Attempts    int                            `json:"attempts,omitempty"`
+	}
+
+	// Queue очередь исходящих сообщений бота с ограничением частоты отправки в каждый чат и в целом,
+	// уведомления пришедшие пачкой в один чат объединяются в одно сообщение
+	Queue struct {
+		outbox  Outbox
+		kernel  *kernel.Kernel
+		limiter *limiter
+		blocked map[int64]time.Time // чаты по которым телеграм вернул retry_after, используется только из Run
+	}
+)
+
+func NewQueue(outbox Outbox, k *kernel.Kernel) *Queue {
+	cfg := k.Config().Notification
+
+	return &Queue{
+		outbox:  outbox,
+		kernel:  k,
+		limiter: newLimiter(cfg.GlobalRate),
+		blocked: make(map[int64]time.Time),
+	}
+}
+
+// Push ставит сообщение в очередь, если coalesce = true отправка откладывается на CoalesceWindow,
+// чтобы успеть собрать пачку уведомлений в одну сводку
+func (q *Queue) Push(ctx context.Context, msg tgbotapi.MessageConfig, coalesce bool) error {
+	const op = "notification.Push"
+
+	m := Message{
+		ChatId:    msg.ChatID,
+		Text:      msg.Text,
+		ParseMode: msg.ParseMode,
+		Coalesce:  coalesce,
+	}
+
+	if markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup); ok {
+		m.ReplyMarkup = &markup
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	readyAt := time.Now()
+	if coalesce {
+		readyAt = readyAt.Add(q.kernel.Config().Notification.CoalesceWindow)
+	}
+
+	if err := q.outbox.Push(ctx, m.ChatId, string(payload), readyAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stats размер очереди и задержка отправки: сколько ждет самый старый чат, в который уже можно отправлять.
+// Чаты отложенные из-за ограничений телеграма или сбора сводки задержкой не считаются
+func (q *Queue) Stats(ctx context.Context) (model.QueueStats, error) {
+	const op = "notification.Stats"
+
+	chats, readyAt, err := q.outbox.Backlog(ctx)
+	if err != nil {
+		return model.QueueStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := model.QueueStats{Chats: chats}
+	if !readyAt.IsZero() {
+		stats.Lag = max(0, time.Since(readyAt).Seconds())
+	}
+
+	return stats, nil
+}
+
+// Run отправляет сообщения из очереди, должен работать в одном экземпляре
+func (q *Queue) Run(sender Sender) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		q.flush(sender)
+	}
+}
+
+func (q *Queue) flush(sender Sender) {
+	const op = "notification.flush"
+	ctx := context.Background()
+
+	chats, err := q.outbox.ReadyChats(ctx, time.Now(), readyChatsLimit)
+	if err != nil {
+		q.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	for _, chatId := range chats {
+		if err := q.deliver(ctx, sender, chatId); err != nil {
+			q.kernel.Log().Error(fmt.Sprintf("%s, chat: %d, error: %s", op, chatId, err))
+		}
+	}
+}
+
+// deliver отправляет очередное сообщение (или сводку) в чат и планирует следующую отправку
+func (q *Queue) deliver(ctx context.Context, sender Sender, chatId int64) error {
+	cfg := q.kernel.Config().Notification
+
+	// новое сообщение могло приблизить время отправки, но ограничение от телеграма важнее
+	if until, ok := q.blocked[chatId]; ok {
+		if time.Now().Before(until) {
+			return q.outbox.Schedule(ctx, chatId, until)
+		}
+
+		delete(q.blocked, chatId)
+	}
+
+	pending, err := q.outbox.Pending(ctx, chatId, pendingLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return q.outbox.Release(ctx, chatId)
+	}
+
+	messages := make([]Message, 0, len(pending))
+	for _, payload := range pending {
+		var m Message
+		if err := json.Unmarshal([]byte(payload), &m); err != nil {
+			// битое сообщение отправить не получится, выкидываем его чтобы не блокировать очередь
+			q.kernel.Log().Error(fmt.Sprintf("notification.deliver, chat: %d, broken message: %s", chatId, err))
+			if err := q.outbox.Remove(ctx, chatId, 1); err != nil {
+				return err
+			}
+
+			return q.outbox.Schedule(ctx, chatId, time.Now())
+		}
+
+		messages = append(messages, m)
+	}
+
+	msg, count := q.batch(messages)
+
+	q.limiter.wait()
+	err = sender.Send(msg)
+
+	var tgErr *tgbotapi.Error
+	switch {
+	case err == nil:
+	case errors.As(err, &tgErr) && tgErr.RetryAfter > 0:
+		// телеграм просит подождать, отправим эти же сообщения позже
+		q.blocked[chatId] = time.Now().Add(time.Duration(tgErr.RetryAfter) * time.Second)
+
+		return q.outbox.Schedule(ctx, chatId, q.blocked[chatId])
+	case isTransient(err) && messages[0].Attempts+1 < cfg.MaxAttempts:
+		messages[0].Attempts++
+		payload, errMarshal := json.Marshal(messages[0])
+		if errMarshal != nil {
+			return errMarshal
+		}
+
+		if err := q.outbox.Update(ctx, chatId, 0, string(payload)); err != nil {
+			return err
+		}
+
+		q.kernel.Log().Info(fmt.Sprintf("notification.deliver, chat: %d, attempt %d: %s", chatId, messages[0].Attempts, err))
+
+		return q.outbox.Schedule(ctx, chatId, time.Now().Add(backoff(messages[0].Attempts)))
+	default:
+		q.kernel.Log().Error(fmt.Sprintf("notification.deliver, chat: %d, drop %d message(s): %s", chatId, count, err))
+	}
+
+	if err := q.outbox.Remove(ctx, chatId, int64(count)); err != nil {
+		return err
+	}
+
+	if err := q.outbox.Schedule(ctx, chatId, time.Now().Add(cfg.ChatInterval)); err != nil {
+		return err
+	}
+
+	return q.outbox.Release(ctx, chatId)
+}
+
+// batch собирает сообщение для отправки: идущие подряд уведомления объединяются в сводку,
+// возвращает сообщение и кол-во сообщений очереди которые в него вошли
+func (q *Queue) batch(messages []Message) (tgbotapi.MessageConfig, int) {
+	first := messages[0]
+
+	count := 1
+	if first.Coalesce {
+		for count < len(messages) && messages[count].Coalesce && messages[count].ParseMode == first.ParseMode {
+			count++
+		}
+	}
+
+	if count == 1 {
+		msg := tgbotapi.NewMessage(first.ChatId, first.Text)
+		msg.ParseMode = first.ParseMode
+		if first.ReplyMarkup != nil {
+			msg.ReplyMarkup = *first.ReplyMarkup
+		}
+
+		return msg, 1
+	}
+
+	// очередь не знает языка получателя, поэтому заголовок и хвост сводки без текста
+	str := strings.Builder{}
+	str.WriteString(fmt.Sprintf("📬 %d\n\n", count))
+	for i, m := range messages[:count] {
+		if str.Len()+len(m.Text) > summaryMaxLength {
+			str.WriteString(fmt.Sprintf("... +%d", count-i))
+			break
+		}
+
+		str.WriteString(m.Text)
+		str.WriteString("\n\n")
+	}
+
+	msg := tgbotapi.NewMessage(first.ChatId, str.String())
+	msg.ParseMode = first.ParseMode
+
+	return msg, count
+}
+
+// isTransient временные ошибки, после которых есть смысл повторить отправку
+func isTransient(err error) bool {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) {
+		return tgErr.Code >= http.StatusInternalServerError
+	}
+
+	// ошибки сети и разбора ответа
+	return true
+}
+
+func backoff(attempt int) time.Duration {
+	return time.Duration(1<<attempt) * time.Second
+}
+
+// limiter ограничивает общую частоту отправки сообщений, используется из одной горутины
+type limiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(perSecond int) *limiter {
+	if perSecond <= 0 {
+		perSecond = 1
+	}
+
+	return &limiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+func (l *limiter) wait() {
+	now := time.Now()
+	if l.next.After(now) {
+		time.Sleep(l.next.Sub(now))
+		now = l.next
+	}
+
+	l.next = now.Add(l.interval)
+}
@@ -0,0 +1,84 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+)
+
+type (
+	// UrlUpdater Интерфейс реалезует возможность изменить ссылку пространства
+	UrlUpdater interface {
+		UrlExist(workspaceId int64, url string) (bool, error)
+		UpdateUrl(workspaceId, id int64, patch model.PingPatch) (string, error)
+		PingById(id int64) (model.Ping, error)
+	}
+
+	// HistoryRenamer Интерфейс реалезует возможность перенести историю опросов на новый адрес ссылки
+	HistoryRenamer interface {
+		RenameUrl(workspaceId int64, oldUrl, newUrl string) error
+	}
+
+	// Editor изменяет настройки ссылки без ее пересоздания, чтобы не терять историю опросов и инциденты
+	Editor struct {
+		urls      UrlUpdater
+		history   HistoryRenamer
+		scheduler *Ping
+		kernel    *kernel.Kernel
+	}
+)
+
+func NewEditor(k *kernel.Kernel, urls UrlUpdater, history HistoryRenamer, scheduler *Ping) *Editor {
+	return &Editor{
+		urls:      urls,
+		history:   history,
+		scheduler: scheduler,
+		kernel:    k,
+	}
+}
+
+// EditUrl проверяет и сохраняет изменения ссылки, при смене адреса переносит историю опросов
+// и перезапускает опрос только этой ссылки
+func (e *Editor) EditUrl(ctx context.Context, workspaceId, id int64, patch model.PingPatch) (model.Ping, error) {
+	const op = "ping.editor.EditUrl"
+
+	if err := patch.Validate(); err != nil {
+		return model.Ping{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if patch.Url != nil {
+		exist, err := e.urls.UrlExist(workspaceId, *patch.Url)
+		if err != nil {
+			return model.Ping{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if exist {
+			return model.Ping{}, fmt.Errorf("%s: %w", op, storage.ErrUrlExists)
+		}
+	}
+
+	oldUrl, err := e.urls.UpdateUrl(workspaceId, id, patch)
+	if err != nil {
+		return model.Ping{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if patch.Url != nil && *patch.Url != oldUrl {
+		// результаты накопленные до изменения сохраняем сразу, чтобы они тоже попали под перенос
+		e.scheduler.startInserting()
+
+		if err := e.history.RenameUrl(workspaceId, oldUrl, *patch.Url); err != nil {
+			e.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		}
+	}
+
+	ping, err := e.urls.PingById(id)
+	if err != nil {
+		return ping, fmt.Errorf("%s: %w", op, err)
+	}
+
+	e.scheduler.Reschedule(ping)
+
+	return ping, nil
+}
@@ -0,0 +1,237 @@
+package ping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"slices"
+	"time"
+)
+
+type (
+	// UrlImporter Интерфейс реалезует возможность добавлять и изменять ссылки пространства пачкой
+	UrlImporter interface {
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+		SaveUrl(workspaceId, userId int64, url, connectionTime, pingTime string) error
+		UpdateUrl(workspaceId, id int64, patch model.PingPatch) (string, error)
+		SetCritical(workspaceId int64, url string, critical bool) error
+		SetTags(workspaceId int64, url string, tags []string) error
+	}
+
+	// MonitorRefresher Интерфейс реалезует возможность запустить опрос добавленных ссылок
+	MonitorRefresher interface {
+		Refresh()
+	}
+
+	// Importer импорт и экспорт ссылок пространства файлом
+	Importer struct {
+		urls      UrlImporter
+		scheduler MonitorRefresher
+		kernel    *kernel.Kernel
+	}
+)
+
+func NewImporter(k *kernel.Kernel, urls UrlImporter, scheduler MonitorRefresher) *Importer {
+	return &Importer{
+		urls:      urls,
+		scheduler: scheduler,
+		kernel:    k,
+	}
+}
+
+// Import проверяет каждую запись файла и добавляет новые ссылки, при options.Upsert изменяет настройки существующих,
+// при options.DryRun ничего не сохраняет. Ошибки записей попадают в отчет на языке из контекста
+func (i *Importer) Import(ctx context.Context, workspaceId, userId int64, rows []transfer.Row, options model.ImportOptions) (model.ImportReport, error) {
+	const op = "ping.importer.Import"
+
+	report := model.ImportReport{DryRun: options.DryRun}
+	loc := i18n.FromContext(ctx)
+
+	list, err := i.urls.UrlListByWorkspace(workspaceId)
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+
+	existing := make(map[string]model.Ping, len(list))
+	for _, ping := range list {
+		existing[ping.Url] = ping
+	}
+
+	seen := make(map[string]int, len(rows))
+	for _, row := range rows {
+		result := model.ImportRow{Line: row.Line, Url: row.Monitor.Url}
+
+		status, err := i.importRow(workspaceId, userId, row, existing, seen, options)
+		if err != nil {
+			result.Error = loc.Error(err)
+		}
+
+		// в отчет внутренняя ошибка попадает общим сообщением, подробности нужны в логе
+		var userErr *i18n.Error
+		if err != nil && !errors.As(err, &userErr) {
+			i.kernel.Log().Error(fmt.Sprintf("%s, line: %d, error: %s", op, row.Line, err))
+		}
+
+		result.Status = status
+		report.Add(result)
+	}
+
+	if !options.DryRun && report.Created+report.Updated > 0 {
+		i.scheduler.Refresh()
+	}
+
+	return report, nil
+}
+
+// Export настройки всех ссылок пространства в порядке добавления
+func (i *Importer) Export(workspaceId int64) ([]transfer.Monitor, error) {
+	const op = "ping.importer.Export"
+
+	list, err := i.urls.UrlListByWorkspace(workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	monitors := make([]transfer.Monitor, 0, len(list))
+	for n := len(list) - 1; n >= 0; n-- {
+		critical := list[n].Critical
+		monitors = append(monitors, transfer.Monitor{
+			Url:            list[n].Url,
+			ConnectionTime: list[n].ConnectionTime,
+			PingTime:       list[n].PingTime,
+			Critical:       &critical,
+			Tags:           list[n].Tags,
+		})
+	}
+
+	return monitors, nil
+}
+
+func (i *Importer) importRow(workspaceId, userId int64, row transfer.Row, existing map[string]model.Ping, seen map[string]int, options model.ImportOptions) (model.ImportStatus, error) {
+	const op = "ping.importer.importRow"
+
+	if row.Err != nil {
+		return model.ImportInvalid, row.Err
+	}
+
+	m := row.Monitor
+	if m.Url == "" {
+		return model.ImportInvalid, i18n.NewError("import.error_url_required")
+	}
+
+	if line, ok := seen[m.Url]; ok {
+		return model.ImportInvalid, i18n.NewError("import.error_duplicate", line)
+	}
+	seen[m.Url] = row.Line
+
+	current, exists := existing[m.Url]
+	if !exists && (m.ConnectionTime == "" || m.PingTime == "") {
+		return model.ImportInvalid, i18n.NewError("import.error_required")
+	}
+
+	// не указанные значения у существующей ссылки означают что настройка не меняется
+	patch := model.PingPatch{Url: &m.Url}
+	if m.ConnectionTime != "" {
+		patch.ConnectionTime = &m.ConnectionTime
+	}
+	if m.PingTime != "" {
+		patch.PingTime = &m.PingTime
+	}
+	if m.Tags != nil {
+		patch.Tags = &m.Tags
+	}
+
+	if err := patch.Validate(); err != nil {
+		return model.ImportInvalid, err
+	}
+
+	if !exists {
+		if options.DryRun {
+			return model.ImportCreated, nil
+		}
+
+		if err := i.urls.SaveUrl(workspaceId, userId, m.Url, m.ConnectionTime, m.PingTime); err != nil {
+			i.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+			return model.ImportInvalid, i18n.NewError("import.error_save")
+		}
+
+		if err := i.setCritical(workspaceId, m, false); err != nil {
+			return model.ImportCreated, err
+		}
+
+		return model.ImportCreated, i.setTags(workspaceId, m)
+	}
+
+	patch.Url = nil
+	if patch.ConnectionTime != nil && sameDuration(*patch.ConnectionTime, current.ConnectionTime) {
+		patch.ConnectionTime = nil
+	}
+	if patch.PingTime != nil && sameDuration(*patch.PingTime, current.PingTime) {
+		patch.PingTime = nil
+	}
+	if patch.Tags != nil && slices.Equal(model.NormalizeTags(*patch.Tags), current.Tags) {
+		patch.Tags = nil
+	}
+
+	changed := patch.ConnectionTime != nil || patch.PingTime != nil || patch.Tags != nil || (m.Critical != nil && *m.Critical != current.Critical)
+	switch {
+	case !changed:
+		return model.ImportUnchanged, nil
+	case !options.Upsert:
+		return model.ImportSkipped, i18n.NewError("import.error_exists")
+	case options.DryRun:
+		return model.ImportUpdated, nil
+	}
+
+	if patch.ConnectionTime != nil || patch.PingTime != nil || patch.Tags != nil {
+		if _, err := i.urls.UpdateUrl(workspaceId, current.Id, patch); err != nil {
+			i.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+			return model.ImportInvalid, i18n.NewError("import.error_save")
+		}
+	}
+
+	return model.ImportUpdated, i.setCritical(workspaceId, m, current.Critical)
+}
+
+// setCritical меняет критичность только если она указана и отличается от текущей
+func (i *Importer) setCritical(workspaceId int64, m transfer.Monitor, current bool) error {
+	const op = "ping.importer.setCritical"
+
+	if m.Critical == nil || *m.Critical == current {
+		return nil
+	}
+
+	if err := i.urls.SetCritical(workspaceId, m.Url, *m.Critical); err != nil {
+		i.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return i18n.NewError("import.error_save")
+	}
+
+	return nil
+}
+
+// setTags сохраняет теги новой ссылки, если они указаны
+func (i *Importer) setTags(workspaceId int64, m transfer.Monitor) error {
+	const op = "ping.importer.setTags"
+
+	if len(m.Tags) == 0 {
+		return nil
+	}
+
+	if err := i.urls.SetTags(workspaceId, m.Url, m.Tags); err != nil {
+		i.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return i18n.NewError("import.error_save")
+	}
+
+	return nil
+}
+
+func sameDuration(a, b string) bool {
+	da, errA := time.ParseDuration(a)
+	db, errB := time.ParseDuration(b)
+
+	return errA == nil && errB == nil && da == db
+}
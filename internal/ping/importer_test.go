@@ -0,0 +1,242 @@
+package ping
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/config"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type (
+	fakeConnection struct{}
+
+	// fakeUrls ссылки пространства в памяти, изменения записываются в calls
+	fakeUrls struct {
+		list  model.PingList
+		calls []string
+	}
+
+	// fakeRefresher считает запуски обновления списка ссылок
+	fakeRefresher struct {
+		count int
+	}
+)
+
+func (fakeConnection) MustRunMigrations()     {}
+func (fakeConnection) DB() *sql.DB            { return nil }
+func (fakeConnection) ConnectionName() string { return "fake" }
+
+func (f *fakeUrls) UrlListByWorkspace(workspaceId int64) (model.PingList, error) {
+	return f.list, nil
+}
+
+func (f *fakeUrls) SaveUrl(workspaceId, userId int64, url, connectionTime, pingTime string) error {
+	f.calls = append(f.calls, fmt.Sprintf("save %s %s %s", url, connectionTime, pingTime))
+	return nil
+}
+
+func (f *fakeUrls) UpdateUrl(workspaceId, id int64, patch model.PingPatch) (string, error) {
+	fields := []string{fmt.Sprintf("update %d", id)}
+	if patch.ConnectionTime != nil {
+		fields = append(fields, "connection_time="+*patch.ConnectionTime)
+	}
+	if patch.PingTime != nil {
+		fields = append(fields, "ping_time="+*patch.PingTime)
+	}
+	if patch.Tags != nil {
+		fields = append(fields, fmt.Sprintf("tags=%v", *patch.Tags))
+	}
+
+	f.calls = append(f.calls, strings.Join(fields, " "))
+
+	return "", nil
+}
+
+func (f *fakeUrls) SetCritical(workspaceId int64, url string, critical bool) error {
+	f.calls = append(f.calls, fmt.Sprintf("critical %s %t", url, critical))
+	return nil
+}
+
+func (f *fakeUrls) SetTags(workspaceId int64, url string, tags []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("tags %s %v", url, tags))
+	return nil
+}
+
+func (f *fakeRefresher) Refresh() {
+	f.count++
+}
+
+func TestImport(t *testing.T) {
+	k := kernel.MustCreateKernel(&config.Config{Env: "local"}, fakeConnection{}, nil)
+	critical, notCritical := true, false
+
+	// в пространстве уже есть одна ссылка
+	existing := model.Ping{Id: 1, Url: "https://a.com", ConnectionTime: "10s", PingTime: "1m", Tags: []string{"env:prod"}}
+
+	tests := []struct {
+		name      string
+		rows      []transfer.Row
+		options   model.ImportOptions
+		want      []model.ImportRow
+		calls     []string
+		refreshed bool
+	}{
+		{
+			name: "новая ссылка с критичностью и тегами",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "30s", Critical: &critical, Tags: []string{"team:ops", "env:prod"}}},
+			},
+			want: []model.ImportRow{{Line: 1, Url: "https://b.com", Status: model.ImportCreated}},
+			calls: []string{
+				"save https://b.com 5s 30s",
+				"critical https://b.com true",
+				"tags https://b.com [team:ops env:prod]",
+			},
+			refreshed: true,
+		},
+		{
+			name: "новая ссылка не критичная и без тегов",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "30s", Critical: &notCritical}},
+			},
+			want:      []model.ImportRow{{Line: 1, Url: "https://b.com", Status: model.ImportCreated}},
+			calls:     []string{"save https://b.com 5s 30s"},
+			refreshed: true,
+		},
+		{
+			name: "dry-run ничего не сохраняет",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "30s", Critical: &critical}},
+				{Line: 2, Monitor: transfer.Monitor{Url: "https://a.com", PingTime: "5m"}},
+			},
+			options: model.ImportOptions{DryRun: true, Upsert: true},
+			want: []model.ImportRow{
+				{Line: 1, Url: "https://b.com", Status: model.ImportCreated},
+				{Line: 2, Url: "https://a.com", Status: model.ImportUpdated},
+			},
+		},
+		{
+			name: "те же настройки в другой записи не считаются изменением",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://a.com", ConnectionTime: "10000ms", PingTime: "60s", Critical: &notCritical, Tags: []string{"ENV:prod", "env:prod"}}},
+			},
+			options: model.ImportOptions{Upsert: true},
+			want:    []model.ImportRow{{Line: 1, Url: "https://a.com", Status: model.ImportUnchanged}},
+		},
+		{
+			name: "не указанные настройки существующей ссылки не меняются",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://a.com"}},
+			},
+			want: []model.ImportRow{{Line: 1, Url: "https://a.com", Status: model.ImportUnchanged}},
+		},
+		{
+			name: "изменение существующей ссылки без upsert",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://a.com", PingTime: "5m"}},
+			},
+			want: []model.ImportRow{{Line: 1, Url: "https://a.com", Status: model.ImportSkipped, Error: errorText("import.error_exists")}},
+		},
+		{
+			name: "upsert сохраняет только отличающиеся настройки",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://a.com", ConnectionTime: "10s", PingTime: "5m", Critical: &critical}},
+			},
+			options:   model.ImportOptions{Upsert: true},
+			want:      []model.ImportRow{{Line: 1, Url: "https://a.com", Status: model.ImportUpdated}},
+			calls:     []string{"update 1 ping_time=5m", "critical https://a.com true"},
+			refreshed: true,
+		},
+		{
+			name: "upsert меняет только критичность",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://a.com", Critical: &critical}},
+			},
+			options:   model.ImportOptions{Upsert: true},
+			want:      []model.ImportRow{{Line: 1, Url: "https://a.com", Status: model.ImportUpdated}},
+			calls:     []string{"critical https://a.com true"},
+			refreshed: true,
+		},
+		{
+			name: "повтор ссылки в файле",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "30s"}},
+				{Line: 2, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "1m"}},
+			},
+			want: []model.ImportRow{
+				{Line: 1, Url: "https://b.com", Status: model.ImportCreated},
+				{Line: 2, Url: "https://b.com", Status: model.ImportInvalid, Error: errorText("import.error_duplicate", 1)},
+			},
+			calls:     []string{"save https://b.com 5s 30s"},
+			refreshed: true,
+		},
+		{
+			name: "повтор ссылки в файле при dry-run",
+			rows: []transfer.Row{
+				{Line: 3, Monitor: transfer.Monitor{Url: "https://a.com"}},
+				{Line: 7, Monitor: transfer.Monitor{Url: "https://a.com", PingTime: "5m"}},
+			},
+			options: model.ImportOptions{DryRun: true},
+			want: []model.ImportRow{
+				{Line: 3, Url: "https://a.com", Status: model.ImportUnchanged},
+				{Line: 7, Url: "https://a.com", Status: model.ImportInvalid, Error: errorText("import.error_duplicate", 3)},
+			},
+		},
+		{
+			name: "ошибки записей",
+			rows: []transfer.Row{
+				{Line: 1, Monitor: transfer.Monitor{Url: "https://b.com", ConnectionTime: "5s"}},
+				{Line: 2, Monitor: transfer.Monitor{ConnectionTime: "5s", PingTime: "1m"}},
+				{Line: 3, Monitor: transfer.Monitor{Url: "https://c.com"}, Err: i18n.NewError("transfer.error_critical", "yes")},
+				{Line: 4, Monitor: transfer.Monitor{Url: "https://d.com", ConnectionTime: "5s", PingTime: "10s"}},
+			},
+			want: []model.ImportRow{
+				{Line: 1, Url: "https://b.com", Status: model.ImportInvalid, Error: errorText("import.error_required")},
+				{Line: 2, Status: model.ImportInvalid, Error: errorText("import.error_url_required")},
+				{Line: 3, Url: "https://c.com", Status: model.ImportInvalid, Error: errorText("transfer.error_critical", "yes")},
+				{Line: 4, Url: "https://d.com", Status: model.ImportInvalid, Error: errorText("patch.error_min_ping_time", model.MinPingTime)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := &fakeUrls{list: model.PingList{existing}}
+			refresher := &fakeRefresher{}
+			importer := NewImporter(k, urls, refresher)
+
+			report, err := importer.Import(context.Background(), 1, 1, tt.rows, tt.options)
+			if err != nil {
+				t.Fatalf("ошибка импорта: %v", err)
+			}
+
+			if !reflect.DeepEqual(report.Rows, tt.want) {
+				t.Fatalf("записи\n%+v\nожидались\n%+v", report.Rows, tt.want)
+			}
+
+			if !reflect.DeepEqual(urls.calls, tt.calls) {
+				t.Fatalf("изменения %q, ожидались %q", urls.calls, tt.calls)
+			}
+
+			if refreshed := refresher.count > 0; refreshed != tt.refreshed {
+				t.Fatalf("обновление списка ссылок %t, ожидалось %t", refreshed, tt.refreshed)
+			}
+
+			if report.DryRun != tt.options.DryRun {
+				t.Fatalf("dry_run %t, ожидался %t", report.DryRun, tt.options.DryRun)
+			}
+		})
+	}
+}
+
+// errorText текст ошибки записи в отчете на языке по умолчанию
+func errorText(key string, args ...any) string {
+	return i18n.Default.Error(i18n.NewError(key, args...))
+}
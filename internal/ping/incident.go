@@ -0,0 +1,46 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"time"
+)
+
+// IncidentRecorder Интерфейс реалезует возможность вести инциденты по ссылкам
+type IncidentRecorder interface {
+	OpenIncident(ctx context.Context, pingId int64, kind model.IncidentEventKind, firstError string, at time.Time) error
+	AddIncidentEvent(ctx context.Context, pingId int64, kind model.IncidentEventKind, text string, at time.Time) error
+	CloseIncident(ctx context.Context, pingId int64, at time.Time) error
+}
+
+// recordIncident открывает инцидент когда ссылка перестает нормально работать, дописывает в него смену состояния
+// и закрывает когда ссылка восстанавливается
+func (p *Ping) recordIncident(ctx context.Context, state model.MonitorState, wasUp bool) {
+	const op = "ping.incident.recordIncident"
+
+	var err error
+	switch {
+	case state.Status == model.StatusUp:
+		err = p.incidents.CloseIncident(ctx, state.PingId, state.Since)
+	case wasUp:
+		err = p.incidents.OpenIncident(ctx, state.PingId, incidentEventKind(state.Status), state.LastError, state.Since)
+	default:
+		err = p.incidents.AddIncidentEvent(ctx, state.PingId, incidentEventKind(state.Status), state.LastError, state.Since)
+	}
+
+	if err != nil {
+		p.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+}
+
+func incidentEventKind(status model.MonitorStatus) model.IncidentEventKind {
+	switch status {
+	case model.StatusDown:
+		return model.IncidentEventDown
+	case model.StatusDegraded:
+		return model.IncidentEventDegraded
+	default:
+		return model.IncidentEventRecovery
+	}
+}
@@ -0,0 +1,95 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/telegram"
+	"html"
+	"time"
+)
+
+// muteReleaseInterval как часто проверять истекшие отключения уведомлений
+const muteReleaseInterval = time.Minute
+
+type (
+	// UrlMuteReleaser Интерфейс реалезует возможность включить уведомления по ссылкам с истекшим временем отключения
+	UrlMuteReleaser interface {
+		ReleaseExpiredMutes() (model.PingList, error)
+	}
+
+	// UserMuteReleaser Интерфейс реалезует возможность включить уведомления пользователям с истекшим временем отключения
+	UserMuteReleaser interface {
+		ReleaseExpiredMutes(ctx context.Context) ([]model.User, error)
+	}
+
+	// MuteWatcher следит за отключениями уведомлений с ограниченным сроком, снимает их и сообщает об этом пользователю
+	MuteWatcher struct {
+		urlRepo  UrlMuteReleaser
+		userRepo UserMuteReleaser
+		kernel   *kernel.Kernel
+		bot      *telegram.Bot
+	}
+)
+
+func NewMuteWatcher(urlRepo UrlMuteReleaser, userRepo UserMuteReleaser, k *kernel.Kernel, bot *telegram.Bot) *MuteWatcher {
+	return &MuteWatcher{
+		urlRepo:  urlRepo,
+		userRepo: userRepo,
+		kernel:   k,
+		bot:      bot,
+	}
+}
+
+func (m *MuteWatcher) Run() {
+	ticker := time.NewTicker(muteReleaseInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.releaseUrls()
+		m.releaseUsers()
+	}
+}
+
+func (m *MuteWatcher) releaseUrls() {
+	const op = "ping.mute.releaseUrls"
+
+	list, err := m.urlRepo.ReleaseExpiredMutes()
+	if err != nil {
+		m.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	// сообщаем тому, кто отключил уведомления, в чат где это было сделано, язык - того, кто отключил
+	for _, ping := range list {
+		m.notify(ping.MuteChatId, i18n.Resolve(ping.User.Language, "").T("mute.released_url", html.EscapeString(ping.Url)))
+	}
+}
+
+func (m *MuteWatcher) releaseUsers() {
+	const op = "ping.mute.releaseUsers"
+
+	list, err := m.userRepo.ReleaseExpiredMutes(context.Background())
+	if err != nil {
+		m.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	for _, user := range list {
+		m.notify(user.Id, i18n.Resolve(user.Language, "").T("mute.released_all"))
+	}
+}
+
+func (m *MuteWatcher) notify(chatId int64, text string) {
+	const op = "ping.mute.notify"
+
+	msg := tgbotapi.NewMessage(chatId, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	if err := m.bot.SendNotification(msg); err != nil {
+		m.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+}
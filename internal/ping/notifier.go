@@ -0,0 +1,166 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/alert"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/telegram"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"time"
+)
+
+type (
+	// MuteChecker Интерфейс реалезует возможность проверить не отключены ли уведомления по ссылке для получателя
+	MuteChecker interface {
+		IsMuted(ctx context.Context, pingId, userId int64) (bool, error)
+	}
+
+	// RecipientProvider Интерфейс реалезует возможность получить всех получателей уведомлений пространства
+	RecipientProvider interface {
+		Recipients(ctx context.Context, workspaceId int64) ([]model.Recipient, error)
+	}
+
+	// DigestHolder Интерфейс реалезует возможность отложить уведомление до окончания тихих часов
+	DigestHolder interface {
+		Hold(ctx context.Context, userId int64, text string) error
+	}
+
+	// AlertTemplateProvider Интерфейс реалезует возможность получить шаблон уведомления пользователя
+	AlertTemplateProvider interface {
+		AlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) (string, error)
+	}
+
+	// Notifier собирает уведомление по шаблону пользователя и доставляет его с учетом отключений и тихих часов
+	Notifier struct {
+		muteChecker MuteChecker
+		recipients  RecipientProvider
+		settings    NotificationSettingsProvider
+		digest      DigestHolder
+		templates   AlertTemplateProvider
+		kernel      *kernel.Kernel
+		bot         *telegram.Bot
+	}
+)
+
+func NewNotifier(k *kernel.Kernel, bot *telegram.Bot, muteChecker MuteChecker, recipients RecipientProvider, settings NotificationSettingsProvider, digest DigestHolder, templates AlertTemplateProvider) *Notifier {
+	return &Notifier{
+		muteChecker: muteChecker,
+		recipients:  recipients,
+		settings:    settings,
+		digest:      digest,
+		templates:   templates,
+		kernel:      k,
+		bot:         bot,
+	}
+}
+
+// Alert отправляет уведомление о смене состояния ссылки всем участникам и чатам пространства,
+// outage - сколько длилось предыдущее состояние
+func (n *Notifier) Alert(ctx context.Context, result model.PingResult, kind model.AlertKind, outage time.Duration) {
+	const op = "ping.notifier.Alert"
+
+	recipients, err := n.recipients.Recipients(ctx, result.Ping.WorkspaceId)
+	if err != nil {
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	for _, recipient := range recipients {
+		n.alertRecipient(ctx, recipient, result, kind, outage)
+	}
+}
+
+// alertRecipient доставляет уведомление одному получателю с учетом его отключений, шаблона и тихих часов
+func (n *Notifier) alertRecipient(ctx context.Context, recipient model.Recipient, result model.PingResult, kind model.AlertKind, outage time.Duration) {
+	const op = "ping.notifier.alertRecipient"
+
+	ping := result.Ping
+
+	// состояние отключения берем из базы, а не из закешированного списка ссылок,
+	// так как отключение может закончиться по времени между обновлениями списка
+	muted, err := n.muteChecker.IsMuted(ctx, ping.Id, recipient.UserId)
+	if err != nil {
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+
+	if muted {
+		return
+	}
+
+	// у чата нет своих настроек, для него используются шаблон и язык того, кто добавил ссылку
+	settingsUserId := recipient.UserId
+	if recipient.IsChat() {
+		settingsUserId = ping.UserId
+	}
+
+	settings := n.userSettings(ctx, settingsUserId)
+	loc := i18n.Resolve(settings.Language, "")
+	text := n.render(ctx, loc, settingsUserId, result, kind, outage)
+
+	// уведомления по некритичным ссылкам в тихие часы откладываем и отправляем одним сообщением после их окончания
+	if !recipient.IsChat() && !ping.Critical && settings.IsQuiet(time.Now()) {
+		errHold := n.digest.Hold(ctx, recipient.UserId, text)
+		if errHold == nil {
+			return
+		}
+
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, errHold))
+	}
+
+	msg := tgbotapi.NewMessage(recipient.ChatId, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	if kind != model.AlertRecovery {
+		msg.ReplyMarkup = command.AlertKeyboard(loc, ping.Id)
+	}
+
+	if err := n.bot.SendNotification(msg); err != nil {
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+}
+
+// render заполняет шаблон пользователя, при любой ошибке используется шаблон по умолчанию,
+// чтобы сломанный шаблон не привел к потере уведомления
+func (n *Notifier) render(ctx context.Context, loc i18n.Locale, userId int64, result model.PingResult, kind model.AlertKind, outage time.Duration) string {
+	const op = "ping.notifier.render"
+
+	cfg := n.kernel.Config()
+	data := alert.NewData(result, outage, cfg.FullApiPath())
+
+	body, err := n.templates.AlertTemplate(ctx, userId, model.ChannelTelegram, kind)
+	if err != nil {
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+
+	text, err := alert.Render(loc, kind, body, data)
+	if err == nil {
+		return text
+	}
+
+	n.kernel.Log().Error(fmt.Sprintf("%s, user: %d, error: %s", op, userId, err))
+	text, err = alert.Render(loc, kind, "", data)
+	if err == nil {
+		return text
+	}
+
+	// шаблон по умолчанию из каталога тоже не подошел, уведомление все равно должно дойти
+	n.kernel.Log().Error(fmt.Sprintf("%s, default template, error: %s", op, err))
+
+	return fmt.Sprintf("%s: %s", alert.KindName(loc, kind), data.Url)
+}
+
+// userSettings настройки доставки уведомлений пользователя, при ошибке - настройки по умолчанию без тихих часов
+func (n *Notifier) userSettings(ctx context.Context, userId int64) model.NotificationSettings {
+	const op = "ping.notifier.userSettings"
+
+	settings, err := n.settings.NotificationSettings(ctx, userId)
+	if err != nil {
+		n.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return model.NotificationSettings{Timezone: model.DefaultTimezone}
+	}
+
+	return settings
+}
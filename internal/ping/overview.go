@@ -0,0 +1,100 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"slices"
+	"strings"
+	"time"
+)
+
+// statusOrder порядок вывода ссылок в обзоре: сначала проблемные
+var statusOrder = map[model.MonitorStatus]int{
+	model.StatusDown:     0,
+	model.StatusDegraded: 1,
+	model.StatusPending:  2,
+	model.StatusPaused:   3,
+	model.StatusUp:       4,
+}
+
+type (
+	// WorkspaceUrlList Интерфейс реалезует возможность получить ссылки пространства
+	WorkspaceUrlList interface {
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+	}
+
+	// StateList Интерфейс реалезует возможность получить текущее состояние нескольких ссылок сразу
+	StateList interface {
+		MonitorStates(ctx context.Context, pingIds []int64) (map[int64]model.MonitorState, error)
+	}
+
+	// Overview текущее состояние всех ссылок пространства
+	Overview struct {
+		urls   WorkspaceUrlList
+		states StateList
+	}
+)
+
+func NewOverview(urls WorkspaceUrlList, states StateList) *Overview {
+	return &Overview{
+		urls:   urls,
+		states: states,
+	}
+}
+
+// StatusByWorkspace состояние ссылок пространства: сначала недоступные и работающие с проблемами,
+// внутри статуса - дольше всех находящиеся в нем
+func (o *Overview) StatusByWorkspace(ctx context.Context, workspaceId int64) (model.MonitorOverviewList, error) {
+	const op = "ping.overview.StatusByWorkspace"
+
+	list, err := o.urls.UrlListByWorkspace(workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids := make([]int64, 0, len(list))
+	for _, ping := range list {
+		ids = append(ids, ping.Id)
+	}
+
+	states, err := o.states.MonitorStates(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	overview := make(model.MonitorOverviewList, 0, len(list))
+	for _, ping := range list {
+		item := model.MonitorOverview{
+			MonitorState: model.MonitorState{PingId: ping.Id, Status: model.StatusPending},
+			Url:          ping.Url,
+			Critical:     ping.Critical,
+			Tags:         ping.Tags,
+		}
+
+		if state, ok := states[ping.Id]; ok {
+			item.MonitorState = state
+		}
+
+		if ping.Mute && (ping.MuteUntil == nil || ping.MuteUntil.After(now)) {
+			item.Status = model.StatusPaused
+		}
+
+		overview = append(overview, item)
+	}
+
+	slices.SortStableFunc(overview, func(a, b model.MonitorOverview) int {
+		if a.Status != b.Status {
+			return statusOrder[a.Status] - statusOrder[b.Status]
+		}
+
+		if c := a.Since.Compare(b.Since); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Url, b.Url)
+	})
+
+	return overview, nil
+}
@@ -3,32 +3,33 @@ package ping
 import (
 	"context"
 	"fmt"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/alert"
 	"github.com/ivankoTut/ping-url/internal/kernel"
 	"github.com/ivankoTut/ping-url/internal/model"
-	"github.com/ivankoTut/ping-url/internal/telegram"
 	"github.com/ivankoTut/ping-url/internal/telegram/command"
 	"github.com/ivankoTut/ping-url/internal/tracing"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
+	"net/http/httptrace"
 	"slices"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 )
 
-const defaultCompleteUrlItems = 1000
+const (
+	defaultCompleteUrlItems = 1000
+	urlListPageSize         = 100
+)
 
 // refreshCommandList список команд после которых необходимо обновить список ссылок
 var refreshCommandList = []string{
 	command.AddUrlCommand,
-	command.RemoveUrlCommand,
 	command.MuteAllCommand,
 	command.UnMuteAllCommand,
+	command.CriticalUrlCommand,
 }
 
 type (
@@ -43,31 +44,42 @@ type (
 		InsertRows(model.PingResultList) error
 	}
 
+	// monitor опрос одной ссылки по собственному таймеру
+	monitor struct {
+		ping     atomic.Pointer[model.Ping] // настройки не влияющие на таймер обновляются без перезапуска
+		interval time.Duration
+		quit     chan struct{}
+	}
+
 	Ping struct {
 		listProvider  UrlListProvider
+		notifier      *Notifier
+		states        StateStorage
+		incidents     IncidentRecorder
 		kernel        *kernel.Kernel
 		completeUrl   model.PingResultList
 		statisticRepo SaveUrlStatistic
 		countPing     int
-		bot           *telegram.Bot
 		rwm           sync.RWMutex
-		pingList      chan model.TimerPingList
-		pingQuit      chan struct{}
+		monitors      map[int64]*monitor
+		mm            sync.Mutex
 		saveUrlQuit   chan struct{}
+		failedInserts atomic.Int64 // неудачные записи в статистику с момента запуска
 	}
 )
 
 var tracer trace.Tracer
 
-func NewPing(listProvider UrlListProvider, k *kernel.Kernel, statisticRepo SaveUrlStatistic, bot *telegram.Bot) *Ping {
+func NewPing(listProvider UrlListProvider, k *kernel.Kernel, statisticRepo SaveUrlStatistic, notifier *Notifier, states StateStorage, incidents IncidentRecorder) *Ping {
 	return &Ping{
 		listProvider:  listProvider,
+		notifier:      notifier,
+		states:        states,
+		incidents:     incidents,
 		statisticRepo: statisticRepo,
 		kernel:        k,
-		bot:           bot,
 		completeUrl:   newCompleteList(),
-		pingList:      make(chan model.TimerPingList),
-		pingQuit:      make(chan struct{}),
+		monitors:      make(map[int64]*monitor),
 		saveUrlQuit:   make(chan struct{}),
 	}
 }
@@ -83,102 +95,225 @@ func (p *Ping) Run() {
 
 	tracer = tp.Tracer("ping")
 
-	go func() {
-		timerList, err := p.listProvider.UrlList(100, 0)
-		if err != nil {
-			log.Fatal(err)
-		}
-		p.countPing, err = p.listProvider.Count()
+	list, err := p.urlList()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		if err != nil {
-			p.kernel.Log().Info(fmt.Sprintf("%s, ошибка в получении кол-ва записей: %s", op, err))
-		}
+	p.countPing, err = p.listProvider.Count()
+	if err != nil {
+		p.kernel.Log().Info(fmt.Sprintf("%s, ошибка в получении кол-ва записей: %s", op, err))
+	}
 
-		p.pingList <- timerList
-	}()
+	p.sync(list)
+}
 
-	for {
-		for pingTime, list := range <-p.pingList {
+// Reschedule применяет новые настройки ссылки, при смене периодичности перезапускается таймер только этой ссылки
+func (p *Ping) Reschedule(ping model.Ping) {
+	p.mm.Lock()
+	defer p.mm.Unlock()
 
-			p.kernel.Log().Info(fmt.Sprintf("-------- start %s(%d)-------", pingTime, len(list)))
+	p.schedule(ping)
+}
 
-			ctx, span := tracer.Start(context.Background(), "start timer")
-			span.SetAttributes(attribute.String("Ping time", pingTime), attribute.Int("Count records", len(list)))
+// Unschedule останавливает опрос удаленной ссылки, не дожидаясь обновления списка ссылок
+func (p *Ping) Unschedule(pingId int64) {
+	p.mm.Lock()
+	defer p.mm.Unlock()
 
-			go p.startTicker(ctx, pingTime, list)
+	if m, ok := p.monitors[pingId]; ok {
+		close(m.quit)
+		delete(p.monitors, pingId)
+	}
+}
+
+// Refresh перечитывает список ссылок и запускает опрос новых, например после импорта
+func (p *Ping) Refresh() {
+	p.refreshPingList(true)
+}
 
-			span.End()
+// sync приводит запущенные опросы в соответствие со списком ссылок: новые запускаются, удаленные останавливаются
+func (p *Ping) sync(list model.PingList) {
+	_, span := tracer.Start(context.Background(), "sync timers")
+	defer span.End()
+	span.SetAttributes(attribute.Int("Count records", len(list)))
+
+	p.mm.Lock()
+	defer p.mm.Unlock()
+
+	actual := make(map[int64]struct{}, len(list))
+	for _, ping := range list {
+		actual[ping.Id] = struct{}{}
+		p.schedule(ping)
+	}
+
+	for id, m := range p.monitors {
+		if _, ok := actual[id]; !ok {
+			close(m.quit)
+			delete(p.monitors, id)
 		}
 	}
 }
 
-func (p *Ping) startTicker(ctx context.Context, pingTime string, list model.PingList) {
-	_, span := tracer.Start(ctx, fmt.Sprintf("start for timer: %s", pingTime))
-	span.End()
+// schedule запускает опрос ссылки или обновляет настройки уже запущенного, вызывается под p.mm
+func (p *Ping) schedule(ping model.Ping) {
+	interval := p.interval(ping)
 
-	timer, err := time.ParseDuration(pingTime)
-	if err != nil {
-		timer = time.Duration(p.kernel.Config().DefaultTimePing) * time.Second
-		p.kernel.Log().Info(fmt.Sprintf("ParseDuration ERROR: %s | SET default duration %d", err, p.kernel.Config().DefaultTimePing))
+	if m, ok := p.monitors[ping.Id]; ok {
+		if m.interval == interval {
+			m.ping.Store(&ping)
+			return
+		}
+
+		close(m.quit)
 	}
 
-	ticker := time.NewTicker(timer)
+	m := &monitor{interval: interval, quit: make(chan struct{})}
+	m.ping.Store(&ping)
+	p.monitors[ping.Id] = m
+
+	go p.startTicker(m)
+}
+
+func (p *Ping) startTicker(m *monitor) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			for _, ping := range list {
-				go p.ping(ping)
-			}
-		case <-p.pingQuit:
-			ticker.Stop()
+			go p.ping(*m.ping.Load())
+		case <-m.quit:
 			return
 		}
 	}
 }
 
+func (p *Ping) interval(ping model.Ping) time.Duration {
+	timer, err := time.ParseDuration(ping.PingTime)
+	if err != nil {
+		timer = time.Duration(p.kernel.Config().DefaultTimePing) * time.Second
+		p.kernel.Log().Info(fmt.Sprintf("ParseDuration ERROR: %s | SET default duration %d", err, p.kernel.Config().DefaultTimePing))
+	}
+
+	return timer
+}
+
+// urlList все ссылки для опроса, загружаются постранично
+func (p *Ping) urlList() (model.PingList, error) {
+	var list model.PingList
+	for offset := 0; ; offset += urlListPageSize {
+		timerList, err := p.listProvider.UrlList(urlListPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		count := 0
+		for _, pings := range timerList {
+			list = append(list, pings...)
+			count += len(pings)
+		}
+
+		if count < urlListPageSize {
+			return list, nil
+		}
+	}
+}
+
 func (p *Ping) ping(ping model.Ping) {
-	start := time.Now()
+	ctx := context.Background()
+
 	connectionTimeout, err := time.ParseDuration(ping.ConnectionTime)
 	if err != nil {
-		p.sendErrorMessageInBot(ping, err)
+		p.track(ctx, model.PingResult{Ping: ping, Error: err})
 		return
 	}
+
+	result := p.request(ctx, ping, connectionTimeout)
+	p.track(ctx, result)
+	p.addCompleteUrl(result)
+}
+
+// Check опрашивает ссылку вне расписания тем же запросом, что и по расписанию: результат сразу сохраняется
+// в статистику с отметкой ручного опроса, состояние ссылки обновляется, при его смене отправляются уведомления
+func (p *Ping) Check(ctx context.Context, ping model.Ping) model.CheckResult {
+	const op = "ping.ping.Check"
+
+	var result model.PingResult
+	if connectionTimeout, err := time.ParseDuration(ping.ConnectionTime); err != nil {
+		result = model.PingResult{Ping: ping, Error: err}
+	} else {
+		result = p.request(ctx, ping, connectionTimeout)
+	}
+
+	result.IsManual = true
+	p.track(ctx, result)
+
+	if err := p.statisticRepo.InsertRows(model.PingResultList{result}); err != nil {
+		p.failedInserts.Add(1)
+		p.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+
+	check := model.CheckResult{
+		PingId:     ping.Id,
+		Url:        ping.Url,
+		Status:     alert.Status(result),
+		StatusCode: result.StatusCode,
+		Latency:    result.RealConnectionTime,
+		Timings:    result.Timings,
+		Headers:    result.Headers,
+		CheckedAt:  time.Now(),
+	}
+
+	if result.Error != nil {
+		check.Error = result.Error.Error()
+	}
+
+	return check
+}
+
+func (p *Ping) request(ctx context.Context, ping model.Ping, connectionTimeout time.Duration) model.PingResult {
+	start := time.Now()
 	client := &http.Client{Timeout: connectionTimeout}
 
-	res, err := client.Get(ping.Url)
+	trace := &probeTrace{start: start}
+	ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ping.Url, nil)
 	if err != nil {
-		p.sendErrorMessageInBot(ping, err)
-		p.addCompleteUrl(ping, err, 504, time.Since(start).Seconds(), true)
-		return
+		return newPingResult(ping, err, 0, 0, true)
 	}
-	defer res.Body.Close()
 
-	p.addCompleteUrl(ping, nil, res.StatusCode, time.Since(start).Seconds(), false)
-}
+	res, err := client.Do(req)
+	if err != nil {
+		result := newPingResult(ping, err, 504, time.Since(start).Seconds(), true)
+		result.Timings = trace.timings()
 
-func (p *Ping) addCompleteUrl(ping model.Ping, requestError error, statusCode int, realTime float64, isCancel bool) {
-	r := model.PingResult{
-		Ping:               ping,
-		Error:              requestError,
-		RealConnectionTime: realTime,
-		StatusCode:         statusCode,
-		IsCancel:           isCancel,
+		return result
+	}
+	defer res.Body.Close()
+
+	result := newPingResult(ping, nil, res.StatusCode, time.Since(start).Seconds(), false)
+	result.Timings = trace.timings()
+	result.Headers = make(map[string]string, len(model.ProbeHeaders))
+	for _, name := range model.ProbeHeaders {
+		if value := res.Header.Get(name); value != "" {
+			result.Headers[name] = value
+		}
 	}
 
+	return result
+}
+
+func (p *Ping) addCompleteUrl(r model.PingResult) {
 	p.rwm.Lock()
 	p.completeUrl = append(p.completeUrl, r)
 	p.rwm.Unlock()
 }
 
+// SaveCompleteUrl периодически записывает результаты опросов в статистику,
+// после Stop записывает оставшиеся результаты и возвращает управление
 func (p *Ping) SaveCompleteUrl() {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		p.saveUrlQuit <- struct{}{}
-	}()
-
 	for {
 		select {
 		case <-time.After(time.Second * 30):
@@ -191,6 +326,11 @@ func (p *Ping) SaveCompleteUrl() {
 	}
 }
 
+// Stop останавливает SaveCompleteUrl, перед остановкой накопленные результаты опросов записываются
+func (p *Ping) Stop() {
+	p.saveUrlQuit <- struct{}{}
+}
+
 func (p *Ping) ListenCommandEvents(commandEvent <-chan model.CommandEvent) {
 	for {
 		event := <-commandEvent
@@ -216,15 +356,13 @@ func (p *Ping) refreshPingList(force bool) {
 		return
 	}
 
-	timerList, err := p.listProvider.UrlList(100, 0)
+	list, err := p.urlList()
 	if err != nil {
 		log.Fatalf("%s, error: %s", op, err)
 	}
 
-	p.stopPing()
-
+	p.sync(list)
 	p.countPing = count
-	p.pingList <- timerList
 }
 
 func (p *Ping) startInserting() {
@@ -238,12 +376,30 @@ func (p *Ping) startInserting() {
 
 	err := p.statisticRepo.InsertRows(rows)
 	if err != nil {
+		p.failedInserts.Add(1)
 		p.kernel.Log().Error(fmt.Sprintf("ошибка при вставке в кликхаус: %s", err))
 	} else {
 		p.kernel.Log().Info(fmt.Sprintf("данные успешно вставлены, строк: %d", countRows))
 	}
 }
 
+// Stats состояние опроса для администратора
+func (p *Ping) Stats() model.SchedulerStats {
+	p.mm.Lock()
+	monitors := len(p.monitors)
+	p.mm.Unlock()
+
+	p.rwm.RLock()
+	pending := len(p.completeUrl)
+	p.rwm.RUnlock()
+
+	return model.SchedulerStats{
+		Monitors:      monitors,
+		PendingRows:   pending,
+		FailedInserts: p.failedInserts.Load(),
+	}
+}
+
 func (p *Ping) withdraw() model.PingResultList {
 	p.rwm.Lock()
 	defer p.rwm.Unlock()
@@ -254,24 +410,20 @@ func (p *Ping) withdraw() model.PingResultList {
 	return urls
 }
 
-func (p *Ping) sendErrorMessageInBot(ping model.Ping, err error) {
-	if ping.User.Mute {
-		return
-	}
-
-	msg := tgbotapi.NewMessage(ping.UserId, fmt.Sprintf("<code>⚠️%s</code> \n \n <u>%s</u>", ping.Url, err))
-	msg.ParseMode = tgbotapi.ModeHTML
-	p.bot.SendMessage(msg)
-}
-
-func (p *Ping) stopPing() {
-	p.pingQuit <- struct{}{}
-}
-
 func (p *Ping) isRefreshEvent(event model.CommandEvent) bool {
 	return slices.Contains(refreshCommandList, event.Command)
 }
 
+func newPingResult(ping model.Ping, requestError error, statusCode int, realTime float64, isCancel bool) model.PingResult {
+	return model.PingResult{
+		Ping:               ping,
+		Error:              requestError,
+		RealConnectionTime: realTime,
+		StatusCode:         statusCode,
+		IsCancel:           isCancel,
+	}
+}
+
 func newCompleteList() model.PingResultList {
 	return make(model.PingResultList, 0, defaultCompleteUrlItems)
 }
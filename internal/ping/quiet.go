@@ -0,0 +1,114 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/telegram"
+	"strings"
+	"time"
+)
+
+const (
+	digestInterval   = time.Minute // как часто проверять закончились ли тихие часы
+	digestMaxTextLen = 3500        // ограничение длины сводки, у телеграма лимит 4096 символов на сообщение
+)
+
+type (
+	// NotificationSettingsProvider Интерфейс реалезует возможность получить настройки доставки уведомлений пользователя
+	NotificationSettingsProvider interface {
+		NotificationSettings(ctx context.Context, userId int64) (model.NotificationSettings, error)
+	}
+
+	// DigestProvider Интерфейс реалезует возможность получить отложенные на время тихих часов уведомления
+	DigestProvider interface {
+		Users(ctx context.Context) ([]int64, error)
+		Withdraw(ctx context.Context, userId int64) ([]string, error)
+	}
+
+	// DigestWatcher отправляет пользователю сводку отложенных уведомлений, когда у него заканчиваются тихие часы
+	DigestWatcher struct {
+		settings NotificationSettingsProvider
+		digest   DigestProvider
+		kernel   *kernel.Kernel
+		bot      *telegram.Bot
+	}
+)
+
+func NewDigestWatcher(settings NotificationSettingsProvider, digest DigestProvider, k *kernel.Kernel, bot *telegram.Bot) *DigestWatcher {
+	return &DigestWatcher{
+		settings: settings,
+		digest:   digest,
+		kernel:   k,
+		bot:      bot,
+	}
+}
+
+func (d *DigestWatcher) Run() {
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		d.deliver()
+	}
+}
+
+func (d *DigestWatcher) deliver() {
+	const op = "ping.quiet.deliver"
+	ctx := context.Background()
+
+	users, err := d.digest.Users(ctx)
+	if err != nil {
+		d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	for _, userId := range users {
+		settings, err := d.settings.NotificationSettings(ctx, userId)
+		if err != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+			continue
+		}
+
+		if settings.IsQuiet(time.Now()) {
+			continue
+		}
+
+		list, err := d.digest.Withdraw(ctx, userId)
+		if err != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+			continue
+		}
+
+		if len(list) == 0 {
+			continue
+		}
+
+		msg := tgbotapi.NewMessage(userId, digestText(i18n.Resolve(settings.Language, ""), list))
+		msg.ParseMode = tgbotapi.ModeHTML
+
+		if err := d.bot.SendNotification(msg); err != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		}
+	}
+}
+
+func digestText(loc i18n.Locale, list []string) string {
+	str := strings.Builder{}
+	str.WriteString(loc.Plural("digest.title", len(list)))
+
+	for i, text := range list {
+		if str.Len()+len(text) > digestMaxTextLen {
+			str.WriteString(loc.Plural("digest.more", len(list)-i))
+			break
+		}
+
+		str.WriteString(text)
+		str.WriteString("\n\n")
+	}
+
+	return str.String()
+}
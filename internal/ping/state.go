@@ -0,0 +1,113 @@
+package ping
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/alert"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"time"
+)
+
+// StateStorage Интерфейс реалезует хранение текущего состояния ссылок
+type StateStorage interface {
+	MonitorState(ctx context.Context, pingId int64) (model.MonitorState, bool, error)
+	SaveMonitorState(ctx context.Context, state model.MonitorState) error
+}
+
+// track обновляет состояние ссылки по результату опроса и отправляет уведомление если состояние изменилось.
+// Пока ссылка не работает или работает с проблемами, уведомление повторяется с интервалом ссылки, см. model.Ping.RepeatInterval
+func (p *Ping) track(ctx context.Context, result model.PingResult) {
+	const op = "ping.state.track"
+
+	now := time.Now()
+	prev, exist, err := p.states.MonitorState(ctx, result.Ping.Id)
+	if err != nil {
+		p.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+
+	state := model.MonitorState{
+		PingId:         result.Ping.Id,
+		Status:         alert.Status(result),
+		Since:          now,
+		LastCheck:      now,
+		LastLatency:    result.RealConnectionTime,
+		LastStatusCode: result.StatusCode,
+		LastAlert:      now,
+	}
+
+	if result.Error != nil {
+		state.LastError = result.Error.Error()
+	}
+
+	changed := !exist || prev.Status != state.Status
+	repeat := !changed && p.isRepeatAlert(result.Ping, prev, now)
+	if !changed {
+		state.Since = prev.Since
+	}
+
+	if !changed && !repeat {
+		state.LastAlert = prev.LastAlert
+	}
+
+	if err := p.states.SaveMonitorState(ctx, state); err != nil {
+		p.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+	}
+
+	if repeat {
+		// повторное уведомление, Duration - сколько ссылка уже не работает
+		p.notifier.Alert(ctx, result, alertKind(state.Status), now.Sub(prev.Since))
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	// неизвестное состояние считаем рабочим: первый неудачный опрос открывает инцидент
+	wasUp := !exist || prev.Status == model.StatusUp
+	p.recordIncident(ctx, state, wasUp)
+
+	// первый успешный опрос новой ссылки не является восстановлением
+	if !exist && state.Status == model.StatusUp {
+		return
+	}
+
+	// длительность нужна только когда закончилась проблема, время работы до сбоя в уведомление не попадает
+	var outage time.Duration
+	if !wasUp {
+		outage = now.Sub(prev.Since)
+	}
+
+	p.notifier.Alert(ctx, result, alertKind(state.Status), outage)
+}
+
+// isRepeatAlert ссылка по-прежнему не работает и с последнего уведомления прошел интервал повтора
+func (p *Ping) isRepeatAlert(ping model.Ping, prev model.MonitorState, now time.Time) bool {
+	if !prev.Status.IsProblem() {
+		return false
+	}
+
+	interval := ping.RepeatInterval(p.kernel.Config().Notification.RepeatAlert)
+	if interval <= 0 {
+		return false
+	}
+
+	// состояние сохраненное до появления повторов: отсчитываем от начала проблемы
+	last := prev.LastAlert
+	if last.IsZero() {
+		last = prev.Since
+	}
+
+	return now.Sub(last) >= interval
+}
+
+func alertKind(status model.MonitorStatus) model.AlertKind {
+	switch status {
+	case model.StatusDown:
+		return model.AlertDown
+	case model.StatusDegraded:
+		return model.AlertDegraded
+	default:
+		return model.AlertRecovery
+	}
+}
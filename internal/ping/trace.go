@@ -0,0 +1,53 @@
+package ping
+
+import (
+	"crypto/tls"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// probeTrace замеряет время этапов запроса, обработчики httptrace могут вызываться из горутин транспорта
+// и после завершения запроса (например при параллельном подключении к нескольким адресам), поэтому под мьютексом
+type probeTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	result       model.ProbeTimings
+}
+
+func (t *probeTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.measure(&t.result.Dns, &t.dnsStart) },
+		ConnectStart:         func(string, string) { t.mark(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.measure(&t.result.Connect, &t.connectStart) },
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.measure(&t.result.Tls, &t.tlsStart) },
+		GotFirstResponseByte: func() { t.measure(&t.result.FirstByte, &t.start) },
+	}
+}
+
+func (t *probeTrace) timings() model.ProbeTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.result
+}
+
+func (t *probeTrace) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*at = time.Now()
+}
+
+func (t *probeTrace) measure(value *float64, from *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*value = time.Since(*from).Seconds()
+}
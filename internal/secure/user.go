@@ -1,22 +1,323 @@
 package secure
 
 import (
-	"github.com/ivankoTut/ping-url/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
 	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
-type UserProvider struct {
-	cfg *config.Config
+// accessReloadInterval как часто перечитывать доступ из бд, чтобы подхватить изменения сделанные в обход бота
+const accessReloadInterval = time.Minute
+
+type (
+	// AdminRepository этот интерфейс реализует возможность хранить администраторов и блокировки пользователей
+	AdminRepository interface {
+		SyncAdmins(ctx context.Context, userIds []int64) error
+		BlockedUserIds(ctx context.Context) ([]int64, error)
+		SetBlocked(ctx context.Context, userId int64, blocked bool) error
+	}
+
+	// AccessRepository этот интерфейс реализует возможность хранить режим доступа, список доступа и коды приглашений
+	AccessRepository interface {
+		InitAccess(ctx context.Context, mode model.AccessMode, userIds []int64) error
+		AccessMode(ctx context.Context) (model.AccessMode, error)
+		SetAccessMode(ctx context.Context, mode model.AccessMode) error
+		AllowList(ctx context.Context) ([]int64, error)
+		Allow(ctx context.Context, userId int64) error
+		Disallow(ctx context.Context, userId int64) error
+		RedeemAccessCode(ctx context.Context, userId int64, code string) error
+	}
+
+	// WorkspaceInviteChecker этот интерфейс реализует возможность проверить приглашение в пространство
+	WorkspaceInviteChecker interface {
+		InviteExist(ctx context.Context, token string) (bool, error)
+	}
+
+	// UserProvider проверяет доступ к боту: режим доступа, список доступа, администраторы и блокировки.
+	// Доступ хранится в бд, а проверяется по копии в памяти, потому что проверка идет на каждое обновление.
+	// Изменения через бота применяются сразу, изменения в бд напрямую - в течение accessReloadInterval
+	UserProvider struct {
+		kernel  *kernel.Kernel
+		users   AdminRepository
+		access  AccessRepository
+		invites WorkspaceInviteChecker
+		mu      sync.RWMutex
+		mode    model.AccessMode
+		allowed map[int64]struct{}
+		blocked map[int64]struct{}
+	}
+)
+
+func NewUserProvider(k *kernel.Kernel, users AdminRepository, access AccessRepository, invites WorkspaceInviteChecker) *UserProvider {
+	return &UserProvider{
+		kernel:  k,
+		users:   users,
+		access:  access,
+		invites: invites,
+		mode:    model.AccessInvite,
+		allowed: make(map[int64]struct{}),
+		blocked: make(map[int64]struct{}),
+	}
+}
+
+// Load сохраняет администраторов из конфига в бд и загружает доступ, вызывается при запуске.
+// При первом запуске access_user_list из конфига переносится в бд: непустой список включает режим allowlist,
+// иначе включается режим invite
+func (u *UserProvider) Load(ctx context.Context) error {
+	const op = "secure.UserProvider.Load"
+
+	cfg := u.kernel.Config()
+	if err := u.users.SyncAdmins(ctx, cfg.AdminUserList); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	mode := model.AccessInvite
+	if len(cfg.AccessUserList) > 0 {
+		mode = model.AccessAllowlist
+	}
+
+	if err := u.access.InitAccess(ctx, mode, cfg.AccessUserList); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := u.reload(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Run периодически перечитывает доступ из бд
+func (u *UserProvider) Run() {
+	const op = "secure.UserProvider.Run"
+
+	ticker := time.NewTicker(accessReloadInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := u.reload(context.Background()); err != nil {
+			u.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		}
+	}
 }
 
-func NewUserProvider(cfg *config.Config) *UserProvider {
-	return &UserProvider{cfg: cfg}
+func (u *UserProvider) reload(ctx context.Context) error {
+	mode, err := u.access.AccessMode(ctx)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := u.access.AllowList(ctx)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := u.users.BlockedUserIds(ctx)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.mode = mode
+	u.allowed = idSet(allowed)
+	u.blocked = idSet(blocked)
+
+	return nil
 }
 
+// IsAccess у администратора доступ есть всегда, у заблокированного пользователя - никогда.
+// В режиме invite писать боту может любой, без кода приглашения нельзя только зарегистрироваться, см. Admit
 func (u *UserProvider) IsAccess(userId int64) bool {
-	if len(u.cfg.AccessUserList) == 0 {
+	if u.IsAdmin(userId) {
 		return true
 	}
 
-	return slices.Contains(u.cfg.AccessUserList, userId)
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if _, ok := u.blocked[userId]; ok {
+		return false
+	}
+
+	if u.mode == model.AccessAllowlist {
+		_, ok := u.allowed[userId]
+		return ok
+	}
+
+	return true
+}
+
+// IsAdmin администраторы задаются в конфиге
+func (u *UserProvider) IsAdmin(userId int64) bool {
+	return slices.Contains(u.kernel.Config().AdminUserList, userId)
+}
+
+func (u *UserProvider) IsBlocked(userId int64) bool {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	_, ok := u.blocked[userId]
+
+	return ok
+}
+
+// Block закрывает пользователю доступ к боту и апи, администратора заблокировать нельзя
+func (u *UserProvider) Block(ctx context.Context, userId int64) error {
+	const op = "secure.UserProvider.Block"
+
+	if u.IsAdmin(userId) {
+		return i18n.NewError("admin.error_block_admin", userId)
+	}
+
+	if err := u.users.SetBlocked(ctx, userId, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.mu.Lock()
+	u.blocked[userId] = struct{}{}
+	u.mu.Unlock()
+
+	return nil
+}
+
+func (u *UserProvider) Unblock(ctx context.Context, userId int64) error {
+	const op = "secure.UserProvider.Unblock"
+
+	if err := u.users.SetBlocked(ctx, userId, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.mu.Lock()
+	delete(u.blocked, userId)
+	u.mu.Unlock()
+
+	return nil
+}
+
+func (u *UserProvider) AccessMode() model.AccessMode {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.mode
+}
+
+func (u *UserProvider) SetAccessMode(ctx context.Context, mode model.AccessMode) error {
+	const op = "secure.UserProvider.SetAccessMode"
+
+	if !mode.IsValid() {
+		return i18n.NewError("access.error_mode", mode)
+	}
+
+	if err := u.access.SetAccessMode(ctx, mode); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.mu.Lock()
+	u.mode = mode
+	u.mu.Unlock()
+
+	return nil
+}
+
+// AllowedCount кол-во пользователей в списке доступа
+func (u *UserProvider) AllowedCount() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return len(u.allowed)
+}
+
+// Allow добавляет пользователя телеграма в список доступа, регистрироваться ему для этого не нужно
+func (u *UserProvider) Allow(ctx context.Context, userId int64) error {
+	const op = "secure.UserProvider.Allow"
+
+	if err := u.access.Allow(ctx, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.mu.Lock()
+	u.allowed[userId] = struct{}{}
+	u.mu.Unlock()
+
+	return nil
+}
+
+func (u *UserProvider) Disallow(ctx context.Context, userId int64) error {
+	const op = "secure.UserProvider.Disallow"
+
+	if err := u.access.Disallow(ctx, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	u.mu.Lock()
+	delete(u.allowed, userId)
+	u.mu.Unlock()
+
+	return nil
+}
+
+// Admit проверяет можно ли зарегистрироваться новому пользователю с переданным кодом из /start <code>.
+// Код приглашения используется в любом режиме и добавляет пользователя в список доступа, тогда возвращается true.
+// Действующее приглашение в пространство в режиме invite тоже дает право регистрации и добавляет в список доступа,
+// но возвращается false: само приглашение принимает регистрация после сохранения пользователя
+func (u *UserProvider) Admit(ctx context.Context, userId int64, code string) (bool, error) {
+	const op = "secure.UserProvider.Admit"
+
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code != "" {
+		err := u.access.RedeemAccessCode(ctx, userId, code)
+		if err == nil {
+			u.mu.Lock()
+			u.allowed[userId] = struct{}{}
+			u.mu.Unlock()
+
+			return true, nil
+		}
+
+		if !errors.Is(err, storage.ErrInviteNotFound) {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if u.AccessMode() != model.AccessInvite || u.IsAdmin(userId) {
+		return false, nil
+	}
+
+	if code == "" {
+		return false, i18n.NewError("access.invite_required")
+	}
+
+	exist, err := u.invites.InviteExist(ctx, code)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exist {
+		return false, i18n.NewError("access.invite_invalid")
+	}
+
+	if err := u.Allow(ctx, userId); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return false, nil
+}
+
+func idSet(ids []int64) map[int64]struct{} {
+	set := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	return set
 }
@@ -0,0 +1,162 @@
+package secure
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ivankoTut/ping-url/internal/config"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type (
+	fakeConnection struct{}
+
+	// fakeStore хранит доступ в памяти вместо бд
+	fakeStore struct {
+		mode    model.AccessMode
+		allowed []int64
+		blocked []int64
+	}
+
+	// fakeKeys пользователь по api ключу, ключ - id пользователя, ключи не удаляются при закрытии доступа
+	fakeKeys struct{}
+)
+
+func (fakeConnection) MustRunMigrations()     {}
+func (fakeConnection) DB() *sql.DB            { return nil }
+func (fakeConnection) ConnectionName() string { return "fake" }
+
+func (s *fakeStore) SyncAdmins(ctx context.Context, userIds []int64) error { return nil }
+func (s *fakeStore) BlockedUserIds(ctx context.Context) ([]int64, error)   { return s.blocked, nil }
+
+func (s *fakeStore) SetBlocked(ctx context.Context, userId int64, blocked bool) error {
+	s.blocked = slices.DeleteFunc(s.blocked, func(id int64) bool { return id == userId })
+	if blocked {
+		s.blocked = append(s.blocked, userId)
+	}
+
+	return nil
+}
+
+func (s *fakeStore) InitAccess(ctx context.Context, mode model.AccessMode, userIds []int64) error {
+	s.mode, s.allowed = mode, userIds
+	return nil
+}
+
+func (s *fakeStore) AccessMode(ctx context.Context) (model.AccessMode, error) { return s.mode, nil }
+
+func (s *fakeStore) SetAccessMode(ctx context.Context, mode model.AccessMode) error {
+	s.mode = mode
+	return nil
+}
+
+func (s *fakeStore) AllowList(ctx context.Context) ([]int64, error) { return s.allowed, nil }
+
+func (s *fakeStore) Allow(ctx context.Context, userId int64) error {
+	s.allowed = append(s.allowed, userId)
+	return nil
+}
+
+func (s *fakeStore) Disallow(ctx context.Context, userId int64) error {
+	s.allowed = slices.DeleteFunc(s.allowed, func(id int64) bool { return id == userId })
+	return nil
+}
+
+func (s *fakeStore) RedeemAccessCode(ctx context.Context, userId int64, code string) error {
+	return nil
+}
+
+func (fakeKeys) UserFromRequest(r *http.Request) (*model.User, error) {
+	id, err := strconv.ParseInt(r.Header.Get("X-Api-Key"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.User{Id: id}, nil
+}
+
+// TestApiAccess ключ апи работает только пока у пользователя есть доступ к боту
+func TestApiAccess(t *testing.T) {
+	const (
+		admin  = int64(1)
+		member = int64(2)
+		other  = int64(3)
+	)
+
+	tests := []struct {
+		name   string
+		list   []int64 // access_user_list из конфига, пустой - режим invite
+		change func(ctx context.Context, u *UserProvider) error
+		user   int64
+		want   int
+	}{
+		{name: "пользователь из списка доступа", list: []int64{member}, user: member, want: http.StatusOK},
+		{name: "пользователь вне списка доступа", list: []int64{member}, user: other, want: http.StatusForbidden},
+		{name: "администратор вне списка доступа", list: []int64{member}, user: admin, want: http.StatusOK},
+		{
+			name: "пользователь удален из списка доступа",
+			list: []int64{member},
+			change: func(ctx context.Context, u *UserProvider) error {
+				return u.Disallow(ctx, member)
+			},
+			user: member,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "включен режим списка доступа",
+			change: func(ctx context.Context, u *UserProvider) error {
+				return u.SetAccessMode(ctx, model.AccessAllowlist)
+			},
+			user: other,
+			want: http.StatusForbidden,
+		},
+		{name: "режим invite", user: other, want: http.StatusOK},
+		{
+			name: "пользователь заблокирован",
+			change: func(ctx context.Context, u *UserProvider) error {
+				return u.Block(ctx, other)
+			},
+			user: other,
+			want: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k := kernel.MustCreateKernel(&config.Config{Env: "local", AdminUserList: []int64{admin}, AccessUserList: tt.list}, fakeConnection{}, nil)
+			store := &fakeStore{}
+
+			u := NewUserProvider(k, store, store, nil)
+			if err := u.Load(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.change != nil {
+				if err := tt.change(ctx, u); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler := authorize.ApiAuth(fakeKeys{}, u)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			r.Header.Set("X-Api-Key", strconv.FormatInt(tt.user, 10))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("статус %d, ожидался %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// NewBlock закрывает пользователю доступ к боту и апи
+func NewBlock(log *slog.Logger, blocker command.UserBlocker) http.HandlerFunc {
+	return newBlock(log, "server.handlers.admin.block", blocker.Block)
+}
+
+// NewUnblock возвращает пользователю доступ к боту и апи
+func NewUnblock(log *slog.Logger, blocker command.UserBlocker) http.HandlerFunc {
+	return newBlock(log, "server.handlers.admin.unblock", blocker.Unblock)
+}
+
+func newBlock(log *slog.Logger, op string, action func(ctx context.Context, userId int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			errorMessage    = "api.admin_block_error"
+			notFoundMessage = "api.admin_user_not_found"
+		)
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		err = action(r.Context(), userId)
+		var userErr *i18n.Error
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		case errors.As(err, &userErr):
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.Error(err))
+			return
+		case err != nil:
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		log.Info(fmt.Sprintf("target user_id: %d, admin user_id: %d", userId, user.Id))
+
+		render.Status(r, http.StatusNoContent)
+		render.JSON(w, r, "")
+	}
+}
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+)
+
+type (
+	broadcastRequest struct {
+		Text string `json:"text"`
+	}
+
+	broadcastResponse struct {
+		Recipients int `json:"recipients"`
+	}
+)
+
+// NewBroadcast отправляет объявление всем незаблокированным пользователям: {"text": "..."}
+func NewBroadcast(log *slog.Logger, broadcaster command.Broadcaster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.admin.broadcast"
+			errorMessage = "api.admin_broadcast_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		var req broadcastRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			return
+		}
+
+		count, err := broadcaster.Broadcast(r.Context(), req.Text)
+		var userErr *i18n.Error
+		if errors.As(err, &userErr) {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.Error(err))
+			return
+		}
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		log.Info(fmt.Sprintf("broadcast to %d users, admin user_id: %d", count, user.Id))
+
+		render.JSON(w, r, broadcastResponse{Recipients: count})
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+)
+
+// NewStats состояние приложения: опрос ссылок, очередь исходящих сообщений, горутины
+func NewStats(log *slog.Logger, runtime command.RuntimeStatsProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.admin.stats"
+			errorMessage = "api.admin_stats_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		stats, err := runtime.Stats(r.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		render.JSON(w, r, stats)
+	}
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+)
+
+// NewUsers все пользователи бота с кол-вом добавленных ими ссылок
+func NewUsers(log *slog.Logger, users command.AdminUserProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.admin.users"
+			errorMessage = "api.admin_users_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		list, err := users.AdminUserList(r.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		if list == nil {
+			list = model.AdminUserList{}
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		log.Info(fmt.Sprintf("admin user list, user_id: %d", user.Id))
+
+		render.JSON(w, r, list)
+	}
+}
@@ -0,0 +1,60 @@
+package incident
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+func NewGet(log *slog.Logger, incidentRepo command.IncidentProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op              = "server.handlers.incident.get"
+			errorMessage    = "api.incident_error"
+			notFoundMessage = "api.incident_not_found"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		incident, err := incidentRepo.IncidentById(r.Context(), user.Workspace.WorkspaceId, id)
+
+		if errors.Is(err, storage.ErrIncidentNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("show incident id: %d user_id: %d", id, user.Id))
+
+		render.JSON(w, r, incident)
+	}
+}
@@ -0,0 +1,60 @@
+package incident
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+func NewList(log *slog.Logger, incidentRepo command.IncidentProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.incident.list"
+			errorMessage = "api.incident_list_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		limit := defaultLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 || parsed > maxLimit {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, loc.T("api.invalid_limit", maxLimit))
+				return
+			}
+
+			limit = parsed
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		list, err := incidentRepo.IncidentList(r.Context(), user.Workspace.WorkspaceId, limit)
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("show incident list user_id: %d", user.Id))
+
+		render.JSON(w, r, list)
+	}
+}
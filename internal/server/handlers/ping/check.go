@@ -0,0 +1,69 @@
+package ping
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type (
+	// PingProvider этот интерфейс реализует возможность получить ссылку по идентификатору
+	PingProvider interface {
+		PingById(id int64) (model.Ping, error)
+	}
+)
+
+// NewCheck опрашивает ссылку вне расписания и возвращает полный результат опроса
+func NewCheck(log *slog.Logger, pingRepo PingProvider, checker command.UrlChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op              = "server.handlers.ping.check"
+			errorMessage    = "api.ping_check_error"
+			notFoundMessage = "api.url_not_found"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		ping, err := pingRepo.PingById(id)
+		if errors.Is(err, storage.ErrUrlNotFound) || (err == nil && ping.WorkspaceId != user.Workspace.WorkspaceId) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		result := checker.Check(r.Context(), ping)
+
+		log.Info(fmt.Sprintf("manual check url - id: %d status: %s user_id: %d", id, result.Status, user.Id))
+
+		render.JSON(w, r, result)
+	}
+}
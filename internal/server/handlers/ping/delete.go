@@ -5,6 +5,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
 	"log/slog"
@@ -14,8 +15,8 @@ import (
 type (
 	// UrlRemover этот интерфейс реализует возможность удалять ссылки
 	UrlRemover interface {
-		RemoveUrlById(userId int64, id string) error
-		UrlExistById(userId int64, id string) (bool, error)
+		RemoveUrlById(workspaceId int64, id string) error
+		UrlExistById(workspaceId int64, id string) (bool, error)
 	}
 )
 
@@ -23,37 +24,45 @@ func NewDelete(log *slog.Logger, urlListRepo UrlRemover) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const (
 			op              = "server.handlers.statistics.delete"
-			errorMessage    = "Ошибка удаления ссылки"
-			notFoundMessage = "Ссылка не найдена"
+			errorMessage    = "api.ping_delete_error"
+			notFoundMessage = "api.url_not_found"
+			readOnlyMessage = "api.ping_delete_read_only"
 		)
 
 		log = log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
+		loc := i18n.FromContext(r.Context())
 
 		user := r.Context().Value(authorize.UserContextKey).(*model.User)
 		urlId := chi.URLParam(r, "id")
 
-		is, err := urlListRepo.UrlExistById(user.Id, urlId)
+		if !user.Workspace.Role.CanEdit() {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, loc.T(readOnlyMessage))
+			return
+		}
+
+		is, err := urlListRepo.UrlExistById(user.Workspace.WorkspaceId, urlId)
 
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
 			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, errorMessage)
+			render.JSON(w, r, loc.T(errorMessage))
 			return
 		}
 
 		if !is {
-			log.Error(fmt.Sprintf("%s: %s", notFoundMessage, err))
-			sendErrorMessage(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(notFoundMessage), err))
+			sendErrorMessage(w, r, loc.T(errorMessage))
 			return
 		}
 
-		err = urlListRepo.RemoveUrlById(user.Id, urlId)
+		err = urlListRepo.RemoveUrlById(user.Workspace.WorkspaceId, urlId)
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
-			sendErrorMessage(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			sendErrorMessage(w, r, loc.T(errorMessage))
 			return
 		}
 
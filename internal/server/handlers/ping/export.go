@@ -0,0 +1,63 @@
+package ping
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"log/slog"
+	"net/http"
+)
+
+// NewExport выгружает ссылки пространства файлом, format=csv|yaml|json, по умолчанию json
+func NewExport(log *slog.Logger, exporter command.MonitorTransfer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.ping.export"
+			errorMessage = "api.ping_export_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		format := transfer.Json
+		if value := r.URL.Query().Get("format"); value != "" {
+			var ok bool
+			if format, ok = transfer.ParseFormat(value); !ok {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, loc.Error(transfer.ErrUnknownFormat))
+				return
+			}
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		list, err := exporter.Export(user.Workspace.WorkspaceId)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		data, err := transfer.Encode(format, list)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("export urls user_id: %d workspace_id: %d format: %s", user.Id, user.Workspace.WorkspaceId, format))
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", format.FileName()))
+		w.Write(data)
+	}
+}
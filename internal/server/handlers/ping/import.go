@@ -0,0 +1,139 @@
+package ping
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewImport импорт ссылок из файла: multipart поле file или тело запроса,
+// параметры: format=csv|yaml|json (по умолчанию по имени файла или Content-Type), dry_run=1, upsert=1
+func NewImport(log *slog.Logger, importer command.MonitorTransfer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op              = "server.handlers.ping.import"
+			errorMessage    = "api.ping_import_error"
+			readOnlyMessage = "api.ping_import_read_only"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		if !user.Workspace.Role.CanEdit() {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, loc.T(readOnlyMessage))
+			return
+		}
+
+		data, format, err := importFile(w, r)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.Error(err))
+			return
+		}
+
+		rows, err := transfer.Decode(format, data)
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.Error(err))
+			return
+		}
+
+		query := r.URL.Query()
+		options := model.ImportOptions{
+			DryRun: queryBool(query.Get("dry_run")),
+			Upsert: queryBool(query.Get("upsert")),
+		}
+
+		report, err := importer.Import(r.Context(), user.Workspace.WorkspaceId, user.Id, rows, options)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("import urls user_id: %d workspace_id: %d created: %d updated: %d dry_run: %t", user.Id, user.Workspace.WorkspaceId, report.Created, report.Updated, report.DryRun))
+
+		render.JSON(w, r, report)
+	}
+}
+
+// importFile содержимое файла импорта и его формат
+func importFile(w http.ResponseWriter, r *http.Request) ([]byte, transfer.Format, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, transfer.MaxFileSize+1<<10)
+
+	body := io.Reader(r.Body)
+	var fileName string
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", i18n.WrapError(err, "api.import_no_file")
+		}
+		defer file.Close()
+
+		body = file
+		fileName = header.Filename
+		mediaType, _, _ = mime.ParseMediaType(header.Header.Get("Content-Type"))
+	}
+
+	format, ok := transfer.ParseFormat(r.URL.Query().Get("format"))
+	if !ok {
+		format, ok = transfer.FormatByFileName(fileName)
+	}
+	if !ok {
+		format, ok = formatByMediaType(mediaType)
+	}
+	if !ok {
+		return nil, "", transfer.ErrUnknownFormat
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, transfer.MaxFileSize+1))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || len(data) > transfer.MaxFileSize {
+		return nil, "", i18n.NewError("api.import_too_large", transfer.MaxFileSize/1024)
+	}
+
+	if err != nil {
+		return nil, "", i18n.WrapError(err, "api.import_no_file")
+	}
+
+	return data, format, nil
+}
+
+func formatByMediaType(mediaType string) (transfer.Format, bool) {
+	switch {
+	case mediaType == "text/csv":
+		return transfer.Csv, true
+	case strings.Contains(mediaType, "yaml"):
+		return transfer.Yaml, true
+	case mediaType == "application/json":
+		return transfer.Json, true
+	}
+
+	return "", false
+}
+
+func queryBool(value string) bool {
+	is, _ := strconv.ParseBool(value)
+
+	return is
+}
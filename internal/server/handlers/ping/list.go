@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
 	"github.com/ivankoTut/ping-url/internal/telegram/command"
@@ -11,29 +12,38 @@ import (
 	"net/http"
 )
 
+// NewList ссылки пространства, ?tag=env:prod - только ссылки с тегом
 func NewList(log *slog.Logger, urlListRepo command.UserUrlList) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const (
 			op           = "server.handlers.statistics.all"
-			errorMessage = "Ошибка получения списка ссылок"
+			errorMessage = "api.ping_list_error"
 		)
 
 		log = log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
+		loc := i18n.FromContext(r.Context())
+
+		tag, err := model.ParseTagFilter(r.URL.Query().Get("tag"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.invalid_tag", loc.Error(err)))
+			return
+		}
 
 		user := r.Context().Value(authorize.UserContextKey).(*model.User)
-		stats, err := urlListRepo.UrlListByUser(user.Id)
+		stats, err := urlListRepo.UrlListByWorkspace(user.Workspace.WorkspaceId)
 
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
-			render.JSON(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.JSON(w, r, loc.T(errorMessage))
 			return
 		}
 
-		log.Info(fmt.Sprintf("show ping list user_id: %d", user.Id))
+		log.Info(fmt.Sprintf("show ping list user_id: %d workspace_id: %d", user.Id, user.Workspace.WorkspaceId))
 
-		render.JSON(w, r, stats)
+		render.JSON(w, r, stats.WithTag(tag))
 	}
 }
@@ -0,0 +1,88 @@
+package ping
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// NewPatch изменяет переданные поля ссылки: {"url": "...", "connection_time": "10s", "ping_time": "1m", "repeat_alert": "1h", "tags": ["env:prod"]}
+func NewPatch(log *slog.Logger, editor command.UrlEditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op              = "server.handlers.ping.patch"
+			errorMessage    = "api.ping_patch_error"
+			notFoundMessage = "api.url_not_found"
+			existsMessage   = "api.url_exists"
+			readOnlyMessage = "api.ping_patch_read_only"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		if !user.Workspace.Role.CanEdit() {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, loc.T(readOnlyMessage))
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		var patch model.PingPatch
+		if err := render.DecodeJSON(r.Body, &patch); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, fmt.Sprintf("%s: %s", loc.T(errorMessage), err))
+			return
+		}
+
+		if err := patch.Validate(); err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.Error(err))
+			return
+		}
+
+		ping, err := editor.EditUrl(r.Context(), user.Workspace.WorkspaceId, id, patch)
+
+		if errors.Is(err, storage.ErrUrlNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(notFoundMessage))
+			return
+		}
+
+		if errors.Is(err, storage.ErrUrlExists) {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, loc.T(existsMessage))
+			return
+		}
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("edit url - id: %d user_id: %d", id, user.Id))
+
+		render.JSON(w, r, ping)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
 	"github.com/ivankoTut/ping-url/internal/telegram/command"
@@ -11,27 +12,47 @@ import (
 	"net/http"
 )
 
-func NewAll(log *slog.Logger, statsRepo command.StatisticUrlList) http.HandlerFunc {
+// NewAll статистика по ссылкам пространства, ?tag=env:prod - только по ссылкам с тегом
+func NewAll(log *slog.Logger, statsRepo command.StatisticUrlList, urlRepo command.UserUrlList) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const (
 			op           = "server.handlers.statistics.all"
-			errorMessage = "Ошибка получения статистики"
+			errorMessage = "api.statistic_error"
 		)
 
 		log = log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
+		loc := i18n.FromContext(r.Context())
+
+		tag, err := model.ParseTagFilter(r.URL.Query().Get("tag"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.invalid_tag", loc.Error(err)))
+			return
+		}
 
 		user := r.Context().Value(authorize.UserContextKey).(*model.User)
-		stats, err := statsRepo.StatisticByUser(user.Id)
+		stats, err := statsRepo.StatisticByWorkspace(user.Workspace.WorkspaceId)
 
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
-			render.JSON(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.JSON(w, r, loc.T(errorMessage))
 			return
 		}
 
+		if tag != "" {
+			list, err := urlRepo.UrlListByWorkspace(user.Workspace.WorkspaceId)
+			if err != nil {
+				log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+				render.JSON(w, r, loc.T(errorMessage))
+				return
+			}
+
+			stats = stats.WithUrls(list.WithTag(tag).Urls())
+		}
+
 		log.Info(fmt.Sprintf("show all statistics, user_id: %d", user.Id))
 
 		render.JSON(w, r, stats)
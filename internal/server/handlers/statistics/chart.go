@@ -0,0 +1,85 @@
+package statistics
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/chart"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NewChart отдает png график времени ответа и неудачных опросов ссылки, period=24h|7d|30d, по умолчанию 24h
+func NewChart(log *slog.Logger, pingRepo command.UrlRepositoryExist, chartRepo command.UrlChart) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.statistics.chart"
+			errorMessage = "api.chart_error"
+			urlNotFound  = "api.url_not_found"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		period, ok := chart.ParsePeriod(r.URL.Query().Get("period"))
+		if !ok {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.chart_period"))
+			return
+		}
+
+		url := r.URL.Query().Get("url")
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		is, err := pingRepo.UrlExist(user.Workspace.WorkspaceId, url)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		if is == false {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(urlNotFound), url))
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, loc.T(urlNotFound))
+			return
+		}
+
+		now := time.Now()
+		points, err := chartRepo.ChartByUrl(user.Workspace.WorkspaceId, url, period.From(now), period.Step)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		// без опросов за промежуток отдается пустой график с подписью, чтобы картинку можно было встроить в дашборд
+		data, err := chart.Render(points, chart.Options{
+			Title:    url,
+			Period:   period,
+			Location: model.NotificationSettings{Timezone: user.Timezone}.Location(),
+			Now:      now,
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("show chart by url: %s, period: %s, user_id: %d", url, period.Name, user.Id))
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(data)
+	}
+}
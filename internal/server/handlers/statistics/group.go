@@ -0,0 +1,56 @@
+package statistics
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+)
+
+// NewGroup общая статистика по всем ссылкам пространства с тегом: ?tag=env:prod
+func NewGroup(log *slog.Logger, groupRepo command.TagStatistic) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.statistics.group"
+			errorMessage = "api.statistic_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		tag, err := model.ParseTagFilter(r.URL.Query().Get("tag"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.invalid_tag", loc.Error(err)))
+			return
+		}
+
+		if tag == "" {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.tag_required"))
+			return
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		stats, err := groupRepo.GroupStatistic(r.Context(), user.Workspace.WorkspaceId, tag)
+
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("show group statistics by tag: %s, user_id: %d", tag, user.Id))
+
+		render.JSON(w, r, stats)
+	}
+}
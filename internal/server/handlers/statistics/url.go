@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
 	"github.com/ivankoTut/ping-url/internal/telegram/command"
@@ -15,14 +16,15 @@ func NewUrl(log *slog.Logger, pingRepo command.UrlRepositoryExist, statsRepo com
 	return func(w http.ResponseWriter, r *http.Request) {
 		const (
 			op           = "server.handlers.statistics.url"
-			errorMessage = "Ошибка получения статистики"
-			urlNotFound  = "Ссылка не найдена"
+			errorMessage = "api.statistic_error"
+			urlNotFound  = "api.url_not_found"
 		)
 
 		log = log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
+		loc := i18n.FromContext(r.Context())
 
 		var url string
 		keyQuery := r.URL.Query()["url"]
@@ -31,25 +33,25 @@ func NewUrl(log *slog.Logger, pingRepo command.UrlRepositoryExist, statsRepo com
 		}
 
 		user := r.Context().Value(authorize.UserContextKey).(*model.User)
-		is, err := pingRepo.UrlExist(user.Id, url)
+		is, err := pingRepo.UrlExist(user.Workspace.WorkspaceId, url)
 
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
-			render.JSON(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.JSON(w, r, loc.T(errorMessage))
 			return
 		}
 
 		if is == false {
-			log.Error(fmt.Sprintf("%s: %s", urlNotFound, url))
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(urlNotFound), url))
 			render.Status(r, http.StatusNotFound)
-			render.JSON(w, r, urlNotFound)
+			render.JSON(w, r, loc.T(urlNotFound))
 			return
 		}
 
-		stats, err := statsRepo.StatisticByUrl(user.Id, url)
+		stats, err := statsRepo.StatisticByUrl(user.Workspace.WorkspaceId, url)
 		if err != nil {
-			log.Error(fmt.Sprintf("%s: %s", errorMessage, err))
-			render.JSON(w, r, errorMessage)
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.JSON(w, r, loc.T(errorMessage))
 			return
 		}
 
@@ -0,0 +1,55 @@
+package status
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
+	"log/slog"
+	"net/http"
+)
+
+// NewList текущее состояние всех ссылок пространства, недоступные и работающие с проблемами в начале списка,
+// ?tag=env:prod - только ссылки с тегом
+func NewList(log *slog.Logger, overview command.MonitorStatusList) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const (
+			op           = "server.handlers.status.list"
+			errorMessage = "api.status_error"
+		)
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		loc := i18n.FromContext(r.Context())
+
+		tag, err := model.ParseTagFilter(r.URL.Query().Get("tag"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, loc.T("api.invalid_tag", loc.Error(err)))
+			return
+		}
+
+		user := r.Context().Value(authorize.UserContextKey).(*model.User)
+		list, err := overview.StatusByWorkspace(r.Context(), user.Workspace.WorkspaceId)
+		if err != nil {
+			log.Error(fmt.Sprintf("%s: %s", i18n.Default.T(errorMessage), err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, loc.T(errorMessage))
+			return
+		}
+
+		log.Info(fmt.Sprintf("show status user_id: %d workspace_id: %d", user.Id, user.Workspace.WorkspaceId))
+
+		list = list.WithTag(tag)
+		if list == nil {
+			list = model.MonitorOverviewList{}
+		}
+
+		render.JSON(w, r, list)
+	}
+}
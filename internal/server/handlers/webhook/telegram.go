@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"net/http"
+)
+
+// SecretHeader заголовок, в котором телеграм передает секрет указанный в setWebhook
+const SecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type (
+	// UpdateReceiver этот интерфейс реализует возможность передать боту обновление пришедшее на webhook
+	UpdateReceiver interface {
+		ReceiveUpdate(update tgbotapi.Update)
+	}
+)
+
+// NewTelegram принимает обновления от телеграма, запросы без верного секрета отклоняются
+func NewTelegram(log *slog.Logger, secret string, receiver UpdateReceiver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "server.handlers.webhook.telegram"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretHeader)), []byte(secret)) != 1 {
+			log.Info("invalid webhook secret")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			// телеграм повторяет запрос при ошибке, повторять не разобранное обновление нет смысла
+			log.Error(fmt.Sprintf("decode update: %s", err))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		receiver.ReceiveUpdate(update)
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
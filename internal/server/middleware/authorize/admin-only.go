@@ -0,0 +1,29 @@
+package authorize
+
+import (
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"net/http"
+)
+
+type (
+	// AdminProvider этот интерфейс реализует возможность проверить является ли пользователь администратором
+	AdminProvider interface {
+		IsAdmin(userId int64) bool
+	}
+)
+
+// AdminOnly пропускает только администраторов, подключается после ApiAuth
+func AdminOnly(provider AdminProvider) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(UserContextKey).(*model.User)
+			if !ok || !provider.IsAdmin(user.Id) {
+				http.Error(w, i18n.FromContext(r.Context()).T("api.admin_forbidden"), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package authorize
+
+import (
+	"crypto/subtle"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"net/http"
+)
+
+// AdminTokenHeader заголовок с токеном апи администратора из конфига
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminToken пропускает только запросы с токеном администратора из конфига, пустой токен в конфиге отключает апи администратора.
+// Ключ пользователя может утечь вместе с его скриптами, поэтому одной роли администратора для /admin недостаточно
+func AdminToken(token string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(AdminTokenHeader)), []byte(token)) != 1 {
+				http.Error(w, i18n.FromContext(r.Context()).T("api.admin_token_invalid"), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
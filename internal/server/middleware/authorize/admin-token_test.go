@@ -0,0 +1,42 @@
+package authorize
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		header string
+		want   int
+	}{
+		{name: "верный токен", config: "secret", header: "secret", want: http.StatusOK},
+		{name: "неверный токен", config: "secret", header: "secreT", want: http.StatusForbidden},
+		{name: "токен длиннее", config: "secret", header: "secret1", want: http.StatusForbidden},
+		{name: "без токена", config: "secret", header: "", want: http.StatusForbidden},
+		{name: "токен не задан в конфиге", config: "", header: "", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := AdminToken(tt.config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/admin/stats", nil)
+			if tt.header != "" {
+				r.Header.Set(AdminTokenHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("статус %d, ожидался %d", w.Code, tt.want)
+			}
+		})
+	}
+}
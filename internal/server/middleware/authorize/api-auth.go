@@ -2,7 +2,7 @@ package authorize
 
 import (
 	"context"
-	"fmt"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
 	"net/http"
 )
@@ -12,20 +12,32 @@ type (
 		UserFromRequest(r *http.Request) (*model.User, error)
 	}
 
+	// AccessProvider этот интерфейс реализует возможность проверить открыт ли пользователю доступ с учетом режима доступа и белого списка
+	AccessProvider interface {
+		IsAccess(userId int64) bool
+	}
+
 	contextKey string
 )
 
 const UserContextKey = contextKey("user")
 
-func ApiAuth(provider UserProvider) func(next http.Handler) http.Handler {
+// ApiAuth находит пользователя по api ключу, ключ пользователя, которому закрыли доступ к боту, тоже перестает работать
+func ApiAuth(provider UserProvider, access AccessProvider) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			user, err := provider.UserFromRequest(r)
 
 			if err != nil {
-				http.Error(w, fmt.Sprintf("Forbidden: %s", err), http.StatusForbidden)
+				loc := i18n.Parse(r.Header.Get("Accept-Language"))
+				http.Error(w, loc.T("api.forbidden", err), http.StatusForbidden)
+			} else if !access.IsAccess(user.Id) {
+				loc := i18n.Resolve(user.Language, r.Header.Get("Accept-Language"))
+				http.Error(w, loc.T("api.access_revoked"), http.StatusForbidden)
 			} else {
+				// язык ответов: выбранный пользователем в настройках, иначе из заголовка Accept-Language
 				ctx := context.WithValue(r.Context(), UserContextKey, user)
+				ctx = i18n.WithLocale(ctx, i18n.Resolve(user.Language, r.Header.Get("Accept-Language")))
 				next.ServeHTTP(w, r.WithContext(ctx))
 			}
 		})
@@ -0,0 +1,68 @@
+package authorize
+
+import (
+	"errors"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type (
+	fakeUsers map[string]*model.User // пользователи по api ключу
+
+	fakeAccess map[int64]bool
+)
+
+func (f fakeUsers) UserFromRequest(r *http.Request) (*model.User, error) {
+	user, ok := f[r.Header.Get("X-Api-Key")]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+
+	return user, nil
+}
+
+func (f fakeAccess) IsAccess(userId int64) bool {
+	return f[userId]
+}
+
+func TestApiAuth(t *testing.T) {
+	users := fakeUsers{
+		"allowed": {Id: 1},
+		"revoked": {Id: 2},
+	}
+	access := fakeAccess{1: true}
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{name: "доступ открыт", key: "allowed", want: http.StatusOK},
+		{name: "доступ закрыт, ключ еще существует", key: "revoked", want: http.StatusForbidden},
+		{name: "неизвестный ключ", key: "unknown", want: http.StatusForbidden},
+	}
+
+	handler := ApiAuth(users, access)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(UserContextKey).(*model.User); !ok {
+			t.Fatal("пользователь не передан в контекст")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			r.Header.Set("X-Api-Key", tt.key)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("статус %d, ожидался %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -3,18 +3,33 @@ package server
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/ivankoTut/ping-url/internal/config"
 	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/server/handlers/admin"
+	"github.com/ivankoTut/ping-url/internal/server/handlers/incident"
 	"github.com/ivankoTut/ping-url/internal/server/handlers/ping"
 	"github.com/ivankoTut/ping-url/internal/server/handlers/statistics"
+	"github.com/ivankoTut/ping-url/internal/server/handlers/status"
+	"github.com/ivankoTut/ping-url/internal/server/handlers/webhook"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/authorize"
 	"github.com/ivankoTut/ping-url/internal/server/middleware/logger"
-	"github.com/ivankoTut/ping-url/internal/storage/clickhouse"
+	"github.com/ivankoTut/ping-url/internal/statistic"
 	"github.com/ivankoTut/ping-url/internal/storage/postgres/repository"
+	"github.com/ivankoTut/ping-url/internal/telegram/command"
 	"net/http"
 	"time"
 )
 
-func RunApiServer(userRepo *repository.User, k *kernel.Kernel, clickhouseStatsRepo *clickhouse.Db, pingRepository *repository.Ping) {
+type (
+	// AdminAccess этот интерфейс реализует возможность проверить доступ и роль администратора и управлять блокировками пользователей
+	AdminAccess interface {
+		authorize.AdminProvider
+		authorize.AccessProvider
+		command.UserBlocker
+	}
+)
+
+func RunApiServer(userRepo *repository.User, k *kernel.Kernel, statsRepo *statistic.Statistic, pingRepository *repository.Ping, incidentRepo *repository.Incident, chartRepo command.UrlChart, overview command.MonitorStatusList, checker command.UrlChecker, editor command.UrlEditor, importer command.MonitorTransfer, updates webhook.UpdateReceiver, access AdminAccess, broadcaster command.Broadcaster, runtime command.RuntimeStatsProvider) {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -22,17 +37,50 @@ func RunApiServer(userRepo *repository.User, k *kernel.Kernel, clickhouseStatsRe
 	r.Use(logger.New(k.Log()))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(authorize.ApiAuth(userRepo))
 	r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Route("/statistics", func(r chi.Router) {
-		r.Get("/all", statistics.NewAll(k.Log(), clickhouseStatsRepo))
-		r.Get("/url", statistics.NewUrl(k.Log(), pingRepository, clickhouseStatsRepo))
-	})
+	// в режиме webhook телеграм отправляет обновления на апи сервер, доступ проверяется по секрету, а не по api ключу
+	if cfg := k.Config().Telegram; cfg.IsWebhook() {
+		r.Post(config.WebhookPath, webhook.NewTelegram(k.Log(), cfg.Webhook.Secret, updates))
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(authorize.ApiAuth(userRepo, access))
+
+		r.Route("/statistics", func(r chi.Router) {
+			r.Get("/all", statistics.NewAll(k.Log(), statsRepo, pingRepository))
+			r.Get("/group", statistics.NewGroup(k.Log(), statsRepo))
+			r.Get("/url", statistics.NewUrl(k.Log(), pingRepository, statsRepo))
+			r.Get("/chart.png", statistics.NewChart(k.Log(), pingRepository, chartRepo))
+		})
+
+		r.Get("/status", status.NewList(k.Log(), overview))
+
+		r.Route("/incidents", func(r chi.Router) {
+			r.Get("/", incident.NewList(k.Log(), incidentRepo))
+			r.Get("/{id}", incident.NewGet(k.Log(), incidentRepo))
+		})
+
+		r.Route("/ping", func(r chi.Router) {
+			r.Get("/", ping.NewList(k.Log(), pingRepository))
+			r.Get("/export", ping.NewExport(k.Log(), importer))
+			r.Post("/import", ping.NewImport(k.Log(), importer))
+			r.Delete("/{id}", ping.NewDelete(k.Log(), pingRepository))
+			r.Patch("/{id}", ping.NewPatch(k.Log(), editor))
+			r.Post("/{id}/check", ping.NewCheck(k.Log(), pingRepository, checker))
+		})
+
+		// апи администратора: кроме ключа пользователя из admin_user_list нужен отдельный токен admin_api_token
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authorize.AdminToken(k.Config().AdminApiToken))
+			r.Use(authorize.AdminOnly(access))
 
-	r.Route("/ping", func(r chi.Router) {
-		r.Get("/", ping.NewList(k.Log(), pingRepository))
-		r.Delete("/{id}", ping.NewDelete(k.Log(), pingRepository))
+			r.Get("/users", admin.NewUsers(k.Log(), userRepo))
+			r.Post("/users/{id}/block", admin.NewBlock(k.Log(), access))
+			r.Post("/users/{id}/unblock", admin.NewUnblock(k.Log(), access))
+			r.Post("/broadcast", admin.NewBroadcast(k.Log(), broadcaster))
+			r.Get("/stats", admin.NewStats(k.Log(), runtime))
+		})
 	})
 
 	http.ListenAndServe(k.Config().BaseApiUrl, r)
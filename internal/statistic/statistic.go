@@ -0,0 +1,109 @@
+package statistic
+
+import (
+	"context"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+)
+
+type (
+	// PingStatistic Интерфейс реалезует возможность получить статистику опросов ссылок
+	PingStatistic interface {
+		StatisticByWorkspace(workspaceId int64) (model.StatisticResultList, error)
+		StatisticByUrl(workspaceId int64, url string) (model.Statistic, error)
+		StatisticByUrlList(workspaceId int64, urlList []string) (model.Statistic, error)
+	}
+
+	// IncidentStatisticProvider Интерфейс реалезует возможность получить статистику инцидентов по ссылкам пространства
+	IncidentStatisticProvider interface {
+		IncidentStatistic(ctx context.Context, workspaceId int64) (map[string]model.IncidentStatistic, error)
+		GroupIncidentStatistic(ctx context.Context, workspaceId int64, urls []string) (model.IncidentStatistic, error)
+	}
+
+	// WorkspaceUrlList Интерфейс реалезует возможность получить ссылки пространства
+	WorkspaceUrlList interface {
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+	}
+
+	// Statistic дополняет статистику опросов ссылок статистикой инцидентов (кол-во, MTTR, MTBF)
+	Statistic struct {
+		pings     PingStatistic
+		incidents IncidentStatisticProvider
+		urls      WorkspaceUrlList
+	}
+)
+
+func NewStatistic(pings PingStatistic, incidents IncidentStatisticProvider, urls WorkspaceUrlList) *Statistic {
+	return &Statistic{
+		pings:     pings,
+		incidents: incidents,
+		urls:      urls,
+	}
+}
+
+func (s *Statistic) StatisticByWorkspace(workspaceId int64) (model.StatisticResultList, error) {
+	const op = "statistic.StatisticByWorkspace"
+
+	list, err := s.pings.StatisticByWorkspace(workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	incidents, err := s.incidents.IncidentStatistic(context.Background(), workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range list {
+		list[i].Incidents = incidents[list[i].Url]
+	}
+
+	return list, nil
+}
+
+func (s *Statistic) StatisticByUrl(workspaceId int64, url string) (model.Statistic, error) {
+	const op = "statistic.StatisticByUrl"
+
+	stats, err := s.pings.StatisticByUrl(workspaceId, url)
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+
+	incidents, err := s.incidents.IncidentStatistic(context.Background(), workspaceId)
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats.Incidents = incidents[url]
+
+	return stats, nil
+}
+
+// GroupStatistic общая статистика опросов и инцидентов по всем ссылкам пространства с тегом
+func (s *Statistic) GroupStatistic(ctx context.Context, workspaceId int64, tag string) (model.GroupStatistic, error) {
+	const op = "statistic.GroupStatistic"
+
+	group := model.GroupStatistic{Tag: tag}
+
+	list, err := s.urls.UrlListByWorkspace(workspaceId)
+	if err != nil {
+		return group, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group.Urls = list.WithTag(tag).Urls()
+	if len(group.Urls) == 0 {
+		return group, nil
+	}
+
+	group.Statistic, err = s.pings.StatisticByUrlList(workspaceId, group.Urls)
+	if err != nil {
+		return group, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group.Statistic.Incidents, err = s.incidents.GroupIncidentStatistic(ctx, workspaceId, group.Urls)
+	if err != nil {
+		return group, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
@@ -33,6 +33,9 @@ const baseStatisticSelect = `url as Url,
 			END) as AvgConnectionTime
 		from url_status `
 
+// groupErrorLimit сколько самых частых ошибок выводить в статистике по группе ссылок
+const groupErrorLimit = 10
+
 type (
 	Db struct {
 		cfg  config.Config
@@ -95,6 +98,25 @@ func (db *Db) MustRunMigrations() {
 		log.Fatal(err)
 	}
 
+	// у старых строк пространство вычисляется из userId: личные пространства существующих пользователей
+	// созданы с id равным id пользователя, см. миграцию create_workspace_tables в postgres
+	stmt, err = tx.Prepare(`
+		ALTER TABLE url_status ADD COLUMN IF NOT EXISTS workspaceId Int64 DEFAULT userId
+		`)
+
+	if _, err := stmt.Exec(); err != nil {
+		log.Fatal(err)
+	}
+
+	// опросы запущенные вручную (/check_now), у старых строк - опросы по расписанию
+	stmt, err = tx.Prepare(`
+		ALTER TABLE url_status ADD COLUMN IF NOT EXISTS isManual Bool DEFAULT false
+		`)
+
+	if _, err := stmt.Exec(); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		log.Fatal(err)
 	}
@@ -115,9 +137,9 @@ func (db *Db) InsertRows(urls model.PingResultList) error {
 	}
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO url_status (userId, url, statusCode, error, pingTime, createdAt, isCancel)
+		INSERT INTO url_status (userId, workspaceId, url, statusCode, error, pingTime, createdAt, isCancel, isManual)
 		VALUES (
-			?, ?, ?, ?, ?, ?, ?
+			?, ?, ?, ?, ?, ?, ?, ?, ?
 		)`)
 
 	if err != nil {
@@ -133,12 +155,14 @@ func (db *Db) InsertRows(urls model.PingResultList) error {
 
 		if _, err := stmt.Exec(
 			v.Ping.UserId,
+			v.Ping.WorkspaceId,
 			v.Ping.Url,
 			v.StatusCode,
 			errMessage,
 			v.RealConnectionTime,
 			time.Now(),
 			v.IsCancel,
+			v.IsManual,
 		); err != nil {
 			return err
 		}
@@ -151,12 +175,33 @@ func (db *Db) InsertRows(urls model.PingResultList) error {
 	return nil
 }
 
-func (db *Db) StatisticByUser(userId int64) (model.StatisticResultList, error) {
+// RenameUrl переносит историю опросов на новый адрес ссылки. Адрес входит в ключ сортировки таблицы
+// и не может быть изменен через UPDATE, поэтому строки копируются с новым адресом, а старые удаляются
+func (db *Db) RenameUrl(workspaceId int64, oldUrl, newUrl string) error {
+	const op = "storage.clickhouse.RenameUrl"
+
+	if _, err := db.conn.Exec(`
+		INSERT INTO url_status (userId, workspaceId, url, statusCode, error, pingTime, createdAt, isCancel, isManual)
+		SELECT userId, workspaceId, ?, statusCode, error, pingTime, createdAt, isCancel, isManual
+		FROM url_status WHERE workspaceId = ? AND url = ?`,
+		newUrl, workspaceId, oldUrl,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := db.conn.Exec(`ALTER TABLE url_status DELETE WHERE workspaceId = ? AND url = ?`, workspaceId, oldUrl); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (db *Db) StatisticByWorkspace(workspaceId int64) (model.StatisticResultList, error) {
 	rows, err := db.conn.Query(`
-		select `+baseStatisticSelect+` where userId = ?
+		select `+baseStatisticSelect+` where workspaceId = ?
 		group by url
 		order by AvgConnectionTime desc;
-	`, userId)
+	`, workspaceId)
 
 	if err != nil {
 		return nil, err
@@ -183,15 +228,19 @@ func (db *Db) StatisticByUser(userId int64) (model.StatisticResultList, error) {
 	return list, nil
 }
 
-func (db *Db) CurrentStatisticByUser(userId int64, urlList []string) (model.StatisticResultList, error) {
-	params := []interface{}{userId}
+func (db *Db) CurrentStatisticByWorkspace(workspaceId int64, urlList []string) (model.StatisticResultList, error) {
+	if len(urlList) == 0 {
+		return nil, nil
+	}
+
+	params := []interface{}{workspaceId}
 	for _, v := range urlList {
 		params = append(params, v)
 	}
 
 	rows, err := db.conn.Query(`
 		select `+baseStatisticSelect+` where 
-		    userId = ? and url in (?, `+strings.Repeat("?, ", len(urlList)-1)+`)
+		    workspaceId = ? and url in (?, `+strings.Repeat("?, ", len(urlList)-1)+`)
 		group by url
 		order by AvgConnectionTime desc;
 	`, params...)
@@ -221,9 +270,9 @@ func (db *Db) CurrentStatisticByUser(userId int64, urlList []string) (model.Stat
 	return list, nil
 }
 
-func (db *Db) StatisticByUrl(userId int64, url string) (model.Statistic, error) {
+func (db *Db) StatisticByUrl(workspaceId int64, url string) (model.Statistic, error) {
 
-	rows, err := db.conn.Query(`select error as errorText, count(error) as count from url_status where userId = ? and url = ? and error <> '' group by error order by count desc`, userId, url)
+	rows, err := db.conn.Query(`select error as errorText, count(error) as count from url_status where workspaceId = ? and url = ? and error <> '' group by error order by count desc`, workspaceId, url)
 	if err != nil {
 		return model.Statistic{}, err
 	}
@@ -237,12 +286,124 @@ func (db *Db) StatisticByUrl(userId int64, url string) (model.Statistic, error)
 		errorList = append(errorList, errorText)
 	}
 
-	statsList, err := db.CurrentStatisticByUser(userId, []string{url})
+	statsList, err := db.CurrentStatisticByWorkspace(workspaceId, []string{url})
 	if err != nil {
 		return model.Statistic{}, err
 	}
 
+	if len(statsList) == 0 {
+		return model.Statistic{Url: url, Errors: errorList}, nil
+	}
+
 	statsList[0].Errors = errorList
 
 	return statsList[0], nil
 }
+
+// StatisticByUrlList общая статистика опросов по нескольким ссылкам пространства и самые частые ошибки среди них
+func (db *Db) StatisticByUrlList(workspaceId int64, urlList []string) (model.Statistic, error) {
+	const op = "storage.clickhouse.StatisticByUrlList"
+
+	var stats model.Statistic
+	if len(urlList) == 0 {
+		return stats, nil
+	}
+
+	params := []interface{}{workspaceId}
+	for _, v := range urlList {
+		params = append(params, v)
+	}
+	where := `workspaceId = ? and url in (?` + strings.Repeat(", ?", len(urlList)-1) + `)`
+
+	// без успешных опросов maxIf и minIf возвращают 0, а avgIf - nan
+	err := db.conn.QueryRow(`
+		select
+			count() as CountPing,
+			countIf(isCancel = false) as CorrectCount,
+			countIf(isCancel = true) as CancelCount,
+			maxIf(pingTime, isCancel = false) as MaxConnectionTime,
+			minIf(pingTime, isCancel = false) as MinConnectionTime,
+			ifNotFinite(avgIf(pingTime, isCancel = false), 0) as AvgConnectionTime
+		from url_status
+		where `+where, params...,
+	).Scan(
+		&stats.CountPing,
+		&stats.CorrectCount,
+		&stats.CancelCount,
+		&stats.MaxConnectionTime,
+		&stats.MinConnectionTime,
+		&stats.AvgConnectionTime,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := db.conn.Query(`
+		select error as errorText, count(error) as count from url_status
+		where `+where+` and error <> ''
+		group by error
+		order by count desc
+		limit ?`, append(params, groupErrorLimit)...,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var errorText model.ErrorMessage
+		if err := rows.Scan(&errorText.Text, &errorText.Count); err != nil {
+			return stats, fmt.Errorf("%s: %w", op, err)
+		}
+
+		stats.Errors = append(stats.Errors, errorText)
+	}
+
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// ChartByUrl перцентили времени ответа и кол-во неудачных опросов ссылки начиная с from, сгруппированные по интервалам step
+func (db *Db) ChartByUrl(workspaceId int64, url string, from time.Time, step time.Duration) (model.ChartPointList, error) {
+	const op = "storage.clickhouse.ChartByUrl"
+
+	// перцентили считаются только по успешным опросам, в интервале без них quantileIf возвращает nan
+	rows, err := db.conn.Query(`
+		select
+			toUnixTimestamp(toStartOfInterval(createdAt, toIntervalSecond(?))) as bucket,
+			ifNotFinite(quantileIf(0.5)(pingTime, isCancel = false), 0) as p50,
+			ifNotFinite(quantileIf(0.95)(pingTime, isCancel = false), 0) as p95,
+			countIf(isCancel = true) as failures,
+			count() as total
+		from url_status
+		where workspaceId = ? and url = ? and createdAt >= toDateTime(?)
+		group by bucket
+		order by bucket`,
+		int64(step.Seconds()), workspaceId, url, from.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list model.ChartPointList
+	for rows.Next() {
+		var point model.ChartPoint
+		var bucket int64
+		if err := rows.Scan(&bucket, &point.P50, &point.P95, &point.Failures, &point.Total); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		point.Time = time.Unix(bucket, 0)
+		list = append(list, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"time"
+)
+
+// Access режим доступа к боту, список доступа и коды приглашений
+type Access struct {
+	connection kernel.DBConnection
+}
+
+func NewAccess(db kernel.DBConnection) *Access {
+	return &Access{connection: db}
+}
+
+// InitAccess при первом запуске сохраняет начальный режим доступа и список доступа,
+// если режим уже сохранен ничего не меняется
+func (a *Access) InitAccess(ctx context.Context, mode model.AccessMode, userIds []int64) error {
+	const op = "storage.postgres.repository.access.InitAccess"
+
+	tx, err := a.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO access_setting(mode) VALUES($1) ON CONFLICT (id) DO NOTHING", mode)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	for _, userId := range userIds {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO access_allow(user_id) VALUES($1) ON CONFLICT (user_id) DO NOTHING", userId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *Access) AccessMode(ctx context.Context) (model.AccessMode, error) {
+	const op = "storage.postgres.repository.access.AccessMode"
+
+	var mode model.AccessMode
+	if err := a.connection.DB().QueryRowContext(ctx, "SELECT mode FROM access_setting").Scan(&mode); err != nil {
+		return mode, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mode, nil
+}
+
+func (a *Access) SetAccessMode(ctx context.Context, mode model.AccessMode) error {
+	const op = "storage.postgres.repository.access.SetAccessMode"
+
+	_, err := a.connection.DB().ExecContext(ctx, `
+		INSERT INTO access_setting(mode) VALUES($1)
+		ON CONFLICT (id) DO UPDATE SET mode = excluded.mode`, mode)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AllowList пользователи из списка доступа
+func (a *Access) AllowList(ctx context.Context) ([]int64, error) {
+	const op = "storage.postgres.repository.access.AllowList"
+
+	rows, err := a.connection.DB().QueryContext(ctx, "SELECT user_id FROM access_allow ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (a *Access) Allow(ctx context.Context, userId int64) error {
+	const op = "storage.postgres.repository.access.Allow"
+
+	_, err := a.connection.DB().ExecContext(ctx, "INSERT INTO access_allow(user_id) VALUES($1) ON CONFLICT (user_id) DO NOTHING", userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Disallow убирает пользователя из списка доступа, если его там нет - storage.ErrUserNotFound
+func (a *Access) Disallow(ctx context.Context, userId int64) error {
+	const op = "storage.postgres.repository.access.Disallow"
+
+	res, err := a.connection.DB().ExecContext(ctx, "DELETE FROM access_allow WHERE user_id = $1", userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// CreateAccessCode создает код приглашения, которым можно воспользоваться maxUses раз до истечения ttl
+func (a *Access) CreateAccessCode(ctx context.Context, createdBy int64, maxUses int, ttl time.Duration) (model.AccessCode, error) {
+	const op = "storage.postgres.repository.access.CreateAccessCode"
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return model.AccessCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	code := model.AccessCode{
+		Code:      hex.EncodeToString(buf),
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	_, err := a.connection.DB().ExecContext(ctx,
+		"INSERT INTO access_code(code, max_uses, created_by, expires_at) VALUES($1, $2, $3, $4)",
+		code.Code, code.MaxUses, code.CreatedBy, code.ExpiresAt,
+	)
+	if err != nil {
+		return model.AccessCode{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return code, nil
+}
+
+// RedeemAccessCode использует код приглашения и добавляет пользователя в список доступа,
+// чтобы при переключении в режим allowlist приглашенные пользователи не потеряли доступ.
+// Если код не найден, истек или исчерпан - storage.ErrInviteNotFound
+func (a *Access) RedeemAccessCode(ctx context.Context, userId int64, code string) error {
+	const op = "storage.postgres.repository.access.RedeemAccessCode"
+
+	tx, err := a.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var uses int
+	err = tx.QueryRowContext(ctx, `
+		UPDATE access_code SET uses = uses + 1
+		WHERE code = $1 AND expires_at > now() AND uses < max_uses
+		RETURNING uses`, code,
+	).Scan(&uses)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, storage.ErrInviteNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO access_allow(user_id) VALUES($1) ON CONFLICT (user_id) DO NOTHING", userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AccessCodes действующие коды приглашений, сначала новые
+func (a *Access) AccessCodes(ctx context.Context) ([]model.AccessCode, error) {
+	const op = "storage.postgres.repository.access.AccessCodes"
+
+	rows, err := a.connection.DB().QueryContext(ctx, `
+		SELECT code, max_uses, uses, created_by, expires_at FROM access_code
+		WHERE expires_at > now() AND uses < max_uses
+		ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var codes []model.AccessCode
+	for rows.Next() {
+		var code model.AccessCode
+		if err := rows.Scan(&code.Code, &code.MaxUses, &code.Uses, &code.CreatedBy, &code.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// RevokeAccessCode удаляет код приглашения, если кода нет - storage.ErrInviteNotFound
+func (a *Access) RevokeAccessCode(ctx context.Context, code string) error {
+	const op = "storage.postgres.repository.access.RevokeAccessCode"
+
+	res, err := a.connection.DB().ExecContext(ctx, "DELETE FROM access_code WHERE code = $1", code)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrInviteNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/lib/pq"
+	"time"
+)
+
+const selectIncident = `
+	SELECT i.id, i.ping_id, p.url, i.started_at, i.ended_at, i.first_error
+	FROM incident i
+	INNER JOIN ping p ON p.id = i.ping_id`
+
+type Incident struct {
+	connection kernel.DBConnection
+}
+
+func NewIncident(db kernel.DBConnection) *Incident {
+	return &Incident{connection: db}
+}
+
+// OpenIncident открывает инцидент по ссылке, если открытый инцидент уже есть - ничего не делает
+func (i *Incident) OpenIncident(ctx context.Context, pingId int64, kind model.IncidentEventKind, firstError string, at time.Time) error {
+	const op = "storage.postgres.repository.incident.OpenIncident"
+
+	tx, err := i.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO incident(ping_id, started_at, first_error) VALUES($1, $2, $3)
+		ON CONFLICT (ping_id) WHERE ended_at IS NULL DO NOTHING
+		RETURNING id`,
+		pingId, at, firstError,
+	).Scan(&id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO incident_event(incident_id, kind, text, created_at) VALUES($1, $2, $3, $4)`, id, kind, firstError, at); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddIncidentEvent добавляет запись в хронологию открытого инцидента по ссылке, если открытого инцидента нет - ничего не делает
+func (i *Incident) AddIncidentEvent(ctx context.Context, pingId int64, kind model.IncidentEventKind, text string, at time.Time) error {
+	const op = "storage.postgres.repository.incident.AddIncidentEvent"
+
+	_, err := i.connection.DB().ExecContext(ctx, `
+		INSERT INTO incident_event(incident_id, kind, text, created_at)
+		SELECT id, $2, $3, $4 FROM incident WHERE ping_id = $1 AND ended_at IS NULL`,
+		pingId, kind, text, at,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CloseIncident закрывает открытый инцидент по ссылке
+func (i *Incident) CloseIncident(ctx context.Context, pingId int64, at time.Time) error {
+	const op = "storage.postgres.repository.incident.CloseIncident"
+
+	_, err := i.connection.DB().ExecContext(ctx, `
+		WITH closed AS (
+			UPDATE incident SET ended_at = $2 WHERE ping_id = $1 AND ended_at IS NULL RETURNING id
+		)
+		INSERT INTO incident_event(incident_id, kind, text, created_at)
+		SELECT id, $3, '', $2 FROM closed`,
+		pingId, at, model.IncidentEventRecovery,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IncidentList последние инциденты пространства, сначала новые
+func (i *Incident) IncidentList(ctx context.Context, workspaceId int64, limit int) (model.IncidentList, error) {
+	const op = "storage.postgres.repository.incident.IncidentList"
+
+	rows, err := i.connection.DB().QueryContext(ctx, selectIncident+` WHERE p.workspace_id = $1 ORDER BY i.started_at DESC LIMIT $2`, workspaceId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	list := model.IncidentList{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// IncidentById инцидент пространства вместе с хронологией
+func (i *Incident) IncidentById(ctx context.Context, workspaceId, id int64) (model.Incident, error) {
+	const op = "storage.postgres.repository.incident.IncidentById"
+
+	incident, err := scanIncident(i.connection.DB().QueryRowContext(ctx, selectIncident+` WHERE p.workspace_id = $1 AND i.id = $2`, workspaceId, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return incident, fmt.Errorf("%s: %w", op, storage.ErrIncidentNotFound)
+	}
+
+	if err != nil {
+		return incident, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := i.connection.DB().QueryContext(ctx, `SELECT kind, text, created_at FROM incident_event WHERE incident_id = $1 ORDER BY created_at, id`, id)
+	if err != nil {
+		return incident, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	incident.Events = []model.IncidentEvent{}
+	for rows.Next() {
+		var event model.IncidentEvent
+		if err := rows.Scan(&event.Kind, &event.Text, &event.CreatedAt); err != nil {
+			return incident, fmt.Errorf("%s: %w", op, err)
+		}
+
+		incident.Events = append(incident.Events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return incident, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return incident, nil
+}
+
+// IncidentStatistic статистика инцидентов по ссылкам пространства, ключ - url.
+// MTTR считается по закрытым инцидентам, MTBF - по промежуткам между окончанием инцидента и началом следующего
+func (i *Incident) IncidentStatistic(ctx context.Context, workspaceId int64) (map[string]model.IncidentStatistic, error) {
+	const op = "storage.postgres.repository.incident.IncidentStatistic"
+
+	rows, err := i.connection.DB().QueryContext(ctx, `
+		SELECT url, count(*),
+			coalesce(extract(epoch from avg(ended_at - started_at)), 0),
+			coalesce(extract(epoch from avg(started_at - prev_ended_at)), 0)
+		FROM (
+			SELECT p.url, i.started_at, i.ended_at,
+				lag(i.ended_at) OVER (PARTITION BY i.ping_id ORDER BY i.started_at) AS prev_ended_at
+			FROM incident i
+			INNER JOIN ping p ON p.id = i.ping_id
+			WHERE p.workspace_id = $1
+		) t
+		GROUP BY url`,
+		workspaceId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]model.IncidentStatistic)
+	for rows.Next() {
+		var (
+			url  string
+			stat model.IncidentStatistic
+		)
+
+		if err := rows.Scan(&url, &stat.Count, &stat.Mttr, &stat.Mtbf); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		stats[url] = stat
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// GroupIncidentStatistic общая статистика инцидентов по нескольким ссылкам пространства,
+// MTBF считается по промежуткам между инцидентами одной ссылки, как и в IncidentStatistic
+func (i *Incident) GroupIncidentStatistic(ctx context.Context, workspaceId int64, urls []string) (model.IncidentStatistic, error) {
+	const op = "storage.postgres.repository.incident.GroupIncidentStatistic"
+
+	var stat model.IncidentStatistic
+	err := i.connection.DB().QueryRowContext(ctx, `
+		SELECT count(*),
+			coalesce(extract(epoch from avg(ended_at - started_at)), 0),
+			coalesce(extract(epoch from avg(started_at - prev_ended_at)), 0)
+		FROM (
+			SELECT i.started_at, i.ended_at,
+				lag(i.ended_at) OVER (PARTITION BY i.ping_id ORDER BY i.started_at) AS prev_ended_at
+			FROM incident i
+			INNER JOIN ping p ON p.id = i.ping_id
+			WHERE p.workspace_id = $1 AND p.url = ANY($2)
+		) t`,
+		workspaceId, pq.Array(urls),
+	).Scan(&stat.Count, &stat.Mttr, &stat.Mtbf)
+	if err != nil {
+		return stat, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stat, nil
+}
+
+func scanIncident(row rowScanner) (model.Incident, error) {
+	var incident model.Incident
+	if err := row.Scan(&incident.Id, &incident.PingId, &incident.Url, &incident.StartedAt, &incident.EndedAt, &incident.FirstError); err != nil {
+		return incident, err
+	}
+
+	end := time.Now()
+	if incident.EndedAt != nil {
+		end = *incident.EndedAt
+	}
+
+	incident.Duration = end.Sub(incident.StartedAt).Seconds()
+
+	return incident, nil
+}
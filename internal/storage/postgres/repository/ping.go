@@ -1,27 +1,45 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/ivankoTut/ping-url/internal/kernel"
 	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/lib/pq"
+	"time"
 )
 
-type Ping struct {
-	connection kernel.DBConnection
-}
+// selectPing общая часть запроса для получения ссылки вместе с пользователем, см. scanPing
+const selectPing = `
+		select p.id, p.url, p.user_id, p.workspace_id, p.connection_time, p.ping_time, p.mute, p.mute_until, p.critical, p.tags, p.repeat_alert, u.id, u.login, u.mute, u.mute_until from ping as p 
+		left join users as u on p.user_id = u.id`
+
+type (
+	Ping struct {
+		connection kernel.DBConnection
+	}
+
+	rowScanner interface {
+		Scan(dest ...any) error
+	}
+)
 
 func NewPing(db kernel.DBConnection) *Ping {
 	return &Ping{connection: db}
 }
 
-func (p *Ping) SaveUrl(userId int64, url, connectionTime, pingTime string) error {
+// SaveUrl добавляет ссылку в пространство, userId - кто ее добавил
+func (p *Ping) SaveUrl(workspaceId, userId int64, url, connectionTime, pingTime string) error {
 	const op = "storage.postgres.repository.ping.SaveUrl"
-	stmt, err := p.connection.DB().Prepare(`INSERT INTO ping(user_id, url, connection_time, ping_time) VALUES($1, $2, $3, $4)`)
+	stmt, err := p.connection.DB().Prepare(`INSERT INTO ping(workspace_id, user_id, url, connection_time, ping_time) VALUES($1, $2, $3, $4, $5)`)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(userId, url, connectionTime, pingTime)
+	_, err = stmt.Exec(workspaceId, userId, url, connectionTime, pingTime)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -29,14 +47,14 @@ func (p *Ping) SaveUrl(userId int64, url, connectionTime, pingTime string) error
 	return nil
 }
 
-func (p *Ping) RemoveUrl(userId int64, url string) error {
+func (p *Ping) RemoveUrl(workspaceId int64, url string) error {
 	const op = "storage.postgres.repository.ping.RemoveUrl"
-	stmt, err := p.connection.DB().Prepare(`delete from ping where user_id = $1 and url = $2`)
+	stmt, err := p.connection.DB().Prepare(`delete from ping where workspace_id = $1 and url = $2`)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(userId, url)
+	_, err = stmt.Exec(workspaceId, url)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -44,40 +62,40 @@ func (p *Ping) RemoveUrl(userId int64, url string) error {
 	return nil
 }
 
-func (p *Ping) UrlExist(userId int64, url string) (bool, error) {
+func (p *Ping) UrlExist(workspaceId int64, url string) (bool, error) {
 	const op = "storage.postgres.repository.ping.SaveUrl"
-	stmt, err := p.connection.DB().Prepare(`SELECT count(*) FROM ping WHERE user_id = $1 and url = $2`)
+	stmt, err := p.connection.DB().Prepare(`SELECT count(*) FROM ping WHERE workspace_id = $1 and url = $2`)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	var count int
-	err = stmt.QueryRow(userId, url).Scan(&count)
+	err = stmt.QueryRow(workspaceId, url).Scan(&count)
 
 	return count > 0, err
 }
 
-func (p *Ping) UrlExistById(userId int64, id string) (bool, error) {
+func (p *Ping) UrlExistById(workspaceId int64, id string) (bool, error) {
 	const op = "storage.postgres.repository.ping.UrlExistById"
-	stmt, err := p.connection.DB().Prepare(`SELECT count(*) FROM ping WHERE user_id = $1 and id = $2`)
+	stmt, err := p.connection.DB().Prepare(`SELECT count(*) FROM ping WHERE workspace_id = $1 and id = $2`)
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	var count int
-	err = stmt.QueryRow(userId, id).Scan(&count)
+	err = stmt.QueryRow(workspaceId, id).Scan(&count)
 
 	return count > 0, err
 }
 
-func (p *Ping) RemoveUrlById(userId int64, id string) error {
+func (p *Ping) RemoveUrlById(workspaceId int64, id string) error {
 	const op = "storage.postgres.repository.ping.RemoveUrlById"
-	stmt, err := p.connection.DB().Prepare(`delete from ping where user_id = $1 and id = $2`)
+	stmt, err := p.connection.DB().Prepare(`delete from ping where workspace_id = $1 and id = $2`)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(userId, id)
+	_, err = stmt.Exec(workspaceId, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -85,13 +103,11 @@ func (p *Ping) RemoveUrlById(userId int64, id string) error {
 	return nil
 }
 
-func (p *Ping) UrlListByUser(userId int64) (model.PingList, error) {
-	const op = "storage.postgres.repository.ping.UrlListByUser"
+func (p *Ping) UrlListByWorkspace(workspaceId int64) (model.PingList, error) {
+	const op = "storage.postgres.repository.ping.UrlListByWorkspace"
 
-	rows, err := p.connection.DB().Query(`
-		select p.id, p.url, p.user_id, p.connection_time, p.ping_time, u.id, u.login, u.mute from ping as p 
-		left join users as u on p.user_id = u.id
-		where p.user_id = $1 order by p.id desc`, userId,
+	rows, err := p.connection.DB().Query(selectPing+`
+		where p.workspace_id = $1 order by p.id desc`, workspaceId,
 	)
 
 	if err != nil {
@@ -102,7 +118,7 @@ func (p *Ping) UrlListByUser(userId int64) (model.PingList, error) {
 	var links model.PingList
 	var link model.Ping
 	for rows.Next() {
-		err := rows.Scan(&link.Id, &link.Url, &link.UserId, &link.ConnectionTime, &link.PingTime, &link.User.Id, &link.User.Login, &link.User.Mute)
+		err := scanPing(rows, &link)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -113,12 +129,74 @@ func (p *Ping) UrlListByUser(userId int64) (model.PingList, error) {
 	return links, nil
 }
 
+// UrlById ссылка из любого пространства, в котором состоит пользователь
+func (p *Ping) UrlById(userId, id int64) (model.Ping, error) {
+	const op = "storage.postgres.repository.ping.UrlById"
+
+	var link model.Ping
+	err := scanPing(p.connection.DB().QueryRow(selectPing+`
+		inner join workspace_member as m on m.workspace_id = p.workspace_id and m.user_id = $1
+		where p.id = $2`, userId, id,
+	), &link)
+
+	if err != nil {
+		return link, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
+
+// PingById ссылка по идентификатору без проверки пространства, используется для перезапуска опроса
+func (p *Ping) PingById(id int64) (model.Ping, error) {
+	const op = "storage.postgres.repository.ping.PingById"
+
+	var link model.Ping
+	err := scanPing(p.connection.DB().QueryRow(selectPing+` where p.id = $1`, id), &link)
+	if errors.Is(err, sql.ErrNoRows) {
+		return link, fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	if err != nil {
+		return link, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
+
+// UpdateUrl изменяет переданные поля ссылки пространства и возвращает адрес ссылки до изменения
+func (p *Ping) UpdateUrl(workspaceId, id int64, patch model.PingPatch) (string, error) {
+	const op = "storage.postgres.repository.ping.UpdateUrl"
+
+	// в FROM строка old содержит значения до обновления
+	var oldUrl string
+	err := p.connection.DB().QueryRow(`
+		update ping as p set
+			url = coalesce($3, p.url),
+			connection_time = coalesce($4, p.connection_time),
+			ping_time = coalesce($5, p.ping_time),
+			tags = coalesce($6::text[], p.tags),
+			repeat_alert = coalesce($7, p.repeat_alert)
+		from ping as old
+		where old.id = p.id and p.workspace_id = $1 and p.id = $2
+		returning old.url`,
+		workspaceId, id, patch.Url, patch.ConnectionTime, patch.PingTime, tagsParam(patch.Tags), patch.RepeatAlert,
+	).Scan(&oldUrl)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%s: %w", op, storage.ErrUrlNotFound)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return oldUrl, nil
+}
+
 func (p *Ping) UrlList(limit, offset int) (model.TimerPingList, error) {
 	const op = "storage.postgres.repository.ping.UrlList"
 
-	rows, err := p.connection.DB().Query(`
-		select p.id, p.url, p.user_id, p.connection_time, p.ping_time, u.id, u.login, u.mute from ping as p 
-		left join users as u on p.user_id = u.id limit $1 offset $2`,
+	rows, err := p.connection.DB().Query(selectPing+` order by p.id limit $1 offset $2`,
 		limit,
 		offset,
 	)
@@ -131,7 +209,7 @@ func (p *Ping) UrlList(limit, offset int) (model.TimerPingList, error) {
 	links := make(model.TimerPingList)
 	var link model.Ping
 	for rows.Next() {
-		err := rows.Scan(&link.Id, &link.Url, &link.UserId, &link.ConnectionTime, &link.PingTime, &link.User.Id, &link.User.Login, &link.User.Mute)
+		err := scanPing(rows, &link)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -156,3 +234,205 @@ func (p *Ping) Count() (int, error) {
 
 	return count, err
 }
+
+// MuteUrl отключает уведомления по ссылке, until = nil - бессрочно, userId и chatId - кто и в каком чате отключил,
+// туда придет сообщение об окончании отключения
+func (p *Ping) MuteUrl(workspaceId int64, url string, until *time.Time, userId, chatId int64) error {
+	const op = "storage.postgres.repository.ping.MuteUrl"
+
+	_, err := p.connection.DB().Exec(`
+		update ping set mute = true, mute_until = $1, muted_by = $4, mute_chat_id = $5
+		where workspace_id = $2 and url = $3`,
+		until, workspaceId, url, userId, chatId,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (p *Ping) UnmuteUrl(workspaceId int64, url string) error {
+	const op = "storage.postgres.repository.ping.UnmuteUrl"
+
+	_, err := p.connection.DB().Exec(`update ping set mute = false, mute_until = null, muted_by = null, mute_chat_id = null where workspace_id = $1 and url = $2`, workspaceId, url)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ToggleCritical меняет признак критичности ссылки на противоположный и возвращает новое значение
+func (p *Ping) ToggleCritical(workspaceId int64, url string) (bool, error) {
+	const op = "storage.postgres.repository.ping.ToggleCritical"
+
+	var critical bool
+	err := p.connection.DB().QueryRow(`update ping set critical = not critical where workspace_id = $1 and url = $2 returning critical`, workspaceId, url).Scan(&critical)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return critical, nil
+}
+
+// SetCritical устанавливает признак критичности ссылки
+func (p *Ping) SetCritical(workspaceId int64, url string, critical bool) error {
+	const op = "storage.postgres.repository.ping.SetCritical"
+
+	_, err := p.connection.DB().Exec(`update ping set critical = $3 where workspace_id = $1 and url = $2`, workspaceId, url, critical)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetTags заменяет теги ссылки, теги сохраняются в виде NormalizeTags
+func (p *Ping) SetTags(workspaceId int64, url string, tags []string) error {
+	const op = "storage.postgres.repository.ping.SetTags"
+
+	_, err := p.connection.DB().Exec(`update ping set tags = $3 where workspace_id = $1 and url = $2`, workspaceId, url, pq.Array(model.NormalizeTags(tags)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Snooze отключает уведомления по ссылке пространства на указанное время. Откладывание хранится
+// так же как отключение в mute_until, поэтому видно в /mute_list и снимается /unmute_url.
+// Бессрочное или более долгое отключение не сокращается, в этом случае возвращается storage.ErrAlreadyMuted.
+// userId и chatId - кто и в каком чате отложил уведомления, туда придет сообщение об окончании
+func (p *Ping) Snooze(ctx context.Context, workspaceId, pingId int64, duration time.Duration, userId, chatId int64) error {
+	const op = "storage.postgres.repository.ping.Snooze"
+
+	res, err := p.connection.DB().ExecContext(ctx, `
+		update ping set mute = true, mute_until = $1, muted_by = $4, mute_chat_id = $5
+		where workspace_id = $2 and id = $3 and not (mute and (mute_until is null or mute_until >= $1))`,
+		time.Now().Add(duration), workspaceId, pingId, userId, chatId,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAlreadyMuted)
+	}
+
+	return nil
+}
+
+// IsMuted проверяет отключены ли уведомления по ссылке или у получателя с учетом времени окончания отключения,
+// userId = 0 - получатель не пользователь (групповой чат), проверяется только ссылка
+func (p *Ping) IsMuted(ctx context.Context, pingId, userId int64) (bool, error) {
+	const op = "storage.postgres.repository.ping.IsMuted"
+
+	var count int
+	err := p.connection.DB().QueryRowContext(ctx, `
+		select count(*) from ping as p
+		left join users as u on u.id = $2
+		where p.id = $1 and (
+			(p.mute and (p.mute_until is null or p.mute_until > now()))
+			or (u.mute and (u.mute_until is null or u.mute_until > now()))
+		)`, pingId, userId,
+	).Scan(&count)
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count > 0, nil
+}
+
+// MutedUrlList список ссылок пространства с отключенными уведомлениями
+func (p *Ping) MutedUrlList(workspaceId int64) (model.PingList, error) {
+	const op = "storage.postgres.repository.ping.MutedUrlList"
+
+	rows, err := p.connection.DB().Query(selectPing+`
+		where p.workspace_id = $1 and p.mute order by p.mute_until nulls first`, workspaceId,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var links model.PingList
+	var link model.Ping
+	for rows.Next() {
+		if err := scanPing(rows, &link); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// ReleaseExpiredMutes включает уведомления по ссылкам у которых истекло время отключения и возвращает их
+// вместе с тем, кто отключил уведомления. Для отключений без этих данных получателем считается тот, кто добавил ссылку
+func (p *Ping) ReleaseExpiredMutes() (model.PingList, error) {
+	const op = "storage.postgres.repository.ping.ReleaseExpiredMutes"
+
+	// в FROM строка old содержит значения до обновления
+	rows, err := p.connection.DB().Query(`
+		update ping p set mute = false, mute_until = null, muted_by = null, mute_chat_id = null
+		from ping old
+		join users u on u.id = coalesce(old.muted_by, old.user_id)
+		where old.id = p.id and p.mute and p.mute_until <= now()
+		returning p.id, p.user_id, p.workspace_id, p.url, u.id, coalesce(old.mute_chat_id, u.id), u.language`,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var links model.PingList
+	var link model.Ping
+	for rows.Next() {
+		if err := rows.Scan(&link.Id, &link.UserId, &link.WorkspaceId, &link.Url, &link.MutedBy, &link.MuteChatId, &link.User.Language); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// tagsParam параметр запроса для тегов из PingPatch, nil - теги не меняются
+func tagsParam(tags *[]string) any {
+	if tags == nil {
+		return nil
+	}
+
+	return pq.Array(model.NormalizeTags(*tags))
+}
+
+func scanPing(row rowScanner, link *model.Ping) error {
+	return row.Scan(
+		&link.Id,
+		&link.Url,
+		&link.UserId,
+		&link.WorkspaceId,
+		&link.ConnectionTime,
+		&link.PingTime,
+		&link.Mute,
+		&link.MuteUntil,
+		&link.Critical,
+		pq.Array(&link.Tags),
+		&link.RepeatAlert,
+		&link.User.Id,
+		&link.User.Login,
+		&link.User.Mute,
+		&link.User.MuteUntil,
+	)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+)
+
+type Template struct {
+	connection kernel.DBConnection
+}
+
+func NewTemplate(db kernel.DBConnection) *Template {
+	return &Template{connection: db}
+}
+
+// AlertTemplate шаблон уведомления пользователя, пустая строка - шаблон не задан
+func (t *Template) AlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) (string, error) {
+	const op = "storage.postgres.repository.template.AlertTemplate"
+
+	var body string
+	err := t.connection.DB().QueryRowContext(ctx, `select body from alert_template where user_id = $1 and channel = $2 and kind = $3`, userId, channel, kind).Scan(&body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return body, nil
+}
+
+func (t *Template) SaveAlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind, body string) error {
+	const op = "storage.postgres.repository.template.SaveAlertTemplate"
+
+	_, err := t.connection.DB().ExecContext(ctx, `
+		INSERT INTO alert_template(user_id, channel, kind, body) VALUES($1, $2, $3, $4)
+		ON CONFLICT (user_id, channel, kind) DO UPDATE SET body = excluded.body`,
+		userId, channel, kind, body,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (t *Template) DeleteAlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) error {
+	const op = "storage.postgres.repository.template.DeleteAlertTemplate"
+
+	_, err := t.connection.DB().ExecContext(ctx, `delete from alert_template where user_id = $1 and channel = $2 and kind = $3`, userId, channel, kind)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
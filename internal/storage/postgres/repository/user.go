@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/ivankoTut/ping-url/internal/kernel"
 	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"github.com/lib/pq"
 	"net/http"
 	"time"
 )
@@ -33,46 +36,204 @@ func (u *User) UserExist(ctx context.Context, userId int64) (bool, error) {
 	return count > 0, err
 }
 
+// UserSave регистрирует пользователя вместе с его личным пространством
 func (u *User) UserSave(ctx context.Context, userId int64, login string) error {
 	const op = "storage.postgres.repository.user.UserSave"
 
-	stmt, err := u.connection.DB().Prepare("INSERT INTO users(id, login) VALUES($1, $2)")
+	tx, err := u.connection.DB().BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	_, err = stmt.Exec(userId, login)
+	if _, err := tx.ExecContext(ctx, "INSERT INTO users(id, login) VALUES($1, $2)", userId, login); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	workspaceId, err := createWorkspace(ctx, tx, userId, login)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if _, err := tx.ExecContext(ctx, "update users set workspace_id = $1 where id = $2", workspaceId, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
-func (u *User) Mute(ctx context.Context, userId int64) error {
-	return u.muteUnmute(ctx, userId, true)
+// Mute отключает все уведомления пользователя, until = nil - бессрочно
+func (u *User) Mute(ctx context.Context, userId int64, until *time.Time) error {
+	return u.muteUnmute(ctx, userId, true, until)
 }
 
 func (u *User) Unmute(ctx context.Context, userId int64) error {
-	return u.muteUnmute(ctx, userId, false)
+	return u.muteUnmute(ctx, userId, false, nil)
 }
 
-func (u *User) muteUnmute(ctx context.Context, userId int64, mute bool) error {
+func (u *User) muteUnmute(ctx context.Context, userId int64, mute bool, until *time.Time) error {
 	const op = "storage.postgres.repository.user.muteUnmute"
 
-	stmt, err := u.connection.DB().Prepare("update users set mute = $1 where id = $2")
+	stmt, err := u.connection.DB().Prepare("update users set mute = $1, mute_until = $2 where id = $3")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = stmt.Exec(mute, until, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (u *User) UserById(ctx context.Context, userId int64) (model.User, error) {
+	const op = "storage.postgres.repository.user.UserById"
+
+	var user model.User
+	err := u.connection.DB().QueryRowContext(ctx, "SELECT id, login, mute, mute_until, timezone, language FROM users WHERE id = $1", userId).
+		Scan(&user.Id, &user.Login, &user.Mute, &user.MuteUntil, &user.Timezone, &user.Language)
+
 	if err != nil {
+		return user, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// ReleaseExpiredMutes включает уведомления пользователям у которых истекло время отключения и возвращает их
+func (u *User) ReleaseExpiredMutes(ctx context.Context) ([]model.User, error) {
+	const op = "storage.postgres.repository.user.ReleaseExpiredMutes"
+
+	rows, err := u.connection.DB().QueryContext(ctx, `
+		update users set mute = false, mute_until = null
+		where mute and mute_until <= now()
+		returning id, login, language`,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.Id, &user.Login, &user.Language); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (u *User) SaveTimezone(ctx context.Context, userId int64, timezone string) error {
+	const op = "storage.postgres.repository.user.SaveTimezone"
+
+	_, err := u.connection.DB().ExecContext(ctx, "update users set timezone = $1 where id = $2", timezone, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveLanguage сохраняет выбранный пользователем язык, пустая строка - язык телеграма
+func (u *User) SaveLanguage(ctx context.Context, userId int64, language string) error {
+	const op = "storage.postgres.repository.user.SaveLanguage"
+
+	_, err := u.connection.DB().ExecContext(ctx, "update users set language = $1 where id = $2", language, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserLanguage язык выбранный пользователем, для незарегистрированного пользователя пустая строка
+func (u *User) UserLanguage(ctx context.Context, userId int64) (string, error) {
+	const op = "storage.postgres.repository.user.UserLanguage"
+
+	var language string
+	err := u.connection.DB().QueryRowContext(ctx, "SELECT language FROM users WHERE id = $1", userId).Scan(&language)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return language, nil
+}
+
+// SaveQuietHours полностью заменяет тихие часы пользователя переданным списком
+func (u *User) SaveQuietHours(ctx context.Context, userId int64, list model.QuietHoursList) error {
+	const op = "storage.postgres.repository.user.SaveQuietHours"
+
+	tx, err := u.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "delete from quiet_hours where user_id = $1", userId); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	_, err = stmt.Exec(mute, userId)
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO quiet_hours(user_id, weekday, start_minute, end_minute) VALUES($1, $2, $3, $4)")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	for _, q := range list {
+		if _, err := stmt.ExecContext(ctx, userId, q.Weekday, q.Start, q.End); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
+func (u *User) NotificationSettings(ctx context.Context, userId int64) (model.NotificationSettings, error) {
+	const op = "storage.postgres.repository.user.NotificationSettings"
+
+	settings := model.NotificationSettings{Timezone: model.DefaultTimezone}
+	err := u.connection.DB().QueryRowContext(ctx, "SELECT coalesce(timezone, $2), language FROM users WHERE id = $1", userId, model.DefaultTimezone).
+		Scan(&settings.Timezone, &settings.Language)
+
+	if err != nil {
+		return settings, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := u.connection.DB().QueryContext(ctx, "SELECT weekday, start_minute, end_minute FROM quiet_hours WHERE user_id = $1 ORDER BY weekday", userId)
+	if err != nil {
+		return settings, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var q model.QuietHours
+		if err := rows.Scan(&q.Weekday, &q.Start, &q.End); err != nil {
+			return settings, fmt.Errorf("%s: %w", op, err)
+		}
+
+		settings.QuietHours = append(settings.QuietHours, q)
+	}
+
+	return settings, nil
+}
+
 func (u *User) UserFromRequest(r *http.Request) (*model.User, error) {
 	const apiKeY = "api-key"
 	var key string
@@ -90,13 +251,17 @@ func (u *User) UserFromRequest(r *http.Request) (*model.User, error) {
 		return nil, errors.New("не передан заголовок содержащий api ключ доступа")
 	}
 
-	stmt, err := u.connection.DB().Prepare("SELECT id, login, mute FROM users WHERE api_key = $1")
+	// ключ дает доступ только к пространству в котором был создан и только пока пользователь в нем состоит и не заблокирован
+	stmt, err := u.connection.DB().Prepare(`
+		SELECT u.id, u.login, u.mute, coalesce(u.timezone, $2), u.language, m.workspace_id, m.user_id, m.role FROM users u
+		INNER JOIN workspace_member m ON m.workspace_id = u.api_workspace_id AND m.user_id = u.id
+		WHERE u.api_key = $1 AND NOT u.blocked`)
 	if err != nil {
 		return nil, fmt.Errorf("prepare statement: %w", err)
 	}
 
 	var user model.User
-	err = stmt.QueryRow(key).Scan(&user.Id, &user.Login, &user.Mute)
+	err = stmt.QueryRow(key, model.DefaultTimezone).Scan(&user.Id, &user.Login, &user.Mute, &user.Timezone, &user.Language, &user.Workspace.WorkspaceId, &user.Workspace.UserId, &user.Workspace.Role)
 
 	if user.Id == 0 {
 		return nil, errors.New("пользователь не найден")
@@ -105,13 +270,14 @@ func (u *User) UserFromRequest(r *http.Request) (*model.User, error) {
 	return &user, err
 }
 
+// ApiKey создает новый ключ апи, ключ дает доступ к текущему пространству пользователя
 func (u *User) ApiKey(ctx context.Context, userId int64) (string, error) {
 	const op = "storage.postgres.repository.user.ApiKey"
 
 	sum := sha256.Sum256([]byte(fmt.Sprintf("%s-%d", time.Now().String(), userId)))
 	keyApi := fmt.Sprintf("%x", sum)
 
-	stmt, err := u.connection.DB().Prepare("update users set api_key = $1 where id = $2")
+	stmt, err := u.connection.DB().Prepare("update users set api_key = $1, api_workspace_id = workspace_id where id = $2")
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -123,3 +289,109 @@ func (u *User) ApiKey(ctx context.Context, userId int64) (string, error) {
 
 	return keyApi, nil
 }
+
+// SyncAdmins делает администраторами только переданных пользователей, остальные роль теряют
+func (u *User) SyncAdmins(ctx context.Context, userIds []int64) error {
+	const op = "storage.postgres.repository.user.SyncAdmins"
+
+	_, err := u.connection.DB().ExecContext(ctx, "update users set is_admin = (id = ANY($1)) where is_admin <> (id = ANY($1))", pq.Array(userIds))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// BlockedUserIds заблокированные пользователи
+func (u *User) BlockedUserIds(ctx context.Context) ([]int64, error) {
+	const op = "storage.postgres.repository.user.BlockedUserIds"
+
+	rows, err := u.connection.DB().QueryContext(ctx, "SELECT id FROM users WHERE blocked")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// SetBlocked блокирует или разблокирует пользователя, для незарегистрированного пользователя storage.ErrUserNotFound
+func (u *User) SetBlocked(ctx context.Context, userId int64, blocked bool) error {
+	const op = "storage.postgres.repository.user.SetBlocked"
+
+	res, err := u.connection.DB().ExecContext(ctx, "update users set blocked = $1 where id = $2", blocked, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// AdminUserList все пользователи с кол-вом добавленных ими ссылок, сначала администраторы
+func (u *User) AdminUserList(ctx context.Context) (model.AdminUserList, error) {
+	const op = "storage.postgres.repository.user.AdminUserList"
+
+	rows, err := u.connection.DB().QueryContext(ctx, `
+		SELECT u.id, coalesce(u.login, ''), u.is_admin, u.blocked, count(p.id) FROM users u
+		LEFT JOIN ping p ON p.user_id = u.id
+		GROUP BY u.id
+		ORDER BY u.is_admin DESC, u.id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list model.AdminUserList
+	for rows.Next() {
+		var user model.AdminUser
+		if err := rows.Scan(&user.Id, &user.Login, &user.IsAdmin, &user.Blocked, &user.Monitors); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, user)
+	}
+
+	return list, nil
+}
+
+// BroadcastRecipients незаблокированные пользователи, которым отправляются объявления
+func (u *User) BroadcastRecipients(ctx context.Context) ([]model.User, error) {
+	const op = "storage.postgres.repository.user.BroadcastRecipients"
+
+	rows, err := u.connection.DB().QueryContext(ctx, "SELECT id, coalesce(login, ''), language FROM users WHERE NOT blocked ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.Id, &user.Login, &user.Language); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
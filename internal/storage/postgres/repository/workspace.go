@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"time"
+)
+
+type Workspace struct {
+	connection kernel.DBConnection
+}
+
+func NewWorkspace(db kernel.DBConnection) *Workspace {
+	return &Workspace{connection: db}
+}
+
+// CurrentMember текущее пространство пользователя в боте вместе с его ролью
+func (w *Workspace) CurrentMember(ctx context.Context, userId int64) (model.Member, error) {
+	const op = "storage.postgres.repository.workspace.CurrentMember"
+
+	var member model.Member
+	err := w.connection.DB().QueryRowContext(ctx, `
+		SELECT m.workspace_id, m.user_id, coalesce(u.login, ''), m.role FROM users u
+		INNER JOIN workspace_member m ON m.workspace_id = u.workspace_id AND m.user_id = u.id
+		WHERE u.id = $1`, userId,
+	).Scan(&member.WorkspaceId, &member.UserId, &member.Login, &member.Role)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return member, fmt.Errorf("%s: %w", op, storage.ErrNotMember)
+	}
+
+	if err != nil {
+		return member, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+// WorkspaceList пространства в которых состоит пользователь
+func (w *Workspace) WorkspaceList(ctx context.Context, userId int64) ([]model.Workspace, error) {
+	const op = "storage.postgres.repository.workspace.WorkspaceList"
+
+	rows, err := w.connection.DB().QueryContext(ctx, `
+		SELECT w.id, w.name, m.role FROM workspace w
+		INNER JOIN workspace_member m ON m.workspace_id = w.id
+		WHERE m.user_id = $1 ORDER BY w.id`, userId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []model.Workspace
+	for rows.Next() {
+		var workspace model.Workspace
+		if err := rows.Scan(&workspace.Id, &workspace.Name, &workspace.Role); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, workspace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// CreateWorkspace создает пространство, делает пользователя его владельцем и переключает на него
+func (w *Workspace) CreateWorkspace(ctx context.Context, userId int64, name string) (int64, error) {
+	const op = "storage.postgres.repository.workspace.CreateWorkspace"
+
+	tx, err := w.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	id, err := createWorkspace(ctx, tx, userId, name)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET workspace_id = $1 WHERE id = $2`, id, userId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SwitchWorkspace делает пространство текущим для пользователя, пользователь должен в нем состоять
+func (w *Workspace) SwitchWorkspace(ctx context.Context, userId, workspaceId int64) error {
+	const op = "storage.postgres.repository.workspace.SwitchWorkspace"
+
+	res, err := w.connection.DB().ExecContext(ctx, `
+		UPDATE users SET workspace_id = $2
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM workspace_member WHERE workspace_id = $2 AND user_id = $1)`,
+		userId, workspaceId,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotMember)
+	}
+
+	return nil
+}
+
+// Members участники пространства, сначала владельцы
+func (w *Workspace) Members(ctx context.Context, workspaceId int64) ([]model.Member, error) {
+	const op = "storage.postgres.repository.workspace.Members"
+
+	rows, err := w.connection.DB().QueryContext(ctx, `
+		SELECT m.workspace_id, m.user_id, coalesce(u.login, ''), m.role FROM workspace_member m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, u.login`, workspaceId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []model.Member
+	for rows.Next() {
+		var member model.Member
+		if err := rows.Scan(&member.WorkspaceId, &member.UserId, &member.Login, &member.Role); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// RemoveMember исключает участника из пространства, владельца исключить нельзя.
+// Если пространство было текущим у участника, он переключается на любое другое свое пространство
+func (w *Workspace) RemoveMember(ctx context.Context, workspaceId, userId int64) error {
+	const op = "storage.postgres.repository.workspace.RemoveMember"
+
+	tx, err := w.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM workspace_member WHERE workspace_id = $1 AND user_id = $2 AND role <> $3`, workspaceId, userId, model.RoleOwner)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotMember)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users SET workspace_id = (SELECT min(workspace_id) FROM workspace_member WHERE user_id = $2)
+		WHERE id = $2 AND workspace_id = $1`, workspaceId, userId,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET api_workspace_id = null WHERE id = $2 AND api_workspace_id = $1`, workspaceId, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateInvite создает приглашение в пространство с указанной ролью
+func (w *Workspace) CreateInvite(ctx context.Context, workspaceId, createdBy int64, role model.Role, ttl time.Duration) (model.Invite, error) {
+	const op = "storage.postgres.repository.workspace.CreateInvite"
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return model.Invite{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invite := model.Invite{
+		Token:       hex.EncodeToString(buf),
+		WorkspaceId: workspaceId,
+		Role:        role,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	_, err := w.connection.DB().ExecContext(ctx, `
+		INSERT INTO workspace_invite(token, workspace_id, role, created_by, expires_at) VALUES($1, $2, $3, $4, $5)`,
+		invite.Token, invite.WorkspaceId, invite.Role, createdBy, invite.ExpiresAt,
+	)
+	if err != nil {
+		return model.Invite{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invite, nil
+}
+
+// InviteExist есть ли действующее приглашение в пространство с таким токеном
+func (w *Workspace) InviteExist(ctx context.Context, token string) (bool, error) {
+	const op = "storage.postgres.repository.workspace.InviteExist"
+
+	var exist bool
+	err := w.connection.DB().QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM workspace_invite WHERE token = $1 AND expires_at > now())", token,
+	).Scan(&exist)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exist, nil
+}
+
+// AcceptInvite добавляет пользователя в пространство по приглашению и делает его текущим,
+// если пользователь уже состоит в пространстве его роль не меняется
+func (w *Workspace) AcceptInvite(ctx context.Context, userId int64, token string) (model.Workspace, error) {
+	const op = "storage.postgres.repository.workspace.AcceptInvite"
+
+	tx, err := w.connection.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return model.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var workspace model.Workspace
+	err = tx.QueryRowContext(ctx, `
+		SELECT w.id, w.name, i.role FROM workspace_invite i
+		INNER JOIN workspace w ON w.id = i.workspace_id
+		WHERE i.token = $1 AND i.expires_at > now()`, token,
+	).Scan(&workspace.Id, &workspace.Name, &workspace.Role)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return workspace, fmt.Errorf("%s: %w", op, storage.ErrInviteNotFound)
+	}
+
+	if err != nil {
+		return workspace, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO workspace_member(workspace_id, user_id, role) VALUES($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = workspace_member.role
+		RETURNING role`,
+		workspace.Id, userId, workspace.Role,
+	).Scan(&workspace.Role)
+	if err != nil {
+		return workspace, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET workspace_id = $1 WHERE id = $2`, workspace.Id, userId); err != nil {
+		return workspace, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return workspace, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspace, nil
+}
+
+// ConnectChat подключает групповой чат или канал к пространству для получения уведомлений
+func (w *Workspace) ConnectChat(ctx context.Context, workspaceId, chatId int64, title string) error {
+	const op = "storage.postgres.repository.workspace.ConnectChat"
+
+	_, err := w.connection.DB().ExecContext(ctx, `
+		INSERT INTO workspace_chat(workspace_id, chat_id, title) VALUES($1, $2, $3)
+		ON CONFLICT (workspace_id, chat_id) DO UPDATE SET title = excluded.title`,
+		workspaceId, chatId, title,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (w *Workspace) DisconnectChat(ctx context.Context, workspaceId, chatId int64) error {
+	const op = "storage.postgres.repository.workspace.DisconnectChat"
+
+	_, err := w.connection.DB().ExecContext(ctx, `DELETE FROM workspace_chat WHERE workspace_id = $1 AND chat_id = $2`, workspaceId, chatId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Chats групповые чаты и каналы подключенные к пространству
+func (w *Workspace) Chats(ctx context.Context, workspaceId int64) ([]model.WorkspaceChat, error) {
+	const op = "storage.postgres.repository.workspace.Chats"
+
+	rows, err := w.connection.DB().QueryContext(ctx, `SELECT workspace_id, chat_id, title FROM workspace_chat WHERE workspace_id = $1 ORDER BY title`, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []model.WorkspaceChat
+	for rows.Next() {
+		var chat model.WorkspaceChat
+		if err := rows.Scan(&chat.WorkspaceId, &chat.ChatId, &chat.Title); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, chat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// Recipients все получатели уведомлений пространства: участники и подключенные чаты
+func (w *Workspace) Recipients(ctx context.Context, workspaceId int64) ([]model.Recipient, error) {
+	const op = "storage.postgres.repository.workspace.Recipients"
+
+	rows, err := w.connection.DB().QueryContext(ctx, `
+		SELECT user_id, user_id FROM workspace_member WHERE workspace_id = $1
+		UNION ALL
+		SELECT chat_id, 0 FROM workspace_chat WHERE workspace_id = $1`, workspaceId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var list []model.Recipient
+	for rows.Next() {
+		var recipient model.Recipient
+		if err := rows.Scan(&recipient.ChatId, &recipient.UserId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		list = append(list, recipient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// createWorkspace создает пространство и делает пользователя его владельцем в рамках транзакции
+func createWorkspace(ctx context.Context, tx *sql.Tx, userId int64, name string) (int64, error) {
+	var id int64
+	if err := tx.QueryRowContext(ctx, `INSERT INTO workspace(name, created_by) VALUES($1, $2) RETURNING id`, name, userId).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO workspace_member(workspace_id, user_id, role) VALUES($1, $2, $3)`, id, userId, model.RoleOwner); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
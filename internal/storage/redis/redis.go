@@ -7,11 +7,8 @@ import (
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"log"
-	"time"
 )
 
-const TtlTime = time.Hour * 12
-
 type ClientRedis struct {
 	rdb *redis.Client
 	cfg *config.Config
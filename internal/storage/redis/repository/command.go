@@ -4,20 +4,47 @@ import (
 	"context"
 	r "github.com/ivankoTut/ping-url/internal/storage/redis"
 	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
 )
 
+// dialogDeadlineKey отсортированное множество начатых диалогов, score - время после которого диалог считается брошенным
+const dialogDeadlineKey = "dialog_deadline"
+
+// expireDialogsScript забирает брошенные диалоги и удаляет их данные за один шаг,
+// иначе ответ пользователя между выборкой и удалением мог бы потеряться
+var expireDialogsScript = redis.NewScript(`
+local keys = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, key in ipairs(keys) do
+	redis.call('ZREM', KEYS[1], key)
+	redis.call('DEL', key, key .. '_answer')
+end
+return keys
+`)
+
 type CommandRepository struct {
-	cr *r.ClientRedis
+	cr      *r.ClientRedis
+	timeout time.Duration // сколько диалог может ждать ответа
 }
 
-func NewCommandRepository(cr *r.ClientRedis) *CommandRepository {
+func NewCommandRepository(cr *r.ClientRedis, timeout time.Duration) *CommandRepository {
 	return &CommandRepository{
-		cr: cr,
+		cr:      cr,
+		timeout: timeout,
 	}
 }
 
+// SaveState сохраняет шаг диалога и продлевает время ожидания ответа
 func (c *CommandRepository) SaveState(ctx context.Context, key string, state int) (bool, error) {
-	return true, c.cr.Client().Set(ctx, key, state, r.TtlTime).Err()
+	cli := c.cr.Client()
+	_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, state, c.timeout)
+		pipe.ZAdd(ctx, dialogDeadlineKey, redis.Z{Score: float64(time.Now().Add(c.timeout).Unix()), Member: key})
+
+		return nil
+	})
+
+	return err == nil, err
 }
 
 func (c *CommandRepository) DialogExist(ctx context.Context, key string) (bool, error) {
@@ -43,7 +70,7 @@ func (c *CommandRepository) SaveAnswer(ctx context.Context, key, state string, a
 		return err
 	}
 
-	return cli.Expire(ctx, key, r.TtlTime).Err()
+	return cli.Expire(ctx, key, c.timeout).Err()
 }
 
 func (c *CommandRepository) GetAnswer(ctx context.Context, key string) (map[string]string, error) {
@@ -51,5 +78,20 @@ func (c *CommandRepository) GetAnswer(ctx context.Context, key string) (map[stri
 }
 
 func (c *CommandRepository) DeleteDialog(ctx context.Context, key string) error {
-	return c.cr.Client().Del(ctx, key).Err()
+	cli := c.cr.Client()
+	_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.ZRem(ctx, dialogDeadlineKey, key)
+
+		return nil
+	})
+
+	return err
+}
+
+// ReleaseExpiredDialogs удаляет диалоги которые ждут ответа дольше допустимого и возвращает их ключи
+func (c *CommandRepository) ReleaseExpiredDialogs(ctx context.Context, now time.Time) ([]string, error) {
+	cli := c.cr.Client()
+
+	return expireDialogsScript.Run(ctx, &cli, []string{dialogDeadlineKey}, strconv.FormatInt(now.Unix(), 10)).StringSlice()
 }
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	r "github.com/ivankoTut/ping-url/internal/storage/redis"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+)
+
+// digestUsersKey множество пользователей у которых есть отложенные уведомления
+const digestUsersKey = "quiet_digest_users"
+
+// DigestRepository хранит уведомления отложенные на время тихих часов
+type DigestRepository struct {
+	cr *r.ClientRedis
+}
+
+func NewDigestRepository(cr *r.ClientRedis) *DigestRepository {
+	return &DigestRepository{
+		cr: cr,
+	}
+}
+
+func (d *DigestRepository) Hold(ctx context.Context, userId int64, text string) error {
+	cli := d.cr.Client()
+	_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, d.key(userId), text)
+		pipe.SAdd(ctx, digestUsersKey, userId)
+
+		return nil
+	})
+
+	return err
+}
+
+// Users список пользователей у которых есть отложенные уведомления
+func (d *DigestRepository) Users(ctx context.Context) ([]int64, error) {
+	members, err := d.cr.Client().SMembers(ctx, digestUsersKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, id)
+	}
+
+	return users, nil
+}
+
+// Withdraw забирает все отложенные уведомления пользователя и очищает их
+func (d *DigestRepository) Withdraw(ctx context.Context, userId int64) ([]string, error) {
+	var list *redis.StringSliceCmd
+
+	cli := d.cr.Client()
+	_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		list = pipe.LRange(ctx, d.key(userId), 0, -1)
+		pipe.Del(ctx, d.key(userId))
+		pipe.SRem(ctx, digestUsersKey, userId)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Val(), nil
+}
+
+func (d *DigestRepository) key(userId int64) string {
+	return fmt.Sprintf("quiet_digest_%d", userId)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	r "github.com/ivankoTut/ping-url/internal/storage/redis"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// outboxReadyKey отсортированное множество чатов с исходящими сообщениями, score - время когда в чат можно отправлять
+const outboxReadyKey = "outbox_ready"
+
+// releaseScript убирает чат из очереди только если у него не осталось сообщений,
+// иначе можно потерять сообщение добавленное между проверкой и удалением
+var releaseScript = redis.NewScript(`
+if redis.call('LLEN', KEYS[1]) == 0 then
+	return redis.call('ZREM', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// OutboxRepository хранит исходящие сообщения бота, чтобы они переживали перезапуск приложения
+type OutboxRepository struct {
+	cr *r.ClientRedis
+}
+
+func NewOutboxRepository(cr *r.ClientRedis) *OutboxRepository {
+	return &OutboxRepository{
+		cr: cr,
+	}
+}
+
+// Push добавляет сообщение в очередь чата, время отправки в чат может только приблизиться
+func (o *OutboxRepository) Push(ctx context.Context, chatId int64, payload string, readyAt time.Time) error {
+	cli := o.cr.Client()
+	_, err := cli.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, o.key(chatId), payload)
+		pipe.ZAddArgs(ctx, outboxReadyKey, redis.ZAddArgs{
+			LT:      true,
+			Members: []redis.Z{{Score: float64(readyAt.UnixMilli()), Member: chatId}},
+		})
+
+		return nil
+	})
+
+	return err
+}
+
+// ReadyChats чаты в которые уже можно отправлять сообщения
+func (o *OutboxRepository) ReadyChats(ctx context.Context, now time.Time, limit int64) ([]int64, error) {
+	members, err := o.cr.Client().ZRangeByScore(ctx, outboxReadyKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: limit,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	chats := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		chats = append(chats, id)
+	}
+
+	return chats, nil
+}
+
+// Pending первые limit сообщений из очереди чата
+func (o *OutboxRepository) Pending(ctx context.Context, chatId int64, limit int64) ([]string, error) {
+	return o.cr.Client().LRange(ctx, o.key(chatId), 0, limit-1).Result()
+}
+
+// Update перезаписывает сообщение в очереди чата, например чтобы сохранить кол-во попыток отправки
+func (o *OutboxRepository) Update(ctx context.Context, chatId int64, index int64, payload string) error {
+	return o.cr.Client().LSet(ctx, o.key(chatId), index, payload).Err()
+}
+
+// Remove удаляет первые count сообщений из очереди чата
+func (o *OutboxRepository) Remove(ctx context.Context, chatId int64, count int64) error {
+	return o.cr.Client().LTrim(ctx, o.key(chatId), count, -1).Err()
+}
+
+// Schedule откладывает отправку в чат до указанного времени
+func (o *OutboxRepository) Schedule(ctx context.Context, chatId int64, at time.Time) error {
+	return o.cr.Client().ZAdd(ctx, outboxReadyKey, redis.Z{Score: float64(at.UnixMilli()), Member: chatId}).Err()
+}
+
+// Release убирает чат из очереди если в нем не осталось сообщений
+func (o *OutboxRepository) Release(ctx context.Context, chatId int64) error {
+	cli := o.cr.Client()
+
+	return releaseScript.Run(ctx, &cli, []string{o.key(chatId), outboxReadyKey}, chatId).Err()
+}
+
+// Backlog кол-во чатов с неотправленными сообщениями и самое раннее время отправки среди них,
+// для пустой очереди нулевое время
+func (o *OutboxRepository) Backlog(ctx context.Context) (int64, time.Time, error) {
+	cli := o.cr.Client()
+
+	chats, err := cli.ZCard(ctx, outboxReadyKey).Result()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	first, err := cli.ZRangeWithScores(ctx, outboxReadyKey, 0, 0).Result()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	if len(first) == 0 {
+		return chats, time.Time{}, nil
+	}
+
+	return chats, time.UnixMilli(int64(first[0].Score)), nil
+}
+
+func (o *OutboxRepository) key(chatId int64) string {
+	return fmt.Sprintf("outbox_%d", chatId)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/model"
+	r "github.com/ivankoTut/ping-url/internal/storage/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// StateRepository хранит текущее состояние ссылок, чтобы понимать когда оно меняется
+type StateRepository struct {
+	cr *r.ClientRedis
+}
+
+func NewStateRepository(cr *r.ClientRedis) *StateRepository {
+	return &StateRepository{
+		cr: cr,
+	}
+}
+
+// MonitorState текущее состояние ссылки, false - ссылку еще не опрашивали
+func (s *StateRepository) MonitorState(ctx context.Context, pingId int64) (model.MonitorState, bool, error) {
+	var state model.MonitorState
+
+	data, err := s.cr.Client().Get(ctx, s.key(pingId)).Bytes()
+	if err == redis.Nil {
+		return state, false, nil
+	}
+
+	if err != nil {
+		return state, false, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, false, err
+	}
+
+	return state, true, nil
+}
+
+func (s *StateRepository) SaveMonitorState(ctx context.Context, state model.MonitorState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return s.cr.Client().Set(ctx, s.key(state.PingId), data, 0).Err()
+}
+
+func (s *StateRepository) key(pingId int64) string {
+	return fmt.Sprintf("monitor_state_%d", pingId)
+}
+
+// MonitorStates текущее состояние нескольких ссылок одним запросом, ссылки которые еще не опрашивали в результат не попадают
+func (s *StateRepository) MonitorStates(ctx context.Context, pingIds []int64) (map[int64]model.MonitorState, error) {
+	states := make(map[int64]model.MonitorState, len(pingIds))
+	if len(pingIds) == 0 {
+		return states, nil
+	}
+
+	keys := make([]string, 0, len(pingIds))
+	for _, id := range pingIds {
+		keys = append(keys, s.key(id))
+	}
+
+	values, err := s.cr.Client().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var state model.MonitorState
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return nil, err
+		}
+
+		states[state.PingId] = state
+	}
+
+	return states, nil
+}
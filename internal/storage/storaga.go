@@ -9,5 +9,12 @@ const (
 )
 
 var (
-	ErrUserExists = errors.New("пользователь уже зарегестрирован")
+	ErrUserExists       = errors.New("пользователь уже зарегестрирован")
+	ErrIncidentNotFound = errors.New("инцидент не найден")
+	ErrNotMember        = errors.New("пользователь не является участником пространства")
+	ErrInviteNotFound   = errors.New("приглашение не найдено или истекло")
+	ErrUrlNotFound      = errors.New("ссылка не найдена")
+	ErrUrlExists        = errors.New("ссылка уже существует")
+	ErrUserNotFound     = errors.New("пользователь не найден")
+	ErrAlreadyMuted     = errors.New("уведомления по ссылке уже отключены дольше")
 )
@@ -1,27 +1,39 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/ivankoTut/ping-url/internal/kernel"
+	"io"
 	"log"
+	"net/http"
 )
 
 type (
 	AccessUserProvider interface {
 		IsAccess(userId int64) bool
 	}
+
+	// MessageQueue очередь исходящих сообщений, через нее уходят все сообщения бота
+	MessageQueue interface {
+		Push(ctx context.Context, msg tgbotapi.MessageConfig, coalesce bool) error
+	}
+
 	Bot struct {
 		bot                *tgbotapi.BotAPI
 		cfg                tgbotapi.UpdateConfig
 		kernel             *kernel.Kernel
 		accessUserProvider AccessUserProvider
+		queue              MessageQueue
 		Command            chan *tgbotapi.Message
 		Message            chan *tgbotapi.Message
+		Callback           chan *tgbotapi.CallbackQuery
+		webhook            chan tgbotapi.Update // обновления пришедшие на webhook, см. ReceiveUpdate
 	}
 )
 
-func MustCreateBot(kernel *kernel.Kernel, accessUserProvider AccessUserProvider) *Bot {
+func MustCreateBot(kernel *kernel.Kernel, accessUserProvider AccessUserProvider, queue MessageQueue) *Bot {
 	bot, err := tgbotapi.NewBotAPI(kernel.Config().BotToken)
 	if err != nil {
 		log.Panic(err)
@@ -35,37 +47,183 @@ func MustCreateBot(kernel *kernel.Kernel, accessUserProvider AccessUserProvider)
 		cfg:                u,
 		kernel:             kernel,
 		accessUserProvider: accessUserProvider,
+		queue:              queue,
 		Command:            make(chan *tgbotapi.Message),
 		Message:            make(chan *tgbotapi.Message),
+		Callback:           make(chan *tgbotapi.CallbackQuery),
+		webhook:            make(chan tgbotapi.Update, kernel.Config().Telegram.Webhook.MaxConnections),
 	}
 }
 
+// StartListen получает обновления от телеграма через long polling или webhook, в зависимости от конфига,
+// в обоих режимах команды и сообщения попадают в одни и те же каналы
 func (b *Bot) StartListen() {
-	updates := b.bot.GetUpdatesChan(b.cfg)
+	var updates tgbotapi.UpdatesChannel
+	if b.kernel.Config().Telegram.IsWebhook() {
+		updates = b.listenWebhook()
+	} else {
+		updates = b.listenPolling()
+	}
+
+	for update := range updates {
+		b.handleUpdate(update)
+	}
+}
+
+// StopListen перестает получать обновления, в режиме webhook снимает его в телеграме,
+// иначе телеграм продолжит отправлять обновления на остановленный сервер
+func (b *Bot) StopListen() error {
+	if !b.kernel.Config().Telegram.IsWebhook() {
+		b.bot.StopReceivingUpdates()
+		return nil
+	}
+
+	_, err := b.bot.Request(tgbotapi.DeleteWebhookConfig{})
+
+	return err
+}
+
+// ReceiveUpdate принимает обновление пришедшее на webhook
+func (b *Bot) ReceiveUpdate(update tgbotapi.Update) {
+	b.webhook <- update
+}
+
+func (b *Bot) listenPolling() tgbotapi.UpdatesChannel {
+	// пока у бота установлен webhook, телеграм не отдает обновления через getUpdates
+	if _, err := b.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		b.kernel.Log().Error(fmt.Sprintf("delete webhook: %s", err))
+	}
 
 	b.kernel.Log().Debug("start listen bot command and message")
 
-	for update := range updates {
-		if update.Message == nil {
-			continue
-		}
+	return b.bot.GetUpdatesChan(b.cfg)
+}
 
-		if !b.accessUserProvider.IsAccess(update.Message.Chat.ID) {
-			b.kernel.Log().Info(fmt.Sprintf("not access for user: %d", update.Message.Chat.ID))
-			continue
-		}
+func (b *Bot) listenWebhook() tgbotapi.UpdatesChannel {
+	cfg := b.kernel.Config().Telegram
 
-		if update.Message.IsCommand() {
-			b.Command <- update.Message
-			continue
-		}
+	// библиотека не поддерживает secret_token, поэтому запрос собирается вручную
+	params := make(tgbotapi.Params)
+	params["url"] = cfg.WebhookUrl()
+	params["secret_token"] = cfg.Webhook.Secret
+	params.AddNonZero("max_connections", cfg.Webhook.MaxConnections)
 
-		b.Message <- update.Message
+	if _, err := b.bot.MakeRequest("setWebhook", params); err != nil {
+		log.Panic(err)
 	}
+
+	b.kernel.Log().Debug(fmt.Sprintf("start listen bot command and message on webhook %s", cfg.WebhookUrl()))
+
+	return b.webhook
+}
+
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		b.listenCallback(update.CallbackQuery)
+		return
+	}
+
+	if update.Message == nil {
+		return
+	}
+
+	// в групповых чатах доступ проверяется по отправителю, а не по чату
+	userId := update.Message.Chat.ID
+	if update.Message.From != nil {
+		userId = update.Message.From.ID
+	}
+
+	if !b.accessUserProvider.IsAccess(userId) {
+		b.kernel.Log().Info(fmt.Sprintf("not access for user: %d", userId))
+		return
+	}
+
+	if update.Message.IsCommand() {
+		b.Command <- update.Message
+		return
+	}
+
+	b.Message <- update.Message
 }
 
+// SendMessage ставит ответ пользователю в очередь на отправку
 func (b *Bot) SendMessage(msg tgbotapi.MessageConfig) error {
-	_, err := b.bot.Send(msg)
+	return b.queue.Push(context.Background(), msg, false)
+}
+
+// SendNotification ставит уведомление в очередь на отправку, несколько уведомлений подряд в один чат
+// будут объединены в одно сообщение
+func (b *Bot) SendNotification(msg tgbotapi.MessageConfig) error {
+	return b.queue.Push(context.Background(), msg, true)
+}
+
+// Send отправляет произвольный запрос в телеграм сразу, минуя очередь (редактирование сообщения, ответ на нажатие кнопки и тд)
+func (b *Bot) Send(c tgbotapi.Chattable) error {
+	_, err := b.bot.Request(c)
 
 	return err
 }
+
+// DownloadFile скачивает файл отправленный боту, файлы больше limit байт не скачиваются
+func (b *Bot) DownloadFile(fileId string, limit int64) ([]byte, error) {
+	const op = "telegram.bot.DownloadFile"
+
+	link, err := b.bot.GetFileDirectURL(fileId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := http.Get(link)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s: file is larger than %d bytes", op, limit)
+	}
+
+	return data, nil
+}
+
+// UserName имя бота, нужно для ссылок вида t.me/<bot>?start=<token>
+func (b *Bot) UserName() string {
+	return b.bot.Self.UserName
+}
+
+// Chat получает данные канала или группы по идентификатору или по @username, если идентификатор не указан
+func (b *Bot) Chat(chatId int64, username string) (tgbotapi.Chat, error) {
+	return b.bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: chatId, SuperGroupUsername: username}})
+}
+
+// IsChatAdmin является ли пользователь администратором канала или группы
+func (b *Bot) IsChatAdmin(chatId, userId int64) (bool, error) {
+	member, err := b.bot.GetChatMember(tgbotapi.GetChatMemberConfig{ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatId, UserID: userId}})
+	if err != nil {
+		return false, err
+	}
+
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
+
+func (b *Bot) listenCallback(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		return
+	}
+
+	if !b.accessUserProvider.IsAccess(query.From.ID) {
+		b.kernel.Log().Info(fmt.Sprintf("not access for user: %d", query.From.ID))
+		return
+	}
+
+	b.Callback <- query
+}
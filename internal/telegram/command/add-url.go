@@ -4,243 +4,174 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/redis/go-redis/v9"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
 	"net/url"
 	"time"
 )
 
-const stateAddUrlNone = -1
-
-const (
-	stateAddUrlBegin             = iota //Начало добавление ссылки
-	stateAddUrlAddConnectionTime        //максимальное время ожидания ответа по ссылке
-	stateAddUrlPingTime                 //Время через которое необходимо делать опрос по ссылке
-)
-
 const (
-	answerUrl            = "url"             // see stateAddUrlBegin
-	answerConnectionTime = "connection_time" // see stateAddUrlAddConnectionTime
-	answerPingTime       = "ping_time"       // see stateAddUrlPingTime
+	answerUrl            = "url"             // адрес ссылки
+	answerConnectionTime = "connection_time" // максимальное время ожидания ответа по ссылке
+	answerPingTime       = "ping_time"       // время через которое необходимо делать опрос по ссылке
 )
 
 type (
 	// UrlSaver этот интерфейс реализует возможность сохранения новой ссылки
 	UrlSaver interface {
-		SaveUrl(userId int64, url, connectionTime, pingTime string) error
-		UrlExist(userId int64, url string) (bool, error)
+		SaveUrl(workspaceId, userId int64, url, connectionTime, pingTime string) error
+		UrlExist(workspaceId int64, url string) (bool, error)
+		SetTags(workspaceId int64, url string, tags []string) error
 	}
 
 	// AddUrl структура для обработки команды добавления новой ссылки
 	AddUrl struct {
-		urlRepo   UrlSaver
-		dialog    DialogChain
-		state     int
-		questions []string
+		urlRepo UrlSaver
+		dialog  *Dialog
 	}
 )
 
 func NewAddUrlCommand(dialog DialogChain, urlRepo UrlSaver) *AddUrl {
-	return &AddUrl{
+	a := &AddUrl{
 		urlRepo: urlRepo,
-		dialog:  dialog,
-		questions: []string{
-			"Укажите url адрес",
-			"Укажите максимально время ожидания ответа, примеры: 100ms|10s|1h|1s500ms",
-			"Укажите время с какой периодичностью необходимо опрашивать ссылку в секундах (минимально 30), примеры: 30m20s|1h",
-		},
 	}
+
+	a.dialog = NewDialog(AddUrlCommand, dialog, editorFromContext, a.save,
+		DialogStep{Name: answerUrl, Prompt: dialogPrompt("add_url.ask_url"), Validate: a.validateUrl},
+		DialogStep{Name: answerConnectionTime, Flag: "timeout", Prompt: dialogPrompt("add_url.ask_connection_time"), Validate: validateDuration},
+		DialogStep{Name: answerPingTime, Flag: "interval", Prompt: dialogPrompt("add_url.ask_ping_time"), Validate: validateDuration, Parse: minPingTime},
+		// теги задаются только аргументом команды, в диалоге их можно изменить через /edit_url
+		DialogStep{Name: answerTags, Flag: "tag", Optional: true, Validate: validateTags},
+	)
+
+	return a
 }
 
 func (a *AddUrl) CommandName() string {
 	return AddUrlCommand
 }
 
-func (a *AddUrl) HelpText() string {
-	return "help text"
+func (a *AddUrl) Description(loc i18n.Locale) string {
+	return loc.T("add_url.description")
 }
 
-func (a *AddUrl) key(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s", message.Chat.ID, a.CommandName())
+func (a *AddUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("add_url.help")
 }
 
-func (a *AddUrl) keyAnswer(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s_answer", message.Chat.ID, a.CommandName())
+func (a *AddUrl) Flags() []string {
+	return a.dialog.Flags()
 }
 
 func (a *AddUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return a.dialog.IsSupport(ctx, message)
+}
 
-	if message.IsCommand() == true {
-		return message.Command() == a.CommandName(), nil
-	}
+func (a *AddUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return a.dialog.IsComplete(ctx, message)
+}
 
-	key := a.key(message)
+func (a *AddUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", a.CommandName()))
+	defer span.End()
 
-	is, err := a.dialog.DialogExist(ctx, key)
+	msg, err := a.dialog.Run(ctx, message)
 	if err != nil {
-		return false, err
+		span.RecordError(err)
 	}
 
-	return is, nil
+	return msg, err
 }
 
-func (a *AddUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-
-	key := a.key(message)
+// RunCallback кнопки возврата к предыдущему вопросу и отмены добавления
+func (a *AddUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", a.CommandName()))
+	defer span.End()
 
-	is, err := a.dialog.DialogExist(ctx, key)
+	msg, err := a.dialog.RunCallback(ctx, query)
 	if err != nil {
-		return false, err
+		span.RecordError(err)
 	}
 
-	return is == false, nil
+	return msg, err
 }
 
-func (a *AddUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
-	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", a.CommandName()))
+func (a *AddUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", a.CommandName()))
 	defer span.End()
-	key := a.key(message)
-	msg := tgbotapi.NewMessage(message.Chat.ID, "")
 
-	state, err := a.dialog.CurrentState(ctx, key)
-	if err != nil && err != redis.Nil {
-		msg.Text = "ошибка при попытке создать запись"
+	if err := a.dialog.Clear(ctx, message.Chat.ID); err != nil {
 		span.RecordError(err)
-		return msg, err
-	}
-
-	if err == redis.Nil {
-		err = nil
-		state = stateAddUrlNone
+		return err
 	}
 
-	var nextState int
-	switch state {
-	case stateAddUrlNone:
-		nextState = stateAddUrlBegin
-		msg.Text = a.questions[nextState]
-	case stateAddUrlBegin:
-		nextState = stateAddUrlAddConnectionTime
-
-		// валидация ссылки
-		u, errUrl := url.Parse(message.Text)
-		if errUrl != nil || (u.Scheme == "" || u.Host == "") {
-			msg.Text = "Не валидная ссылка, повторите ввод"
-			span.RecordError(errUrl)
-			return msg, errUrl
-		}
-
-		is, errExist := a.urlRepo.UrlExist(message.Chat.ID, message.Text)
-		if errExist != nil {
-			msg.Text = "Ошибка при проверке ссылки, повторите ввод"
-			span.RecordError(errExist)
-			return msg, errExist
-		}
-
-		if is {
-			msg.Text = "Данная ссылка уже существует"
-			return msg, nil
-		}
-
-		err = a.dialog.SaveAnswer(ctx, a.keyAnswer(message), answerUrl, message.Text)
-		if err != nil {
-			msg.Text = "ошибка при сохранении ссылки, повторите попытку"
-			nextState = stateAddUrlBegin
-		} else {
-			msg.Text = a.questions[nextState]
-		}
-	case stateAddUrlAddConnectionTime:
-		// валидация времени ожидания ответа от сервера
-		_, err = time.ParseDuration(message.Text)
-		if err != nil {
-			fmt.Println("sdf")
-			msg.Text = "указано неверное время, примеры: 100ms|10s|1h|1s500ms"
-
-			span.RecordError(err)
-
-			return msg, err
-		}
-
-		nextState = stateAddUrlPingTime
-		err = a.dialog.SaveAnswer(ctx, a.keyAnswer(message), answerConnectionTime, message.Text)
-		if err != nil {
-			msg.Text = "ошибка при сохранении время отклика, повторите попытку"
-			nextState = stateAddUrlAddConnectionTime
-		} else {
-			msg.Text = a.questions[nextState]
-		}
-	case stateAddUrlPingTime:
-		// валидация времени ожидания ответа от сервера
-		timer, errTime := time.ParseDuration(message.Text)
-		if errTime != nil {
-			msg.Text = "указано неверное время, примеры: 100ms|10s|1h|1s500ms"
-
-			span.RecordError(errTime)
+	return nil
+}
 
-			return msg, errTime
-		}
+// validateUrl адрес должен быть полным и еще не добавленным в пространство
+func (a *AddUrl) validateUrl(ctx context.Context, s *DialogState, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return i18n.NewError("add_url.invalid_url")
+	}
 
-		// проверяем на минимальное время повторений
-		if timer < time.Second*30 {
-			message.Text = "30s"
-		}
+	is, err := a.urlRepo.UrlExist(s.Member.WorkspaceId, value)
+	if err != nil {
+		return i18n.WrapError(err, "common.error_check")
+	}
 
-		err = a.dialog.SaveAnswer(ctx, a.keyAnswer(message), answerPingTime, message.Text)
-		if err != nil {
-			msg.Text = "ошибка при сохранении время повторения, повторите попытку"
-			nextState = stateAddUrlPingTime
-		} else {
-			err := a.saveUrl(ctx, message)
-			if err != nil {
-				msg.Text = "Произошла ошибка при сохранении, повторите позже"
-			} else {
-				msg.Text = "запись успешно добавлена"
-			}
+	if is {
+		return i18n.NewError("common.url_exists")
+	}
 
-			if err := a.ClearData(ctx, message); err != nil {
-				msg.Text = "Произошла ошибка, повторите позже"
+	return nil
+}
 
-				span.RecordError(err)
+func (a *AddUrl) save(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
 
-				return msg, err
-			}
+	err := a.urlRepo.SaveUrl(s.Member.WorkspaceId, s.Member.UserId, s.Answers[answerUrl], s.Answers[answerConnectionTime], s.Answers[answerPingTime])
+	if err != nil {
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
 
+	if tags, ok := s.Answers[answerTags]; ok {
+		if err := a.urlRepo.SetTags(s.Member.WorkspaceId, s.Answers[answerUrl], model.ParseTags(tags)); err != nil {
+			msg.Text = loc.T("common.error_save")
 			return msg, err
 		}
-	default:
-		nextState = stateAddUrlNone
-		msg.Text = a.questions[0]
 	}
 
-	_, errSave := a.dialog.SaveState(ctx, key, nextState)
-	if errSave != nil {
-		msg.Text = "ошибка при сохранении текущего шага"
+	msg.Text = loc.T("add_url.success")
 
-		span.RecordError(errSave)
+	return msg, nil
+}
 
-		return msg, errSave
+// validateDuration ответ должен быть временем вида 100ms|10s|1h|1s500ms
+func validateDuration(ctx context.Context, s *DialogState, value string) error {
+	if _, err := time.ParseDuration(value); err != nil {
+		return i18n.NewError("common.invalid_time")
 	}
 
-	return msg, err
+	return nil
 }
 
-func (a *AddUrl) saveUrl(ctx context.Context, message *tgbotapi.Message) error {
-
-	answers, err := a.dialog.GetAnswer(ctx, a.keyAnswer(message))
-	if err != nil {
-		return err
+// validateTags теги через пробел или запятую, проверяются так же как при изменении ссылки
+func validateTags(ctx context.Context, s *DialogState, value string) error {
+	if err := editUrlPatch(answerTags, value).Validate(); err != nil {
+		return i18n.NewError("edit_url.invalid_value", i18n.FromContext(ctx).Error(err))
 	}
 
-	return a.urlRepo.SaveUrl(message.Chat.ID, answers[answerUrl], answers[answerConnectionTime], answers[answerPingTime])
+	return nil
 }
 
-func (a *AddUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
-	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", a.CommandName()))
-	defer span.End()
-
-	if err := a.dialog.DeleteDialog(ctx, a.key(message)); err != nil {
-		span.RecordError(err)
-		return err
+// minPingTime периодичность опроса не может быть меньше model.MinPingTime
+func minPingTime(value string) string {
+	if timer, _ := time.ParseDuration(value); timer < model.MinPingTime {
+		return model.MinPingTime.String()
 	}
 
-	return a.dialog.DeleteDialog(ctx, a.keyAnswer(message))
+	return value
 }
@@ -0,0 +1,452 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	adminActionUsers     = "users"
+	adminActionBlock     = "block"
+	adminActionUnblock   = "unblock"
+	adminActionBroadcast = "broadcast"
+	adminActionStats     = "stats"
+	adminActionAccess    = "access"
+	adminActionAllow     = "allow"
+	adminActionDisallow  = "disallow"
+	adminActionInvite    = "invite"
+	adminActionInvites   = "invites"
+	adminActionRevoke    = "revoke"
+
+	// accessCodeMaxUses ограничение кол-ва использований одного кода приглашения
+	accessCodeMaxUses = 1000
+	// accessCodeTtl сколько действует код приглашения, если срок не указан
+	accessCodeTtl = time.Hour * 24 * 7
+	// accessCodeMaxTtl максимальный срок действия кода приглашения
+	accessCodeMaxTtl = time.Hour * 24 * 365
+	// adminStatsCommands сколько самых частых команд выводить в состоянии приложения
+	adminStatsCommands = 10
+	// accessCodeTimeFormat формат срока действия кода приглашения
+	accessCodeTimeFormat = "02.01.2006 15:04 UTC"
+)
+
+type (
+	// AdminProvider этот интерфейс реализует возможность проверить является ли пользователь администратором
+	AdminProvider interface {
+		IsAdmin(userId int64) bool
+	}
+
+	// AdminUserProvider этот интерфейс реализует возможность получить всех пользователей бота
+	AdminUserProvider interface {
+		AdminUserList(ctx context.Context) (model.AdminUserList, error)
+	}
+
+	// UserBlocker этот интерфейс реализует возможность закрыть и вернуть пользователю доступ к боту и апи
+	UserBlocker interface {
+		Block(ctx context.Context, userId int64) error
+		Unblock(ctx context.Context, userId int64) error
+	}
+
+	// AccessManager этот интерфейс реализует возможность управлять доступом к боту: режим доступа, список доступа и блокировки
+	AccessManager interface {
+		UserBlocker
+		AccessMode() model.AccessMode
+		SetAccessMode(ctx context.Context, mode model.AccessMode) error
+		AllowedCount() int
+		Allow(ctx context.Context, userId int64) error
+		Disallow(ctx context.Context, userId int64) error
+	}
+
+	// AccessCodeManager этот интерфейс реализует возможность создавать, просматривать и отзывать коды приглашений в бота
+	AccessCodeManager interface {
+		CreateAccessCode(ctx context.Context, createdBy int64, maxUses int, ttl time.Duration) (model.AccessCode, error)
+		AccessCodes(ctx context.Context) ([]model.AccessCode, error)
+		RevokeAccessCode(ctx context.Context, code string) error
+	}
+
+	// Broadcaster этот интерфейс реализует возможность отправить объявление всем пользователям
+	Broadcaster interface {
+		Broadcast(ctx context.Context, text string) (int, error)
+	}
+
+	// RuntimeStatsProvider этот интерфейс реализует возможность получить состояние приложения
+	RuntimeStatsProvider interface {
+		Stats(ctx context.Context) (model.RuntimeStats, error)
+	}
+
+	adminContextKey struct{}
+
+	// Admin структура для обработки команды администратора: пользователи, доступ к боту, объявления и состояние приложения
+	Admin struct {
+		users       AdminUserProvider
+		access      AccessManager
+		codes       AccessCodeManager
+		broadcaster Broadcaster
+		runtime     RuntimeStatsProvider
+		bot         BotNameProvider
+	}
+)
+
+func NewAdminCommand(users AdminUserProvider, access AccessManager, codes AccessCodeManager, broadcaster Broadcaster, runtime RuntimeStatsProvider, bot BotNameProvider) *Admin {
+	return &Admin{
+		users:       users,
+		access:      access,
+		codes:       codes,
+		broadcaster: broadcaster,
+		runtime:     runtime,
+		bot:         bot,
+	}
+}
+
+func (a *Admin) CommandName() string {
+	return AdminCommand
+}
+
+func (a *Admin) Description(loc i18n.Locale) string {
+	return loc.T("admin.description")
+}
+
+func (a *Admin) HelpText(loc i18n.Locale) string {
+	return loc.T("admin.help")
+}
+
+func (a *Admin) AdminOnly() bool {
+	return true
+}
+
+func (a *Admin) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == a.CommandName(), nil
+}
+
+// Run выполняет действие из первого аргумента: /admin users, /admin block 123, /admin broadcast текст
+func (a *Admin) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	action, argument := cutArgument(message.CommandArguments())
+	switch strings.ToLower(action) {
+	case adminActionUsers, adminActionInvites:
+		pages, err := a.pager(ctx, loc, strings.ToLower(action))
+		if err != nil {
+			msg.Text = loc.T("common.error_list")
+			return msg, err
+		}
+
+		return pages.Message(loc, message.Chat.ID), nil
+	case adminActionBlock:
+		return a.userAction(ctx, msg, argument, a.access.Block, "admin.blocked", "admin.user_not_found")
+	case adminActionUnblock:
+		return a.userAction(ctx, msg, argument, a.access.Unblock, "admin.unblocked", "admin.user_not_found")
+	case adminActionAllow:
+		return a.userAction(ctx, msg, argument, a.access.Allow, "access.allowed", "admin.user_not_found")
+	case adminActionDisallow:
+		return a.userAction(ctx, msg, argument, a.access.Disallow, "access.disallowed", "access.not_allowed")
+	case adminActionAccess:
+		return a.accessMode(ctx, msg, argument)
+	case adminActionInvite:
+		return a.createCode(ctx, msg, senderId(message), argument)
+	case adminActionRevoke:
+		return a.revokeCode(ctx, msg, argument)
+	case adminActionBroadcast:
+		count, err := a.broadcaster.Broadcast(ctx, argument)
+		if isDialogInputError(err) {
+			msg.Text = loc.Error(err)
+			return msg, nil
+		}
+
+		if err != nil {
+			msg.Text = loc.T("common.error")
+			return msg, err
+		}
+
+		msg.Text = loc.Plural("admin.broadcast_success", count)
+	case adminActionStats:
+		stats, err := a.runtime.Stats(ctx)
+		if err != nil {
+			msg.Text = loc.T("common.error")
+			return msg, err
+		}
+
+		msg.Text = runtimeStatsText(loc, stats)
+	default:
+		msg.Text = loc.T("admin.help")
+	}
+
+	return msg, nil
+}
+
+// RunCallback перелистывает страницы списка пользователей или кодов приглашений
+func (a *Admin) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	loc := i18n.FromContext(ctx)
+
+	page, args, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	list := adminActionUsers
+	if len(args) > 0 {
+		list = args[0]
+	}
+
+	pages, err := a.pager(ctx, loc, list)
+	if err != nil {
+		return tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	return pages.Edit(loc, query, page), nil
+}
+
+func (a *Admin) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
+
+func (a *Admin) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// pager список постранично: users - все пользователи бота, сначала администраторы, invites - действующие коды приглашений
+func (a *Admin) pager(ctx context.Context, loc i18n.Locale, list string) (*pager, error) {
+	if list == adminActionInvites {
+		return a.codesPager(ctx, loc)
+	}
+
+	users, err := a.users.AdminUserList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(a.CommandName(), adminActionUsers)
+	pages.Header(loc.T("admin.users_title", len(users)))
+	for _, user := range users {
+		var marks string
+		if user.IsAdmin {
+			marks += loc.T("admin.mark_admin")
+		}
+		if user.Blocked {
+			marks += loc.T("admin.mark_blocked")
+		}
+
+		pages.Add(loc.T("admin.user_item", user.Id, html.EscapeString(user.Login), loc.Plural("admin.monitors", user.Monitors), marks))
+	}
+
+	return pages, nil
+}
+
+func (a *Admin) codesPager(ctx context.Context, loc i18n.Locale) (*pager, error) {
+	codes, err := a.codes.AccessCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(a.CommandName(), adminActionInvites)
+	pages.Header(loc.T("access.codes_title", len(codes)))
+	for _, code := range codes {
+		pages.Add(loc.T("access.code_item", code.Code, code.Uses, code.MaxUses, code.ExpiresAt.UTC().Format(accessCodeTimeFormat)))
+	}
+
+	return pages, nil
+}
+
+// userAction действие над пользователем по id из аргумента: /admin block 123
+func (a *Admin) userAction(ctx context.Context, msg tgbotapi.MessageConfig, argument string, action func(ctx context.Context, userId int64) error, successKey, notFoundKey string) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+
+	userId, err := strconv.ParseInt(argument, 10, 64)
+	if err != nil {
+		msg.Text = loc.T("admin.error_user_id")
+		return msg, nil
+	}
+
+	err = action(ctx, userId)
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		msg.Text = loc.T(notFoundKey, userId)
+		return msg, nil
+	case isDialogInputError(err):
+		msg.Text = loc.Error(err)
+		return msg, nil
+	case err != nil:
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
+
+	msg.Text = loc.T(successKey, userId)
+
+	return msg, nil
+}
+
+// accessMode без аргумента выводит текущий режим доступа, с аргументом - меняет его: /admin access invite
+func (a *Admin) accessMode(ctx context.Context, msg tgbotapi.MessageConfig, argument string) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+
+	if argument != "" {
+		err := a.access.SetAccessMode(ctx, model.AccessMode(strings.ToLower(argument)))
+		if isDialogInputError(err) {
+			msg.Text = loc.Error(err)
+			return msg, nil
+		}
+
+		if err != nil {
+			msg.Text = loc.T("common.error_save")
+			return msg, err
+		}
+	}
+
+	mode := a.access.AccessMode()
+	msg.Text = loc.T("access.mode", loc.T("access.mode_"+string(mode)), a.access.AllowedCount())
+
+	return msg, nil
+}
+
+// createCode создает код приглашения: /admin invite [кол-во использований] [срок действия]
+func (a *Admin) createCode(ctx context.Context, msg tgbotapi.MessageConfig, userId int64, argument string) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+
+	maxUses, ttl, err := parseAccessCodeArgs(argument)
+	if err != nil {
+		msg.Text = loc.T("access.error_invite_args", accessCodeMaxUses, loc.Duration(accessCodeMaxTtl))
+		return msg, nil
+	}
+
+	code, err := a.codes.CreateAccessCode(ctx, userId, maxUses, ttl)
+	if err != nil {
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
+
+	msg.Text = loc.T("access.code_created", code.Code, code.MaxUses, code.ExpiresAt.UTC().Format(accessCodeTimeFormat), a.bot.UserName(), code.Code)
+
+	return msg, nil
+}
+
+func (a *Admin) revokeCode(ctx context.Context, msg tgbotapi.MessageConfig, argument string) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+
+	code := strings.ToLower(argument)
+	if code == "" {
+		msg.Text = loc.T("access.error_code")
+		return msg, nil
+	}
+
+	err := a.codes.RevokeAccessCode(ctx, code)
+	if errors.Is(err, storage.ErrInviteNotFound) {
+		msg.Text = loc.T("access.code_not_found", html.EscapeString(code))
+		return msg, nil
+	}
+
+	if err != nil {
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
+
+	msg.Text = loc.T("access.code_revoked", html.EscapeString(code))
+
+	return msg, nil
+}
+
+// parseAccessCodeArgs кол-во использований и срок действия кода приглашения: "", "5", "5 72h"
+func parseAccessCodeArgs(argument string) (int, time.Duration, error) {
+	maxUses, ttl := 1, accessCodeTtl
+
+	args := strings.Fields(argument)
+	if len(args) > 2 {
+		return 0, 0, fmt.Errorf("лишние аргументы: %s", argument)
+	}
+
+	if len(args) > 0 {
+		uses, err := strconv.Atoi(args[0])
+		if err != nil || uses < 1 || uses > accessCodeMaxUses {
+			return 0, 0, fmt.Errorf("не верное кол-во использований: %s", args[0])
+		}
+
+		maxUses = uses
+	}
+
+	if len(args) > 1 {
+		duration, err := time.ParseDuration(args[1])
+		if err != nil || duration <= 0 || duration > accessCodeMaxTtl {
+			return 0, 0, fmt.Errorf("не верный срок действия: %s", args[1])
+		}
+
+		ttl = duration
+	}
+
+	return maxUses, ttl, nil
+}
+
+func runtimeStatsText(loc i18n.Locale, stats model.RuntimeStats) string {
+	return loc.T(
+		"admin.stats",
+		stats.Scheduler.Monitors,
+		stats.Scheduler.PendingRows,
+		stats.Scheduler.FailedInserts,
+		stats.Queue.Chats,
+		loc.Seconds(stats.Queue.Lag),
+		stats.Goroutines,
+		loc.Seconds(stats.Uptime),
+	) + commandStatsText(loc, stats.Commands)
+}
+
+// commandStatsText самые частые команды с момента запуска
+func commandStatsText(loc i18n.Locale, list []model.CommandStats) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	str := strings.Builder{}
+	str.WriteString(loc.T("admin.stats_commands"))
+	for _, stats := range list[:min(len(list), adminStatsCommands)] {
+		str.WriteString(loc.T("admin.stats_command", stats.Command, stats.Runs, stats.Errors, loc.Number(stats.Average, 3), loc.Number(stats.Max, 3)))
+	}
+
+	return str.String()
+}
+
+// withAdmin сохраняет в контексте является ли отправитель администратором
+func withAdmin(ctx context.Context, isAdmin bool) context.Context {
+	return context.WithValue(ctx, adminContextKey{}, isAdmin)
+}
+
+// isAdminFromContext см. withAdmin
+func isAdminFromContext(ctx context.Context) bool {
+	isAdmin, _ := ctx.Value(adminContextKey{}).(bool)
+
+	return isAdmin
+}
+
+// isAllowed команды администратора недоступны остальным пользователям, для них такой команды как будто нет
+func isAllowed(ctx context.Context, handle any) bool {
+	if admin, ok := handle.(AdminOnly); ok && admin.AdminOnly() {
+		return isAdminFromContext(ctx)
+	}
+
+	return true
+}
+
+// cutArgument первое слово аргументов команды и остальной текст, остальной текст может быть многострочным
+func cutArgument(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	i := strings.IndexFunc(text, unicode.IsSpace)
+	if i < 0 {
+		return text, ""
+	}
+
+	return text[:i], strings.TrimSpace(text[i:])
+}
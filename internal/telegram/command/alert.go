@@ -0,0 +1,271 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// AlertCallback имя обработчика кнопок под уведомлениями о недоступности ссылки
+const AlertCallback = "alert"
+
+const alertSnoozeTime = time.Hour
+
+const (
+	alertActionAcknowledge = "ack"    // принять уведомление в работу
+	alertActionSnooze      = "snooze" // отложить уведомления по ссылке на alertSnoozeTime
+	alertActionCheck       = "check"  // проверить ссылку прямо сейчас
+	alertActionStats       = "stats"  // показать статистику по ссылке
+)
+
+type (
+	// AlertSnoozer этот интерфейс реализует возможность временно отключить уведомления по ссылке
+	AlertSnoozer interface {
+		Snooze(ctx context.Context, workspaceId, pingId int64, duration time.Duration, userId, chatId int64) error
+	}
+
+	// UrlChecker этот интерфейс реализует возможность опросить ссылку вне расписания
+	UrlChecker interface {
+		Check(ctx context.Context, ping model.Ping) model.CheckResult
+	}
+
+	// UrlProvider этот интерфейс реализует возможность получить ссылку пользователя по идентификатору
+	UrlProvider interface {
+		UrlById(userId, id int64) (model.Ping, error)
+	}
+
+	// IncidentEventRecorder этот интерфейс реализует возможность дописать событие в хронологию открытого инцидента по ссылке
+	IncidentEventRecorder interface {
+		AddIncidentEvent(ctx context.Context, pingId int64, kind model.IncidentEventKind, text string, at time.Time) error
+	}
+
+	// Alert структура для обработки нажатий на кнопки под уведомлениями
+	Alert struct {
+		urlRepo       UrlProvider
+		snoozer       AlertSnoozer
+		checker       UrlChecker
+		statisticRepo UrlStatistic
+		incidentRepo  IncidentEventRecorder
+	}
+)
+
+func NewAlertCallback(urlRepo UrlProvider, snoozer AlertSnoozer, checker UrlChecker, statisticRepo UrlStatistic, incidentRepo IncidentEventRecorder) *Alert {
+	return &Alert{
+		urlRepo:       urlRepo,
+		snoozer:       snoozer,
+		checker:       checker,
+		statisticRepo: statisticRepo,
+		incidentRepo:  incidentRepo,
+	}
+}
+
+// AlertKeyboard кнопки которые прикрепляются к уведомлению о недоступности ссылки
+func AlertKeyboard(loc i18n.Locale, pingId int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(alertButton(loc.T("alert.button_ack"), alertActionAcknowledge, pingId)),
+		alertFollowUpRow(loc, pingId),
+	)
+}
+
+// alertFollowUpRow кнопки которые остаются под уведомлением после того как его приняли
+func alertFollowUpRow(loc i18n.Locale, pingId int64) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(
+		alertButton(loc.T("alert.button_snooze", loc.Duration(alertSnoozeTime)), alertActionSnooze, pingId),
+		alertButton(loc.T("alert.button_check"), alertActionCheck, pingId),
+		alertButton(loc.T("alert.button_stats"), alertActionStats, pingId),
+	)
+}
+
+func alertButton(text, action string, pingId int64) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(text, callbackData(AlertCallback, action, strconv.FormatInt(pingId, 10)))
+}
+
+func (a *Alert) CommandName() string {
+	return AlertCallback
+}
+
+func (a *Alert) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", a.CommandName()))
+	defer span.End()
+
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) != 2 {
+		return nil, fmt.Errorf("не верные данные кнопки: %s", query.Data)
+	}
+
+	pingId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	ping, err := a.urlRepo.UrlById(query.From.ID, pingId)
+	if err != nil {
+		span.RecordError(err)
+		msg.Text = loc.T("common.url_not_found")
+		return msg, err
+	}
+
+	switch args[0] {
+	case alertActionAcknowledge:
+		return a.acknowledge(ctx, query, ping)
+	case alertActionSnooze:
+		// откладывание действует на всех участников, поэтому доступно только редакторам пространства ссылки
+		if member, err := editorFromContext(ctx); err != nil || member.WorkspaceId != ping.WorkspaceId {
+			msg.Text = memberErrorText(ctx, errReadOnly)
+			return msg, nil
+		}
+
+		err := a.snoozer.Snooze(ctx, ping.WorkspaceId, ping.Id, alertSnoozeTime, query.From.ID, query.Message.Chat.ID)
+		if errors.Is(err, storage.ErrAlreadyMuted) {
+			msg.Text = loc.T("alert.snooze_muted", html.EscapeString(ping.Url))
+			break
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			msg.Text = loc.T("common.error")
+			return msg, err
+		}
+
+		msg.Text = loc.T("alert.snoozed", html.EscapeString(ping.Url), time.Now().Add(alertSnoozeTime).Format("15:04"))
+	case alertActionCheck:
+		msg.Text = checkResultText(loc, a.checker.Check(ctx, ping))
+	case alertActionStats:
+		stats, err := a.statisticRepo.StatisticByUrl(ping.WorkspaceId, ping.Url)
+		if err != nil {
+			span.RecordError(err)
+			msg.Text = loc.T("statistic.error")
+			return msg, err
+		}
+
+		msg.Text = statisticUrlText(loc, stats)
+	default:
+		return nil, fmt.Errorf("неизвестное действие: %s", args[0])
+	}
+
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
+}
+
+// acknowledge редактирует уведомление, дописывая кто и когда его принял
+func (a *Alert) acknowledge(ctx context.Context, query *tgbotapi.CallbackQuery, ping model.Ping) (tgbotapi.Chattable, error) {
+	who := query.From.UserName
+	if who != "" {
+		who = "@" + who
+	} else {
+		who = query.From.FirstName
+	}
+
+	loc := i18n.FromContext(ctx)
+	now := time.Now()
+	text := loc.T("alert.acknowledged", messageHTML(query.Message.Text, query.Message.Entities), html.EscapeString(who), now.Format("15:04"))
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID, text, tgbotapi.NewInlineKeyboardMarkup(alertFollowUpRow(loc, ping.Id)))
+	edit.ParseMode = tgbotapi.ModeHTML
+
+	// уведомление редактируем даже если не удалось записать событие в инцидент
+	err := a.incidentRepo.AddIncidentEvent(ctx, ping.Id, model.IncidentEventAcknowledge, who, now)
+
+	return edit, err
+}
+
+// entityTags html теги для разметки сообщения, остальные сущности (ссылки, упоминания) телеграм выделяет сам
+var entityTags = map[string]string{
+	"bold":          "b",
+	"italic":        "i",
+	"underline":     "u",
+	"strikethrough": "s",
+	"spoiler":       "tg-spoiler",
+	"code":          "code",
+	"pre":           "pre",
+	"blockquote":    "blockquote",
+	"text_link":     "a",
+	"text_mention":  "a",
+}
+
+// messageHTML восстанавливает html разметку сообщения по его тексту и сущностям, телеграм присылает текст без разметки.
+// Смещения сущностей считаются в единицах UTF-16
+func messageHTML(text string, entities []tgbotapi.MessageEntity) string {
+	units := utf16.Encode([]rune(text))
+
+	list := make([]tgbotapi.MessageEntity, 0, len(entities))
+	for _, entity := range entities {
+		if entityTags[entity.Type] != "" && entity.Length > 0 && (entity.Type != "text_mention" || entity.User != nil) {
+			list = append(list, entity)
+		}
+	}
+
+	// внешняя сущность открывается раньше вложенной, начинающейся с той же позиции
+	slices.SortStableFunc(list, func(a, b tgbotapi.MessageEntity) int {
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+
+		return b.Length - a.Length
+	})
+
+	str := strings.Builder{}
+	var open []tgbotapi.MessageEntity
+	next := 0
+
+	for i := 0; ; {
+		for len(open) > 0 && open[len(open)-1].Offset+open[len(open)-1].Length <= i {
+			str.WriteString("</" + entityTags[open[len(open)-1].Type] + ">")
+			open = open[:len(open)-1]
+		}
+
+		if i >= len(units) {
+			break
+		}
+
+		for ; next < len(list) && list[next].Offset <= i; next++ {
+			str.WriteString(entityOpenTag(list[next]))
+			open = append(open, list[next])
+		}
+
+		size := 1
+		if utf16.IsSurrogate(rune(units[i])) && i+1 < len(units) {
+			size = 2
+		}
+
+		str.WriteString(html.EscapeString(string(utf16.Decode(units[i : i+size]))))
+		i += size
+	}
+
+	return str.String()
+}
+
+func entityOpenTag(entity tgbotapi.MessageEntity) string {
+	switch {
+	case entity.Type == "text_link":
+		return fmt.Sprintf(`<a href="%s">`, html.EscapeString(entity.URL))
+	case entity.Type == "text_mention":
+		return fmt.Sprintf(`<a href="tg://user?id=%d">`, entity.User.ID)
+	default:
+		return "<" + entityTags[entity.Type] + ">"
+	}
+}
+
+// checkResultText форматирует результат внепланового опроса ссылки
+func checkResultText(loc i18n.Locale, result model.CheckResult) string {
+	if result.Error != "" {
+		return loc.T("alert.check_down", html.EscapeString(result.Url), html.EscapeString(result.Error))
+	}
+
+	return loc.T("alert.check_up", html.EscapeString(result.Url), result.StatusCode, loc.Number(result.Latency, 4))
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"testing"
+)
+
+func TestMessageHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		entities []tgbotapi.MessageEntity
+		want     string
+	}{
+		{
+			name: "без сущностей текст экранируется",
+			text: "a < b & c",
+			want: "a &lt; b &amp; c",
+		},
+		{
+			name:     "одна сущность",
+			text:     "hello world",
+			entities: []tgbotapi.MessageEntity{{Type: "bold", Offset: 0, Length: 5}},
+			want:     "<b>hello</b> world",
+		},
+		{
+			name: "вложенная сущность",
+			text: "hello world",
+			entities: []tgbotapi.MessageEntity{
+				{Type: "bold", Offset: 0, Length: 11},
+				{Type: "italic", Offset: 6, Length: 5},
+			},
+			want: "<b>hello <i>world</i></b>",
+		},
+		{
+			name: "сущности с одного места, вложенная раньше в списке",
+			text: "hello world",
+			entities: []tgbotapi.MessageEntity{
+				{Type: "italic", Offset: 0, Length: 5},
+				{Type: "bold", Offset: 0, Length: 11},
+			},
+			want: "<b><i>hello</i> world</b>",
+		},
+		{
+			name: "сущности с одинаковыми границами",
+			text: "hello",
+			entities: []tgbotapi.MessageEntity{
+				{Type: "bold", Offset: 0, Length: 5},
+				{Type: "underline", Offset: 0, Length: 5},
+			},
+			want: "<b><u>hello</u></b>",
+		},
+		{
+			name:     "эмодзи перед сущностью",
+			text:     "😀 ok",
+			entities: []tgbotapi.MessageEntity{{Type: "bold", Offset: 3, Length: 2}},
+			want:     "😀 <b>ok</b>",
+		},
+		{
+			name:     "сущность из эмодзи",
+			text:     "a😀b",
+			entities: []tgbotapi.MessageEntity{{Type: "italic", Offset: 1, Length: 2}},
+			want:     "a<i>😀</i>b",
+		},
+		{
+			name:     "разметка внутри кода экранируется",
+			text:     "x <b> y",
+			entities: []tgbotapi.MessageEntity{{Type: "code", Offset: 2, Length: 3}},
+			want:     "x <code>&lt;b&gt;</code> y",
+		},
+		{
+			name:     "ссылка с & и кавычками",
+			text:     "site",
+			entities: []tgbotapi.MessageEntity{{Type: "text_link", Offset: 0, Length: 4, URL: `https://x.com/?a=1&b="q"`}},
+			want:     `<a href="https://x.com/?a=1&amp;b=&#34;q&#34;">site</a>`,
+		},
+		{
+			name:     "упоминание пользователя",
+			text:     "hi Ivan",
+			entities: []tgbotapi.MessageEntity{{Type: "text_mention", Offset: 3, Length: 4, User: &tgbotapi.User{ID: 42}}},
+			want:     `hi <a href="tg://user?id=42">Ivan</a>`,
+		},
+		{
+			name: "сущности без html тега и упоминание без пользователя пропускаются",
+			text: "see https://x.com @bot",
+			entities: []tgbotapi.MessageEntity{
+				{Type: "url", Offset: 4, Length: 13},
+				{Type: "mention", Offset: 18, Length: 4},
+				{Type: "text_mention", Offset: 18, Length: 4},
+			},
+			want: "see https://x.com @bot",
+		},
+		{
+			name:     "пустая сущность пропускается",
+			text:     "abc",
+			entities: []tgbotapi.MessageEntity{{Type: "bold", Offset: 1, Length: 0}},
+			want:     "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageHTML(tt.text, tt.entities); got != tt.want {
+				t.Fatalf("html %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
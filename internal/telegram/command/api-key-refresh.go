@@ -2,8 +2,8 @@ package command
 
 import (
 	"context"
-	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 )
 
 type (
@@ -30,8 +30,12 @@ func (a *ApiKeyRefresh) CommandName() string {
 	return ApiKeyRefreshCommand
 }
 
-func (a *ApiKeyRefresh) HelpText() string {
-	return "help text"
+func (a *ApiKeyRefresh) Description(loc i18n.Locale) string {
+	return loc.T("api_key_refresh.description")
+}
+
+func (a *ApiKeyRefresh) HelpText(loc i18n.Locale) string {
+	return loc.T("api_key_refresh.help")
 }
 
 func (a *ApiKeyRefresh) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -46,14 +50,15 @@ func (a *ApiKeyRefresh) IsSupport(ctx context.Context, message *tgbotapi.Message
 func (a *ApiKeyRefresh) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
 	userId := message.Chat.ID
 	msg := tgbotapi.NewMessage(userId, "")
+	loc := i18n.FromContext(ctx)
 
 	key, err := a.keyGenerator.ApiKey(ctx, userId)
 	if err != nil {
-		msg.Text = "Произошла ошибка при генерации токена, повторите позже"
+		msg.Text = loc.T("api_key.error")
 		return msg, err
 	}
 
-	msg.Text = fmt.Sprintf("api-key: <code>%s</code> \n\n ссылка: <code>%s?api-key=%s</code>", key, a.baseUrl, key)
+	msg.Text = loc.T("api_key.success", key, a.baseUrl, key, WorkspaceCommand)
 	msg.ParseMode = tgbotapi.ModeHTML
 
 	return msg, nil
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"strings"
+	"unicode"
+)
+
+type (
+	// commandArguments аргументы команды одной строкой: позиционные по порядку и именованные вида "--flag value"
+	commandArguments struct {
+		Positional []string            `json:"positional,omitempty"`
+		Flags      map[string][]string `json:"flags,omitempty"` // значения по имени аргумента, аргумент можно передать несколько раз
+	}
+)
+
+// parseCommandArguments разбирает аргументы команды. Значение с пробелами берется в кавычки,
+// имя аргумента можно передать как "--flag value" или "--flag=value". Телеграм на телефонах
+// заменяет "--" на тире, а прямые кавычки на типографские, такие варианты тоже принимаются
+func parseCommandArguments(text string) (commandArguments, error) {
+	args := commandArguments{Flags: map[string][]string{}}
+
+	tokens, err := splitArguments(text)
+	if err != nil {
+		return args, err
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		name, ok := flagName(tokens[i])
+		if !ok {
+			args.Positional = append(args.Positional, tokens[i])
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(name, "=")
+		if !hasValue {
+			if i+1 >= len(tokens) {
+				return args, i18n.NewError("arguments.error_value", name)
+			}
+
+			if _, isFlag := flagName(tokens[i+1]); isFlag {
+				return args, i18n.NewError("arguments.error_value", name)
+			}
+
+			i++
+			value = tokens[i]
+		}
+
+		name = normalizeFlag(name)
+		args.Flags[name] = append(args.Flags[name], value)
+	}
+
+	return args, nil
+}
+
+// take забирает ответ на шаг: именованные аргументы шага через пробел, иначе следующий позиционный аргумент
+func (a *commandArguments) take(step DialogStep) (string, bool) {
+	var values []string
+	for _, name := range step.flags() {
+		values = append(values, a.Flags[name]...)
+		delete(a.Flags, name)
+	}
+
+	if len(values) > 0 {
+		return strings.Join(values, " "), true
+	}
+
+	if len(a.Positional) == 0 {
+		return "", false
+	}
+
+	value := a.Positional[0]
+	a.Positional = a.Positional[1:]
+
+	return value, true
+}
+
+func (a *commandArguments) empty() bool {
+	return len(a.Positional) == 0 && len(a.Flags) == 0
+}
+
+// flagName имя аргумента без "--", false если токен не является именованным аргументом
+func flagName(token string) (string, bool) {
+	for _, prefix := range []string{"--", "—"} {
+		if name, ok := strings.CutPrefix(token, prefix); ok && name != "" {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// normalizeFlag "--Connection-Time" и "--connection_time" один и тот же аргумент
+func normalizeFlag(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+}
+
+// splitArguments делит строку по пробелам, текст в кавычках остается одним аргументом
+func splitArguments(text string) ([]string, error) {
+	var (
+		tokens []string
+		token  strings.Builder
+		quoted bool
+		filled bool // токен начат, в том числе пустой в кавычках
+	)
+
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”' || r == '«' || r == '»':
+			quoted = !quoted
+			filled = true
+		case unicode.IsSpace(r) && !quoted:
+			if filled {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				filled = false
+			}
+		default:
+			token.WriteRune(r)
+			filled = true
+		}
+	}
+
+	if quoted {
+		return nil, i18n.NewError("arguments.error_quote")
+	}
+
+	if filled {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens, nil
+}
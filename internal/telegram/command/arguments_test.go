@@ -0,0 +1,164 @@
+package command
+
+import (
+	"errors"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"reflect"
+	"testing"
+)
+
+func TestSplitArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr string // ключ i18n ошибки
+	}{
+		{name: "пустая строка", text: "", want: nil},
+		{name: "только пробелы", text: "  \t\n ", want: nil},
+		{name: "слова через пробелы", text: " a  b\tc\nd ", want: []string{"a", "b", "c", "d"}},
+		{name: "прямые кавычки", text: `"hello world" x`, want: []string{"hello world", "x"}},
+		{name: "типографские кавычки", text: `“hello world” x`, want: []string{"hello world", "x"}},
+		{name: "елочки", text: `«hello world» x`, want: []string{"hello world", "x"}},
+		{name: "разные кавычки в паре", text: `«hello world" x`, want: []string{"hello world", "x"}},
+		{name: "пустое значение в кавычках", text: `a "" b`, want: []string{"a", "", "b"}},
+		{name: "кавычки внутри слова", text: `a"b c"d`, want: []string{"ab cd"}},
+		{name: "перенос строки в кавычках", text: "\"a\nb\"", want: []string{"a\nb"}},
+		{name: "незакрытая кавычка", text: `"hello world`, wantErr: "arguments.error_quote"},
+		{name: "незакрытая типографская кавычка", text: `a “b`, wantErr: "arguments.error_quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArguments(tt.text)
+			if key := errorKey(err); key != tt.wantErr {
+				t.Fatalf("ошибка %q (%v), ожидалась %q", key, err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("аргументы %q, ожидались %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    commandArguments
+		wantErr string // ключ i18n ошибки
+	}{
+		{
+			name: "без аргументов",
+			text: "",
+			want: commandArguments{Flags: map[string][]string{}},
+		},
+		{
+			name: "позиционные аргументы",
+			text: "https://example.com 10s 1m",
+			want: commandArguments{Positional: []string{"https://example.com", "10s", "1m"}, Flags: map[string][]string{}},
+		},
+		{
+			name: "именованный аргумент через пробел",
+			text: "--url https://example.com",
+			want: commandArguments{Flags: map[string][]string{"url": {"https://example.com"}}},
+		},
+		{
+			name: "именованный аргумент через равно",
+			text: "--url=https://example.com/?a=1",
+			want: commandArguments{Flags: map[string][]string{"url": {"https://example.com/?a=1"}}},
+		},
+		{
+			name: "пустое значение через равно",
+			text: "--tags=",
+			want: commandArguments{Flags: map[string][]string{"tags": {""}}},
+		},
+		{
+			name: "значение с пробелами в кавычках",
+			text: `--name "my site" --tag=“env prod”`,
+			want: commandArguments{Flags: map[string][]string{"name": {"my site"}, "tag": {"env prod"}}},
+		},
+		{
+			name: "тире вместо двух дефисов",
+			text: "—url https://example.com —ping-time=1m",
+			want: commandArguments{Flags: map[string][]string{"url": {"https://example.com"}, "ping_time": {"1m"}}},
+		},
+		{
+			name: "имя в другом регистре и с дефисами",
+			text: "--Connection-Time 10s --PING_time 1m",
+			want: commandArguments{Flags: map[string][]string{"connection_time": {"10s"}, "ping_time": {"1m"}}},
+		},
+		{
+			name: "повторяющийся аргумент",
+			text: "--tag env:prod --tag=team:ops —tag db",
+			want: commandArguments{Flags: map[string][]string{"tag": {"env:prod", "team:ops", "db"}}},
+		},
+		{
+			name: "позиционные и именованные вперемешку",
+			text: "https://example.com --critical true 10s",
+			want: commandArguments{Positional: []string{"https://example.com", "10s"}, Flags: map[string][]string{"critical": {"true"}}},
+		},
+		{
+			name: "дефисы без имени считаются значением",
+			text: "-- — -5",
+			want: commandArguments{Positional: []string{"--", "—", "-5"}, Flags: map[string][]string{}},
+		},
+		{
+			name: "значение с одним дефисом",
+			text: "--offset -5",
+			want: commandArguments{Flags: map[string][]string{"offset": {"-5"}}},
+		},
+		{
+			name:    "нет значения в конце",
+			text:    "https://example.com --ping-time",
+			wantErr: "arguments.error_value",
+		},
+		{
+			name:    "вместо значения следующий аргумент",
+			text:    "--url --ping-time 1m",
+			wantErr: "arguments.error_value",
+		},
+		{
+			name:    "вместо значения аргумент с тире",
+			text:    "--url —ping-time 1m",
+			wantErr: "arguments.error_value",
+		},
+		{
+			name:    "незакрытая кавычка",
+			text:    `--name "my site`,
+			wantErr: "arguments.error_quote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCommandArguments(tt.text)
+			if key := errorKey(err); key != tt.wantErr {
+				t.Fatalf("ошибка %q (%v), ожидалась %q", key, err, tt.wantErr)
+			}
+
+			if tt.wantErr != "" {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("аргументы %+v, ожидались %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// errorKey ключ i18n ошибки, пустая строка если ошибки нет
+func errorKey(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var i18nErr *i18n.Error
+	if !errors.As(err, &i18nErr) {
+		return err.Error()
+	}
+
+	return i18nErr.Key
+}
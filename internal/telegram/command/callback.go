@@ -0,0 +1,21 @@
+package command
+
+import (
+	"strings"
+)
+
+// callbackSeparator разделитель частей в данных inline кнопки: "команда:аргумент:аргумент"
+const callbackSeparator = ":"
+
+// callbackData собирает данные для inline кнопки, первой частью всегда идет имя команды,
+// по нему диспетчер находит обработчик нажатия
+func callbackData(command string, args ...string) string {
+	return strings.Join(append([]string{command}, args...), callbackSeparator)
+}
+
+// parseCallbackData разбирает данные inline кнопки на имя команды и аргументы
+func parseCallbackData(data string) (string, []string) {
+	parts := strings.Split(data, callbackSeparator)
+
+	return parts[0], parts[1:]
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"strings"
+)
+
+type (
+	// Cancel структура для обработки команды отмены начатых диалогов
+	Cancel struct {
+		dialog   DialogChain
+		commands []HandlerCommand
+	}
+)
+
+// NewCancelCommand отменяет диалоги переданных команд
+func NewCancelCommand(dialog DialogChain, commands []HandlerCommand) *Cancel {
+	return &Cancel{
+		dialog:   dialog,
+		commands: commands,
+	}
+}
+
+func (c *Cancel) CommandName() string {
+	return CancelCommand
+}
+
+func (c *Cancel) Description(loc i18n.Locale) string {
+	return loc.T("cancel.description")
+}
+
+func (c *Cancel) HelpText(loc i18n.Locale) string {
+	return loc.T("cancel.help")
+}
+
+// KeepDialogs диалоги должны дожить до Run, чтобы сообщить какие из них отменены
+func (c *Cancel) KeepDialogs() bool {
+	return true
+}
+
+func (c *Cancel) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == c.CommandName(), nil
+}
+
+// Run отменяет все начатые в чате диалоги и перечисляет отмененные команды
+func (c *Cancel) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", c.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	var (
+		cancelled []string
+		errs      []error
+	)
+
+	for _, handle := range c.commands {
+		is, err := c.dialog.DialogExist(ctx, dialogKey(message.Chat.ID, handle.CommandName()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if is != true {
+			continue
+		}
+
+		if err := handle.ClearData(ctx, message); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		cancelled = append(cancelled, loc.T("cancel.item", handle.CommandName(), handle.Description(loc)))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		span.RecordError(err)
+		msg.Text = loc.T("common.error")
+		return msg, err
+	}
+
+	if len(cancelled) == 0 {
+		msg.Text = loc.T("cancel.nothing")
+		return msg, nil
+	}
+
+	msg.Text = loc.T("cancel.done") + strings.Join(cancelled, "\n")
+
+	return msg, nil
+}
+
+func (c *Cancel) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (c *Cancel) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
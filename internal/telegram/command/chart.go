@@ -0,0 +1,258 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/chart"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// UrlChart этот интерфейс реализует возможность получить время ответа и неудачные опросы ссылки по интервалам
+	UrlChart interface {
+		ChartByUrl(workspaceId int64, url string, from time.Time, step time.Duration) (model.ChartPointList, error)
+	}
+
+	// UserLocation этот интерфейс реализует возможность получить часовой пояс пользователя
+	UserLocation interface {
+		NotificationSettings(ctx context.Context, userId int64) (model.NotificationSettings, error)
+	}
+
+	// PhotoSender этот интерфейс реализует возможность отправить картинку в чат
+	PhotoSender interface {
+		Send(c tgbotapi.Chattable) error
+	}
+
+	// Chart структура для обработки команды вывода графика времени ответа ссылки
+	Chart struct {
+		chartRepo UrlChart
+		urlRepo   UserUrlList
+		settings  UserLocation
+		sender    PhotoSender
+	}
+)
+
+func NewChartCommand(chartRepo UrlChart, urlRepo UserUrlList, settings UserLocation, sender PhotoSender) *Chart {
+	return &Chart{
+		chartRepo: chartRepo,
+		urlRepo:   urlRepo,
+		settings:  settings,
+		sender:    sender,
+	}
+}
+
+func (c *Chart) CommandName() string {
+	return ChartCommand
+}
+
+func (c *Chart) Description(loc i18n.Locale) string {
+	return loc.T("chart.description")
+}
+
+func (c *Chart) HelpText(loc i18n.Locale) string {
+	return loc.T("chart.help")
+}
+
+func (c *Chart) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == c.CommandName(), nil
+}
+
+// Run команда /chart <ссылка> [24h|7d|30d] сразу отправляет график, без ссылки предлагает выбрать ее кнопками
+func (c *Chart) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", c.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) > 2 {
+		msg.Text = loc.T("chart.usage")
+		return msg, nil
+	}
+
+	list, err := c.urlRepo.UrlListByWorkspace(member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	if len(list) == 0 {
+		msg.Text = loc.T("common.empty_list")
+		return msg, nil
+	}
+
+	if len(args) == 0 {
+		msg.Text = loc.T("chart.ask_url")
+		msg.ReplyMarkup = urlPickerKeyboard(loc, c.CommandName(), list, 0)
+		return msg, nil
+	}
+
+	period, ok := chart.ParsePeriod(strings.Join(args[1:], ""))
+	if !ok {
+		msg.Text = loc.T("chart.usage")
+		return msg, nil
+	}
+
+	ping, ok := pingByUrl(list, args[0])
+	if !ok {
+		msg.Text = loc.T("common.url_not_exists")
+		return msg, nil
+	}
+
+	photo, text, err := c.render(ctx, message.Chat.ID, senderId(message), member.WorkspaceId, ping, period)
+	if photo == nil {
+		msg.Text = text
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return msg, err
+	}
+
+	if err := c.sender.Send(*photo); err != nil {
+		msg.Text = loc.T("chart.error")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	// график уже отправлен, текстовый ответ не нужен
+	return msg, nil
+}
+
+// RunCallback отправляет график ссылки выбранной кнопкой, кнопки под графиком перестраивают его за другой промежуток
+func (c *Chart) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", c.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	action, id, err := parseUrlPick(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.urlRepo.UrlListByWorkspace(member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	if action == urlPickerActionPage {
+		return urlPickerPage(loc, query, c.CommandName(), list, id), nil
+	}
+
+	// у кнопок промежутков под графиком вместо действия указано название промежутка
+	period, ok := chart.Periods[0], true
+	if action != urlPickerActionPick {
+		period, ok = chart.ParsePeriod(action)
+	}
+
+	ping, exists := pingById(list, id)
+	if !exists || !ok {
+		msg.Text = loc.T("common.url_not_found")
+		return msg, nil
+	}
+
+	photo, text, err := c.render(ctx, query.Message.Chat.ID, query.From.ID, member.WorkspaceId, ping, period)
+	if photo == nil {
+		msg.Text = text
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return msg, err
+	}
+
+	return *photo, nil
+}
+
+func (c *Chart) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (c *Chart) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// render строит график ссылки за промежуток, если опросов не было или произошла ошибка - возвращает текст ответа вместо картинки
+func (c *Chart) render(ctx context.Context, chatId, userId, workspaceId int64, ping model.Ping, period chart.Period) (*tgbotapi.PhotoConfig, string, error) {
+	loc := i18n.FromContext(ctx)
+	now := time.Now()
+
+	points, err := c.chartRepo.ChartByUrl(workspaceId, ping.Url, period.From(now), period.Step)
+	if err != nil {
+		return nil, loc.T("chart.error"), err
+	}
+
+	if len(points) == 0 {
+		return nil, loc.T("chart.no_data", ping.Url, period.Name), nil
+	}
+
+	// подписи времени в часовом поясе пользователя, без настроек график строится в UTC
+	settings, err := c.settings.NotificationSettings(ctx, userId)
+	if err != nil {
+		return nil, loc.T("chart.error"), err
+	}
+
+	data, err := chart.Render(points, chart.Options{
+		Title:    ping.Url,
+		Period:   period,
+		Location: settings.Location(),
+		Now:      now,
+	})
+	if err != nil {
+		return nil, loc.T("chart.error"), err
+	}
+
+	var total, failures int64
+	for _, p := range points {
+		total += p.Total
+		failures += p.Failures
+	}
+
+	photo := tgbotapi.NewPhoto(chatId, tgbotapi.FileBytes{Name: "chart.png", Bytes: data})
+	photo.Caption = loc.T("chart.caption", ping.Url, period.Name, total, failures, loc.Number(float64(total-failures)/float64(total)*100, 2))
+	photo.ReplyMarkup = c.periodKeyboard(ping.Id, period)
+
+	return &photo, "", nil
+}
+
+// periodKeyboard кнопки перестроения графика за другой промежуток, текущий промежуток отмечен
+func (c *Chart) periodKeyboard(pingId int64, current chart.Period) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(chart.Periods))
+	for _, p := range chart.Periods {
+		label := p.Name
+		if p.Name == current.Name {
+			label = "• " + label
+		}
+
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, callbackData(c.CommandName(), p.Name, strconv.FormatInt(pingId, 10))))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
@@ -0,0 +1,195 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"strconv"
+	"strings"
+)
+
+type (
+	// CheckNow структура для обработки команды внепланового опроса ссылки
+	CheckNow struct {
+		urlRepo UserUrlList
+		checker UrlChecker
+	}
+)
+
+func NewCheckNowCommand(urlRepo UserUrlList, checker UrlChecker) *CheckNow {
+	return &CheckNow{
+		urlRepo: urlRepo,
+		checker: checker,
+	}
+}
+
+func (c *CheckNow) CommandName() string {
+	return CheckNowCommand
+}
+
+func (c *CheckNow) Description(loc i18n.Locale) string {
+	return loc.T("check_now.description")
+}
+
+func (c *CheckNow) HelpText(loc i18n.Locale) string {
+	return loc.T("check_now.help")
+}
+
+func (c *CheckNow) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == c.CommandName(), nil
+}
+
+// Run команда /check_now <ссылка> сразу опрашивает ссылку, без ссылки предлагает выбрать ее кнопками
+func (c *CheckNow) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", c.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	list, err := c.urlRepo.UrlListByWorkspace(member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	if len(list) == 0 {
+		msg.Text = loc.T("common.empty_list")
+		return msg, nil
+	}
+
+	url := strings.TrimSpace(message.CommandArguments())
+	if url == "" {
+		msg.Text = loc.T("check_now.ask_url")
+		msg.ReplyMarkup = urlPickerKeyboard(loc, c.CommandName(), list, 0)
+		return msg, nil
+	}
+
+	ping, ok := pingByUrl(list, url)
+	if !ok {
+		msg.Text = loc.T("common.url_not_exists")
+		return msg, nil
+	}
+
+	return c.check(ctx, msg, ping), nil
+}
+
+// RunCallback опрашивает ссылку выбранную кнопкой, в том числе кнопкой повторной проверки под результатом
+func (c *CheckNow) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", c.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	action, id, err := parseUrlPick(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.urlRepo.UrlListByWorkspace(member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	if action == urlPickerActionPage {
+		return urlPickerPage(loc, query, c.CommandName(), list, id), nil
+	}
+
+	ping, ok := pingById(list, id)
+	if !ok || action != urlPickerActionPick {
+		msg.Text = loc.T("common.url_not_found")
+		return msg, nil
+	}
+
+	return c.check(ctx, msg, ping), nil
+}
+
+func (c *CheckNow) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (c *CheckNow) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// check опрашивает ссылку и заполняет ответ результатом с кнопкой повторной проверки
+func (c *CheckNow) check(ctx context.Context, msg tgbotapi.MessageConfig, ping model.Ping) tgbotapi.MessageConfig {
+	loc := i18n.FromContext(ctx)
+
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.Text = checkNowText(loc, c.checker.Check(ctx, ping))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("check_now.again"), callbackData(c.CommandName(), urlPickerActionPick, strconv.FormatInt(ping.Id, 10))),
+	))
+
+	return msg
+}
+
+// checkNowText полный результат опроса: статус, время ответа по этапам, основные заголовки и ошибка
+func checkNowText(loc i18n.Locale, result model.CheckResult) string {
+	str := strings.Builder{}
+	str.WriteString(loc.T("check_now.title", html.EscapeString(result.Url)))
+	str.WriteString(fmt.Sprintf("%s %s", statusEmoji[result.Status], loc.T("status.name_"+string(result.Status))))
+	if result.StatusCode > 0 {
+		str.WriteString(loc.T("status.code", result.StatusCode))
+	}
+
+	str.WriteString(loc.T("check_now.latency", loc.Number(result.Latency, 3)))
+
+	var timings []string
+	for _, t := range []struct {
+		key   string
+		value float64
+	}{
+		{"check_now.timing_dns", result.Timings.Dns},
+		{"check_now.timing_connect", result.Timings.Connect},
+		{"check_now.timing_tls", result.Timings.Tls},
+		{"check_now.timing_first_byte", result.Timings.FirstByte},
+	} {
+		if t.value > 0 {
+			timings = append(timings, loc.T(t.key, loc.Number(t.value, 3)))
+		}
+	}
+
+	if len(timings) > 0 {
+		str.WriteString("\n" + strings.Join(timings, " · "))
+	}
+
+	if len(result.Headers) > 0 {
+		str.WriteString(loc.T("check_now.headers"))
+		for _, name := range model.ProbeHeaders {
+			if value, ok := result.Headers[name]; ok {
+				str.WriteString(fmt.Sprintf("\n<code>%s: %s</code>", name, html.EscapeString(value)))
+			}
+		}
+	}
+
+	if result.Error != "" {
+		str.WriteString(loc.T("check_now.error", html.EscapeString(result.Error)))
+	}
+
+	return str.String()
+}
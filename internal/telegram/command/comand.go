@@ -2,27 +2,50 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/kernel"
 	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
 	"github.com/ivankoTut/ping-url/internal/telegram"
 	"github.com/ivankoTut/ping-url/internal/tracing"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	RegistrationCommand  = "start"
-	AddUrlCommand        = "add_url"
-	RemoveUrlCommand     = "remove_url"
-	ListUrlCommand       = "list_url"
-	MuteAllCommand       = "mute_all"
-	UnMuteAllCommand     = "unmute_all"
-	StatisticAllCommand  = "statistic_all"
-	StatisticCommand     = "statistic"
-	StatisticUrlCommand  = "statistic_url"
-	ApiKeyRefreshCommand = "api_key_refresh"
+	RegistrationCommand    = "start"
+	AddUrlCommand          = "add_url"
+	RemoveUrlCommand       = "remove_url"
+	ListUrlCommand         = "list_url"
+	MuteAllCommand         = "mute_all"
+	UnMuteAllCommand       = "unmute_all"
+	StatisticAllCommand    = "statistic_all"
+	StatisticCommand       = "statistic"
+	StatisticUrlCommand    = "statistic_url"
+	ApiKeyRefreshCommand   = "api_key_refresh"
+	MuteUrlCommand         = "mute_url"
+	UnMuteUrlCommand       = "unmute_url"
+	MuteListCommand        = "mute_list"
+	SettingsCommand        = "settings"
+	CriticalUrlCommand     = "critical_url"
+	TemplateCommand        = "template"
+	TemplatePreviewCommand = "template_preview"
+	IncidentsCommand       = "incidents"
+	WorkspaceCommand       = "workspace"
+	ConnectChatCommand     = "connect_chat"
+	EditUrlCommand         = "edit_url"
+	HelpCommand            = "help"
+	ImportCommand          = "import"
+	ExportCommand          = "export"
+	ChartCommand           = "chart"
+	StatusCommand          = "status"
+	CheckNowCommand        = "check_now"
+	CancelCommand          = "cancel"
+	AdminCommand           = "admin"
 )
 
 var tracer trace.Tracer
@@ -41,38 +64,98 @@ type (
 	// HandlerCommand интерфейс которому должны удовлетворять все команды
 	HandlerCommand interface {
 		CommandName() string
-		HelpText() string
+		Description(loc i18n.Locale) string // короткое описание для списка команд и меню телеграма
+		HelpText(loc i18n.Locale) string    // подробная справка с примерами для /help <команда>
 		IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error)
 		IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error)
 		Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error)
 		ClearData(ctx context.Context, message *tgbotapi.Message) error
 	}
 
+	// HandlerCallback интерфейс которому должны удовлетворять обработчики нажатий на inline кнопки,
+	// нажатие попадает в обработчик у которого CommandName совпадает с первой частью данных кнопки
+	HandlerCallback interface {
+		CommandName() string
+		RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error)
+	}
+
+	// DialogKeeper команда которой нужны начатые диалоги, перед ее запуском диалоги других команд не очищаются
+	DialogKeeper interface {
+		KeepDialogs() bool
+	}
+
+	// ArgumentsCommand команда с диалогом, шаги которого можно заполнить именованными аргументами, /help выводит их список
+	ArgumentsCommand interface {
+		Flags() []string
+	}
+
+	// AdminOnly команда только для администраторов, остальным пользователям она не видна в меню и справке и не выполняется
+	AdminOnly interface {
+		AdminOnly() bool
+	}
+
+	// UserLanguageProvider этот интерфейс реализует возможность получить язык выбранный пользователем в настройках
+	UserLanguageProvider interface {
+		UserLanguage(ctx context.Context, userId int64) (string, error)
+	}
+
 	// Command структура обертка для работы с всеми командами
 	Command struct {
-		kernel   *kernel.Kernel
-		bot      *telegram.Bot
-		commands []HandlerCommand
-		event    chan model.CommandEvent
+		kernel      *kernel.Kernel
+		bot         *telegram.Bot
+		commands    []HandlerCommand
+		callbacks   map[string]HandlerCallback
+		members     MemberProvider
+		languages   UserLanguageProvider
+		admins      AdminProvider
+		middlewares []Middleware
+		event       chan model.CommandEvent
+		dispatch    *dispatcher
 	}
 )
 
-func NewCommand(kernel *kernel.Kernel, bot *telegram.Bot, commands []HandlerCommand) *Command {
-	return &Command{
-		bot:      bot,
-		commands: commands,
-		kernel:   kernel,
-		event:    make(chan model.CommandEvent, 100),
+// NewCommand команды реализующие HandlerCallback автоматически подключаются к обработке нажатий на кнопки,
+// в callbacks передаются обработчики, которые не являются командами (например кнопки под уведомлениями)
+func NewCommand(kernel *kernel.Kernel, bot *telegram.Bot, members MemberProvider, languages UserLanguageProvider, admins AdminProvider, commands []HandlerCommand, callbacks []HandlerCallback) *Command {
+	callbackList := make(map[string]HandlerCallback, len(callbacks))
+	for _, handle := range commands {
+		if callback, ok := handle.(HandlerCallback); ok {
+			callbackList[callback.CommandName()] = callback
+		}
 	}
+
+	for _, callback := range callbacks {
+		callbackList[callback.CommandName()] = callback
+	}
+
+	cfg := kernel.Config().Telegram
+	c := &Command{
+		bot:       bot,
+		commands:  commands,
+		callbacks: callbackList,
+		members:   members,
+		languages: languages,
+		admins:    admins,
+		kernel:    kernel,
+		event:     make(chan model.CommandEvent, 100),
+		dispatch:  newDispatcher(kernel.Log(), max(1, cfg.Workers), max(1, cfg.MaxPending)),
+	}
+	c.initTracer()
+
+	return c
 }
 
+// ListenCommandAndMessage обновления одного чата обрабатываются по очереди, разных чатов - параллельно,
+// медленная команда в одном чате не задерживает ответы в остальных
 func (c *Command) ListenCommandAndMessage() {
 	for {
 		select {
 		case message := <-c.bot.Message:
-			c.runCommand(message)
+			c.dispatch.Submit(message.Chat.ID, func() { c.runCommand(message) })
 		case command := <-c.bot.Command:
-			c.runCommand(command)
+			c.dispatch.Submit(command.Chat.ID, func() { c.runCommand(command) })
+		case query := <-c.bot.Callback:
+			c.dispatch.Submit(query.Message.Chat.ID, func() { c.runCallback(query) })
 		}
 	}
 }
@@ -84,16 +167,16 @@ func (c *Command) CommandEventChanelRead() <-chan model.CommandEvent {
 func (c *Command) runCommand(message *tgbotapi.Message) {
 	const op = "telegram.command.runCommand"
 
-	cfg := c.kernel.Config().Jaeger
-	tp, err := tracing.NewJaegerTraceProvider(cfg.Url, cfg.Name, cfg.Env)
-	if err != nil {
-		c.kernel.Log().Error(fmt.Sprintf("%s: ошибка инициализации Jaeger: %s", op, err))
-	}
-
-	tracer = tp.Tracer(cfg.Name)
+	// пространство и язык определяются один раз на сообщение и доступны командам через контекст
+	ctx := c.withMember(context.Background(), senderId(message))
+	ctx = c.withLocale(ctx, senderId(message), message.From)
+	ctx = withAdmin(ctx, c.admins.IsAdmin(senderId(message)))
 
 	for _, handle := range c.commands {
-		ctx := context.Background()
+		if !isAllowed(ctx, handle) {
+			continue
+		}
+
 		is, err := handle.IsSupport(ctx, message)
 		if err != nil {
 			c.kernel.Log().Error(fmt.Sprintf("%s%s: error: %s", op, handle.CommandName(), err))
@@ -107,10 +190,12 @@ func (c *Command) runCommand(message *tgbotapi.Message) {
 		ctx, span := tracer.Start(ctx, fmt.Sprintf("message_from_%d", message.Chat.ID))
 		if message.IsCommand() {
 			span.SetAttributes(attribute.String("command start", handle.CommandName()))
-			c.clearAllCommand(ctx, span, message)
+			if keeper, ok := handle.(DialogKeeper); !ok || !keeper.KeepDialogs() {
+				c.clearAllCommand(ctx, span, message)
+			}
 		}
 
-		msg, err := handle.Run(ctx, message)
+		msg, err := c.chain(handle)(ctx, message)
 		if err != nil {
 			span.RecordError(err)
 			span.SetAttributes(attribute.String("Error RUN", handle.CommandName()))
@@ -124,6 +209,12 @@ func (c *Command) runCommand(message *tgbotapi.Message) {
 			c.kernel.Log().Debug(fmt.Sprintf("%s%s: %s", op, handle.CommandName(), err))
 		}
 
+		// команда могла ответить сама (например отправить картинку), тогда пустой текст не отправляется
+		if msg.Text == "" {
+			span.End()
+			continue
+		}
+
 		err = c.bot.SendMessage(msg)
 		if err != nil {
 			span.SetAttributes(attribute.String("error send message", handle.CommandName()))
@@ -135,6 +226,101 @@ func (c *Command) runCommand(message *tgbotapi.Message) {
 	}
 }
 
+func (c *Command) runCallback(query *tgbotapi.CallbackQuery) {
+	const op = "telegram.command.runCallback"
+
+	// телеграм ждет ответ на каждое нажатие, иначе кнопка останется в состоянии загрузки
+	defer func() {
+		if err := c.bot.Send(tgbotapi.NewCallback(query.ID, "")); err != nil {
+			c.kernel.Log().Error(fmt.Sprintf("%s: answer callback: %s", op, err))
+		}
+	}()
+
+	ctx := c.withLocale(c.withMember(context.Background(), query.From.ID), query.From.ID, query.From)
+	ctx = withAdmin(ctx, c.admins.IsAdmin(query.From.ID))
+
+	name, _ := parseCallbackData(query.Data)
+	handle, ok := c.callbacks[name]
+	if !ok || !isAllowed(ctx, handle) {
+		c.kernel.Log().Info(fmt.Sprintf("%s: unknown callback: %s", op, query.Data))
+		return
+	}
+
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("callback_from_%d", query.From.ID))
+	defer span.End()
+	span.SetAttributes(attribute.String("callback", query.Data))
+
+	msg, err := c.callbackChain(handle)(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		c.kernel.Log().Error(fmt.Sprintf("%s%s: error: %s", op, handle.CommandName(), err))
+	}
+
+	if msg == nil {
+		return
+	}
+
+	// новые сообщения отправляем через общую очередь, а редактирование и прочие запросы сразу
+	if message, ok := msg.(tgbotapi.MessageConfig); ok {
+		err = c.bot.SendMessage(message)
+	} else {
+		err = c.bot.Send(msg)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		c.kernel.Log().Error(fmt.Sprintf("%s-%s: %s", op, handle.CommandName(), err))
+	}
+}
+
+// withMember добавляет в контекст текущее пространство пользователя, незарегистрированный пользователь остается без пространства
+func (c *Command) withMember(ctx context.Context, userId int64) context.Context {
+	const op = "telegram.command.withMember"
+
+	member, err := c.members.CurrentMember(ctx, userId)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotMember) {
+			c.kernel.Log().Error(fmt.Sprintf("%s: %s", op, err))
+		}
+
+		return ctx
+	}
+
+	return withMember(ctx, member)
+}
+
+// withLocale добавляет в контекст язык пользователя: выбранный в настройках, а если не выбран - язык телеграма
+func (c *Command) withLocale(ctx context.Context, userId int64, from *tgbotapi.User) context.Context {
+	const op = "telegram.command.withLocale"
+
+	var telegramLanguage string
+	if from != nil {
+		telegramLanguage = from.LanguageCode
+	}
+
+	language, err := c.languages.UserLanguage(ctx, userId)
+	if err != nil {
+		c.kernel.Log().Error(fmt.Sprintf("%s: %s", op, err))
+	}
+
+	return i18n.WithLocale(ctx, i18n.Resolve(language, telegramLanguage))
+}
+
+// initTracer создает провайдер один раз на все команды, без Jaeger используется глобальный провайдер
+func (c *Command) initTracer() {
+	const op = "telegram.command.initTracer"
+
+	cfg := c.kernel.Config().Jaeger
+	tp, err := tracing.NewJaegerTraceProvider(cfg.Url, cfg.Name, cfg.Env)
+	if err != nil {
+		c.kernel.Log().Error(fmt.Sprintf("%s: ошибка инициализации Jaeger: %s", op, err))
+		tracer = otel.Tracer(cfg.Name)
+		return
+	}
+
+	tracer = tp.Tracer(cfg.Name)
+}
+
 func (c *Command) emitEvent(ctx context.Context, message *tgbotapi.Message, handle HandlerCommand, process model.ProcessType) error {
 
 	complete, err := handle.IsComplete(ctx, message)
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"html"
+	"strconv"
+	"strings"
+)
+
+type (
+	// ChatConnector этот интерфейс реализует возможность подключить чат к пространству для получения уведомлений
+	ChatConnector interface {
+		ConnectChat(ctx context.Context, workspaceId, chatId int64, title string) error
+	}
+
+	// ChatProvider этот интерфейс реализует возможность получить данные канала и проверить права пользователя в нем
+	ChatProvider interface {
+		Chat(chatId int64, username string) (tgbotapi.Chat, error)
+		IsChatAdmin(chatId, userId int64) (bool, error)
+	}
+
+	// ConnectChat структура для обработки команды подключения группового чата или канала к пространству
+	ConnectChat struct {
+		workspaceRepo ChatConnector
+		bot           ChatProvider
+	}
+)
+
+func NewConnectChatCommand(workspaceRepo ChatConnector, bot ChatProvider) *ConnectChat {
+	return &ConnectChat{
+		workspaceRepo: workspaceRepo,
+		bot:           bot,
+	}
+}
+
+func (c *ConnectChat) CommandName() string {
+	return ConnectChatCommand
+}
+
+func (c *ConnectChat) Description(loc i18n.Locale) string {
+	return loc.T("connect_chat.description")
+}
+
+func (c *ConnectChat) HelpText(loc i18n.Locale) string {
+	return loc.T("connect_chat.help")
+}
+
+func (c *ConnectChat) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == c.CommandName(), nil
+}
+
+// Run в группе подключает текущий чат, в личных сообщениях - канал или группу указанную аргументом: /connect_chat @channel
+func (c *ConnectChat) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	member, err := editorFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	chat := *message.Chat
+	if chat.IsPrivate() {
+		ref := strings.TrimSpace(message.CommandArguments())
+		if ref == "" {
+			msg.Text = loc.T("connect_chat.usage", c.CommandName(), c.CommandName())
+			return msg, nil
+		}
+
+		chat, err = c.chat(ref)
+		if err != nil {
+			msg.Text = loc.T("connect_chat.not_found")
+			return msg, nil
+		}
+	}
+
+	// подключить чат может только его администратор, иначе уведомления можно было бы отправлять в чужие каналы,
+	// а любой участник группы мог бы подключить ее к своему пространству
+	isAdmin, err := c.bot.IsChatAdmin(chat.ID, member.UserId)
+	if err != nil || !isAdmin {
+		msg.Text = loc.T("connect_chat.not_admin")
+		return msg, err
+	}
+
+	title := chat.Title
+	if title == "" {
+		title = chat.UserName
+	}
+
+	if err := c.workspaceRepo.ConnectChat(ctx, member.WorkspaceId, chat.ID, title); err != nil {
+		msg.Text = loc.T("connect_chat.error")
+		return msg, err
+	}
+
+	msg.Text = loc.T("connect_chat.success", html.EscapeString(title), WorkspaceCommand)
+
+	return msg, nil
+}
+
+func (c *ConnectChat) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
+
+func (c *ConnectChat) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// chat находит чат по идентификатору или @username
+func (c *ConnectChat) chat(ref string) (tgbotapi.Chat, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return c.bot.Chat(id, "")
+	}
+
+	if !strings.HasPrefix(ref, "@") {
+		ref = "@" + ref
+	}
+
+	return c.bot.Chat(0, ref)
+}
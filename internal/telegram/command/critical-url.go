@@ -0,0 +1,113 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+)
+
+type (
+	// UrlCriticalToggler этот интерфейс реализует возможность пометить ссылку критичной или снять отметку
+	UrlCriticalToggler interface {
+		ToggleCritical(workspaceId int64, url string) (bool, error)
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+	}
+
+	// CriticalUrl структура для обработки команды изменения критичности ссылки, уведомления по критичным ссылкам приходят и в тихие часы
+	CriticalUrl struct {
+		urlRepo UrlCriticalToggler
+		dialog  *Dialog
+	}
+)
+
+func NewCriticalUrlCommand(dialog DialogChain, urlRepo UrlCriticalToggler) *CriticalUrl {
+	c := &CriticalUrl{
+		urlRepo: urlRepo,
+	}
+
+	c.dialog = NewDialog(CriticalUrlCommand, dialog, editorFromContext, c.toggle,
+		urlDialogStep("critical_url.ask_url", c.list),
+	)
+
+	return c
+}
+
+func (c *CriticalUrl) CommandName() string {
+	return CriticalUrlCommand
+}
+
+func (c *CriticalUrl) Description(loc i18n.Locale) string {
+	return loc.T("critical_url.description")
+}
+
+func (c *CriticalUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("critical_url.help")
+}
+
+func (c *CriticalUrl) Flags() []string {
+	return c.dialog.Flags()
+}
+
+func (c *CriticalUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return c.dialog.IsSupport(ctx, message)
+}
+
+func (c *CriticalUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return c.dialog.IsComplete(ctx, message)
+}
+
+func (c *CriticalUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", c.CommandName()))
+	defer span.End()
+
+	msg, err := c.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback меняет критичность ссылки выбранной кнопкой
+func (c *CriticalUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", c.CommandName()))
+	defer span.End()
+
+	msg, err := c.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+func (c *CriticalUrl) list(s *DialogState) (model.PingList, error) {
+	return c.urlRepo.UrlListByWorkspace(s.Member.WorkspaceId)
+}
+
+func (c *CriticalUrl) toggle(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+	url := s.Answers[answerUrl]
+
+	critical, err := c.urlRepo.ToggleCritical(s.Member.WorkspaceId, url)
+	if err != nil {
+		msg.Text = loc.T("common.error_update")
+		return msg, err
+	}
+
+	msg.Text = loc.T("critical_url.off", html.EscapeString(url))
+	if critical {
+		msg.Text = loc.T("critical_url.on", html.EscapeString(url))
+	}
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
+}
+
+func (c *CriticalUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return c.dialog.Clear(ctx, message.Chat.ID)
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/kernel"
+	"github.com/ivankoTut/ping-url/internal/telegram"
+	"runtime/debug"
+	"time"
+)
+
+// dialogReleaseInterval как часто проверять диалоги без ответа
+const dialogReleaseInterval = time.Second * 30
+
+type (
+	// DialogReleaser этот интерфейс реализует возможность удалить диалоги, которые ждут ответа дольше допустимого
+	DialogReleaser interface {
+		ReleaseExpiredDialogs(ctx context.Context, now time.Time) ([]string, error)
+	}
+
+	// DialogWatcher отменяет диалоги команд оставшиеся без ответа и сообщает об этом в чат
+	DialogWatcher struct {
+		dialogs   DialogReleaser
+		languages UserLanguageProvider
+		kernel    *kernel.Kernel
+		bot       *telegram.Bot
+	}
+)
+
+func NewDialogWatcher(dialogs DialogReleaser, languages UserLanguageProvider, k *kernel.Kernel, bot *telegram.Bot) *DialogWatcher {
+	return &DialogWatcher{
+		dialogs:   dialogs,
+		languages: languages,
+		kernel:    k,
+		bot:       bot,
+	}
+}
+
+func (d *DialogWatcher) Run() {
+	ticker := time.NewTicker(dialogReleaseInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		d.release()
+	}
+}
+
+func (d *DialogWatcher) release() {
+	const op = "telegram.command.DialogWatcher.release"
+
+	defer func() {
+		if r := recover(); r != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, panic: %v\n%s", op, r, debug.Stack()))
+		}
+	}()
+
+	ctx := context.Background()
+	keys, err := d.dialogs.ReleaseExpiredDialogs(ctx, time.Now())
+	if err != nil {
+		d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		return
+	}
+
+	timeout := d.kernel.Config().Telegram.DialogTimeout
+	for _, key := range keys {
+		chatId, command, ok := parseDialogKey(key)
+		if !ok {
+			d.kernel.Log().Error(fmt.Sprintf("%s, wrong dialog key: %s", op, key))
+			continue
+		}
+
+		// диалоги ведутся в личном чате, id чата совпадает с id пользователя
+		language, err := d.languages.UserLanguage(ctx, chatId)
+		if err != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		}
+
+		loc := i18n.Resolve(language, "")
+		if err := d.bot.SendMessage(tgbotapi.NewMessage(chatId, loc.T("dialog.timeout", command, loc.Duration(timeout)))); err != nil {
+			d.kernel.Log().Error(fmt.Sprintf("%s, error: %s", op, err))
+		}
+	}
+}
@@ -0,0 +1,677 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/redis/go-redis/v9"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// кнопки диалога имеют данные вида "<команда>:dialog:<действие>:<номер шага>[:<аргумент>]"
+const dialogCallback = "dialog"
+
+const (
+	dialogActionPick   = "pick"   // выбран вариант ответа, аргумент - id варианта
+	dialogActionPage   = "page"   // переход на страницу вариантов, аргумент - номер страницы
+	dialogActionBack   = "back"   // возврат к предыдущему шагу
+	dialogActionCancel = "cancel" // диалог отменен
+)
+
+const dialogPageSize = urlPickerPageSize
+
+// dialogArgumentsAnswer ответ под которым хранятся аргументы команды для шагов, до которых диалог еще не дошел
+const dialogArgumentsAnswer = "--"
+
+// errDialogExpired диалог не найден: отменен, истекло время ответа или его шаг больше не существует
+var errDialogExpired = errors.New("dialog expired")
+
+type (
+	// DialogOption вариант ответа на шаг диалога, выбирается inline кнопкой
+	DialogOption struct {
+		Id    string // короткий идентификатор для данных кнопки, телеграм ограничивает их 64 байтами
+		Label string
+		Value string // значение которое сохраняется как ответ
+	}
+
+	// DialogState данные текущего диалога, доступны шагам и завершению диалога
+	DialogState struct {
+		ChatId  int64
+		Member  model.Member
+		Answers map[string]string // ответы на пройденные шаги по DialogStep.Name
+	}
+
+	// DialogStep шаг диалога, ошибки созданные через i18n.NewError показываются пользователю,
+	// остальные ошибки считаются внутренними и попадают в лог
+	DialogStep struct {
+		Name     string                                                            // ключ под которым сохраняется ответ
+		Prompt   func(ctx context.Context, s *DialogState) (string, error)         // текст вопроса
+		Validate func(ctx context.Context, s *DialogState, value string) error     // проверка ответа, при ошибке шаг повторяется
+		Parse    func(value string) string                                         // приведение ответа перед сохранением
+		Skip     func(s *DialogState) bool                                         // шаг пропускается если вернет true
+		Options  func(ctx context.Context, s *DialogState) ([]DialogOption, error) // варианты ответа кнопками, ответ текстом тоже принимается
+		Empty    string                                                            // ключ сообщения если вариантов нет, диалог при этом завершается
+		Flag     string                                                            // имя аргумента команды "--flag" в дополнение к Name, например "tag" для шага "tags"
+		Optional bool                                                              // вопрос не задается, ответ можно передать только аргументом команды
+		Read     func(message *tgbotapi.Message) (string, error)                   // ответ из сообщения вместо его текста, например отправленный файл
+	}
+
+	// DialogSubmit завершение диалога после ответа на последний шаг,
+	// ошибка созданная через i18n.NewError оставляет диалог на последнем шаге
+	DialogSubmit func(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error)
+
+	// Dialog пошаговый диалог команды: хранит шаг и ответы в DialogChain, обрабатывает выбор вариантов кнопками,
+	// возврат к предыдущему шагу и отмену, время ожидания ответа ограничивает хранилище DialogChain.
+	// На шаги можно ответить сразу аргументами команды, вопросы задаются только для недостающих ответов
+	Dialog struct {
+		command string
+		chain   DialogChain
+		access  func(ctx context.Context) (model.Member, error) // кто может вести диалог, например editorFromContext
+		steps   []DialogStep
+		submit  DialogSubmit
+	}
+)
+
+func NewDialog(command string, chain DialogChain, access func(ctx context.Context) (model.Member, error), submit DialogSubmit, steps ...DialogStep) *Dialog {
+	return &Dialog{
+		command: command,
+		chain:   chain,
+		access:  access,
+		steps:   steps,
+		submit:  submit,
+	}
+}
+
+// dialogPrompt вопрос шага из каталога i18n
+func dialogPrompt(key string) func(ctx context.Context, s *DialogState) (string, error) {
+	return func(ctx context.Context, s *DialogState) (string, error) {
+		return i18n.FromContext(ctx).T(key), nil
+	}
+}
+
+// dialogKey ключ шага диалога команды в чате, ответы хранятся под ключом с окончанием "_answer"
+func dialogKey(chatId int64, command string) string {
+	return fmt.Sprintf("%d_%s", chatId, command)
+}
+
+// parseDialogKey разбирает ключ шага диалога на чат и команду
+func parseDialogKey(key string) (int64, string, bool) {
+	chat, command, ok := strings.Cut(key, "_")
+	if !ok {
+		return 0, "", false
+	}
+
+	chatId, err := strconv.ParseInt(chat, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return chatId, command, true
+}
+
+func (d *Dialog) key(chatId int64) string {
+	return dialogKey(chatId, d.command)
+}
+
+func (d *Dialog) keyAnswer(chatId int64) string {
+	return d.key(chatId) + "_answer"
+}
+
+// IsSupport команда начинает диалог, остальные сообщения попадают в него пока он не завершен
+func (d *Dialog) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() == true {
+		return message.Command() == d.command, nil
+	}
+
+	return d.chain.DialogExist(ctx, d.key(message.Chat.ID))
+}
+
+func (d *Dialog) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	is, err := d.chain.DialogExist(ctx, d.key(message.Chat.ID))
+	if err != nil {
+		return false, err
+	}
+
+	return is == false, nil
+}
+
+// IsCallback относится ли нажатая кнопка к диалогу, остальные кнопки команда обрабатывает сама
+func (d *Dialog) IsCallback(data string) bool {
+	_, args := parseCallbackData(data)
+
+	return len(args) > 0 && args[0] == dialogCallback
+}
+
+// Clear удаляет шаг и ответы диалога в чате
+func (d *Dialog) Clear(ctx context.Context, chatId int64) error {
+	if err := d.chain.DeleteDialog(ctx, d.key(chatId)); err != nil {
+		return err
+	}
+
+	return d.chain.DeleteDialog(ctx, d.keyAnswer(chatId))
+}
+
+// Run команда начинает диалог заново, аргументы команды заполняют шаги без вопросов, см. fill.
+// Текстовое сообщение считается ответом на текущий шаг
+func (d *Dialog) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.Run"
+
+	chatId := message.Chat.ID
+	msg := tgbotapi.NewMessage(chatId, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := d.access(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	if message.IsCommand() {
+		args, err := parseCommandArguments(message.CommandArguments())
+		if err == nil {
+			err = d.checkFlags(args)
+		}
+
+		if err != nil {
+			msg.Text = loc.Error(err)
+			return msg, d.Clear(ctx, chatId)
+		}
+
+		return d.start(ctx, chatId, member, args)
+	}
+
+	s, step, err := d.load(ctx, chatId, member)
+	if errors.Is(err, errDialogExpired) {
+		msg.Text = loc.T("dialog.expired", d.command)
+		return msg, d.Clear(ctx, chatId)
+	}
+
+	if err != nil {
+		msg.Text = loc.T("common.error")
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+
+	value := strings.TrimSpace(message.Text)
+	if read := d.steps[step].Read; read != nil {
+		if value, err = read(message); err != nil {
+			msg.Text = loc.Error(err)
+			if isDialogInputError(err) {
+				return msg, nil
+			}
+
+			return msg, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return d.answer(ctx, s, step, value)
+}
+
+// Start начинает диалог не командой, а кнопкой или из ответа другой команды,
+// args заполняют шаги так же как аргументы команды
+func (d *Dialog) Start(ctx context.Context, chatId int64, args commandArguments) (tgbotapi.MessageConfig, error) {
+	member, err := d.access(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	return d.start(ctx, chatId, member, args)
+}
+
+// start удаляет незавершенный диалог и заполняет шаги нового аргументами
+func (d *Dialog) start(ctx context.Context, chatId int64, member model.Member, args commandArguments) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.start"
+
+	if err := d.Clear(ctx, chatId); err != nil {
+		msg := tgbotapi.NewMessage(chatId, i18n.FromContext(ctx).T("common.error"))
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s := &DialogState{ChatId: chatId, Member: member, Answers: map[string]string{}}
+
+	return d.fill(ctx, s, 0, args)
+}
+
+// RunCallback выбор варианта, переход по страницам вариантов, возврат к предыдущему шагу и отмена диалога
+func (d *Dialog) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	const op = "telegram.command.Dialog.RunCallback"
+
+	chatId := query.Message.Chat.ID
+	msg := tgbotapi.NewMessage(chatId, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := d.access(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) < 3 || args[0] != dialogCallback {
+		return nil, fmt.Errorf("%s: не верные данные кнопки: %s", op, query.Data)
+	}
+
+	action := args[1]
+	step, err := strconv.Atoi(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("%s: не верные данные кнопки: %s", op, query.Data)
+	}
+
+	if action == dialogActionCancel {
+		if err := d.Clear(ctx, chatId); err != nil {
+			msg.Text = loc.T("common.error")
+			return msg, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("dialog.cancelled")), nil
+	}
+
+	s, current, err := d.load(ctx, chatId, member)
+	if errors.Is(err, errDialogExpired) {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("dialog.expired", d.command)), d.Clear(ctx, chatId)
+	}
+
+	if err != nil {
+		msg.Text = loc.T("common.error")
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// кнопка из вопроса который уже пройден, такие кнопки просто убираются
+	if step != current {
+		return tgbotapi.NewEditMessageReplyMarkup(chatId, query.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}), nil
+	}
+
+	switch action {
+	case dialogActionBack:
+		prev := d.prev(s, step)
+		if prev < 0 {
+			return nil, nil
+		}
+
+		return d.ask(ctx, s, prev, "")
+	case dialogActionPick, dialogActionPage:
+		if len(args) != 4 {
+			return nil, fmt.Errorf("%s: не верные данные кнопки: %s", op, query.Data)
+		}
+
+		options, err := d.options(ctx, s, step)
+		if err != nil {
+			msg.Text = loc.Error(err)
+			return msg, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if action == dialogActionPage {
+			page, _ := strconv.Atoi(args[3])
+			return tgbotapi.NewEditMessageReplyMarkup(chatId, query.Message.MessageID, d.keyboard(loc, s, step, options, page)), nil
+		}
+
+		for _, option := range options {
+			if option.Id == args[3] {
+				return d.answer(ctx, s, step, option.Value)
+			}
+		}
+
+		msg.Text = loc.T("dialog.option_not_found")
+
+		return msg, nil
+	default:
+		return nil, fmt.Errorf("%s: неизвестное действие: %s", op, action)
+	}
+}
+
+// load текущий шаг и ответы диалога, errDialogExpired если диалога нет или он устарел
+func (d *Dialog) load(ctx context.Context, chatId int64, member model.Member) (*DialogState, int, error) {
+	step, err := d.chain.CurrentState(ctx, d.key(chatId))
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, errDialogExpired
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	answers, err := d.chain.GetAnswer(ctx, d.keyAnswer(chatId))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if step < 0 || step >= len(d.steps) {
+		return nil, 0, errDialogExpired
+	}
+
+	return &DialogState{ChatId: chatId, Member: member, Answers: answers}, step, nil
+}
+
+// answer проверяет и сохраняет ответ на шаг i и переходит к следующему шагу,
+// оставшиеся аргументы команды заполняют следующие шаги, после последнего шага диалог завершается
+func (d *Dialog) answer(ctx context.Context, s *DialogState, i int, value string) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.answer"
+
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+
+	if err := d.accept(ctx, s, i, value); err != nil {
+		msg.Text = i18n.FromContext(ctx).Error(err)
+		if isDialogInputError(err) {
+			return msg, nil
+		}
+
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var args commandArguments
+	_ = json.Unmarshal([]byte(s.Answers[dialogArgumentsAnswer]), &args)
+
+	return d.fill(ctx, s, i+1, args)
+}
+
+// accept проверяет и сохраняет ответ на шаг i
+func (d *Dialog) accept(ctx context.Context, s *DialogState, i int, value string) error {
+	step := d.steps[i]
+
+	if step.Validate != nil {
+		if err := step.Validate(ctx, s, value); err != nil {
+			return err
+		}
+	}
+
+	if step.Parse != nil {
+		value = step.Parse(value)
+	}
+
+	if err := d.chain.SaveAnswer(ctx, d.keyAnswer(s.ChatId), step.Name, value); err != nil {
+		return i18n.WrapError(err, "common.error_state")
+	}
+
+	s.Answers[step.Name] = value
+
+	return nil
+}
+
+// fill отвечает на шаги начиная с from аргументами команды и задает вопрос первого шага без ответа,
+// неверный ответ из аргумента проверяется так же как в диалоге и его вопрос повторяется вместе с ошибкой.
+// Аргументы, которые не понадобились до вопроса, сохраняются и заполняют шаги после ответа на него
+func (d *Dialog) fill(ctx context.Context, s *DialogState, from int, args commandArguments) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.fill"
+
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+	last := -1
+
+	for i := from; i < len(d.steps); i++ {
+		step := d.steps[i]
+		if step.Skip != nil && step.Skip(s) {
+			continue
+		}
+
+		value, ok := args.take(step)
+		if !ok {
+			if step.Optional {
+				continue
+			}
+
+			return d.wait(ctx, s, i, args, "")
+		}
+
+		value, err := d.optionValue(ctx, s, i, value)
+		if err == nil {
+			err = d.accept(ctx, s, i, value)
+		}
+
+		if isDialogInputError(err) && !step.Optional {
+			return d.wait(ctx, s, i, args, loc.Error(err))
+		}
+
+		if err != nil {
+			return d.abort(ctx, s, err)
+		}
+
+		last = i
+	}
+
+	if len(args.Positional) > 0 {
+		return d.abort(ctx, s, i18n.NewError("arguments.error_extra", strings.Join(args.Positional, " ")))
+	}
+
+	// при ошибке ввода на завершении диалог остается на последнем заполненном шаге
+	if last >= 0 {
+		if _, err := d.chain.SaveState(ctx, d.key(s.ChatId), last); err != nil {
+			msg.Text = loc.T("common.error_state")
+			return msg, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return d.finish(ctx, s)
+}
+
+// wait сохраняет оставшиеся аргументы команды и задает вопрос шага i, notice выводится перед вопросом
+func (d *Dialog) wait(ctx context.Context, s *DialogState, i int, args commandArguments, notice string) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.wait"
+
+	if !args.empty() || s.Answers[dialogArgumentsAnswer] != "" {
+		value := ""
+		if !args.empty() {
+			data, err := json.Marshal(args)
+			if err != nil {
+				return d.abort(ctx, s, fmt.Errorf("%s: %w", op, err))
+			}
+
+			value = string(data)
+		}
+
+		if err := d.chain.SaveAnswer(ctx, d.keyAnswer(s.ChatId), dialogArgumentsAnswer, value); err != nil {
+			return d.abort(ctx, s, i18n.WrapError(fmt.Errorf("%s: %w", op, err), "common.error_state"))
+		}
+
+		s.Answers[dialogArgumentsAnswer] = value
+	}
+
+	return d.ask(ctx, s, i, notice)
+}
+
+// optionValue аргументом команды можно передать id варианта ответа, например id ссылки вместо адреса
+func (d *Dialog) optionValue(ctx context.Context, s *DialogState, i int, value string) (string, error) {
+	options, err := d.options(ctx, s, i)
+	if err != nil {
+		return "", err
+	}
+
+	for _, option := range options {
+		if option.Id == value {
+			return option.Value, nil
+		}
+	}
+
+	return value, nil
+}
+
+// Flags именованные аргументы команды по одному на шаг в порядке шагов, для подсказок и справки
+func (d *Dialog) Flags() []string {
+	names := make([]string, 0, len(d.steps))
+	for _, step := range d.steps {
+		names = append(names, "--"+step.flags()[len(step.flags())-1])
+	}
+
+	return names
+}
+
+// checkFlags каждый именованный аргумент должен относиться к шагу диалога
+func (d *Dialog) checkFlags(args commandArguments) error {
+	known := make(map[string]bool)
+	for _, step := range d.steps {
+		for _, name := range step.flags() {
+			known[name] = true
+		}
+	}
+
+	for name := range args.Flags {
+		if !known[name] {
+			return i18n.NewError("arguments.error_unknown", name, d.command, strings.Join(d.Flags(), " "))
+		}
+	}
+
+	return nil
+}
+
+// ask переводит диалог на шаг i и задает его вопрос, notice выводится перед вопросом,
+// при i за последним шагом диалог завершается
+func (d *Dialog) ask(ctx context.Context, s *DialogState, i int, notice string) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.ask"
+
+	if i >= len(d.steps) {
+		return d.finish(ctx, s)
+	}
+
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+	step := d.steps[i]
+
+	options, err := d.options(ctx, s, i)
+	if err != nil {
+		return d.abort(ctx, s, err)
+	}
+
+	if step.Options != nil && len(options) == 0 && step.Empty != "" {
+		return d.abort(ctx, s, i18n.NewError(step.Empty))
+	}
+
+	text, err := step.Prompt(ctx, s)
+	if err != nil {
+		return d.abort(ctx, s, err)
+	}
+
+	if _, err := d.chain.SaveState(ctx, d.key(s.ChatId), i); err != nil {
+		msg.Text = loc.T("common.error_state")
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg.Text = text
+	if notice != "" {
+		msg.Text = html.EscapeString(notice) + "\n\n" + text
+	}
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ReplyMarkup = d.keyboard(loc, s, i, options, 0)
+
+	return msg, nil
+}
+
+// finish завершает диалог, при ошибке ввода диалог остается на последнем шаге
+func (d *Dialog) finish(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg, err := d.submit(ctx, s)
+	if isDialogInputError(err) {
+		return tgbotapi.NewMessage(s.ChatId, i18n.FromContext(ctx).Error(err)), nil
+	}
+
+	if clearErr := d.Clear(ctx, s.ChatId); clearErr != nil {
+		err = errors.Join(err, clearErr)
+	}
+
+	return msg, err
+}
+
+// abort прерывает диалог с текстом ошибки, внутренние ошибки возвращаются для записи в лог
+func (d *Dialog) abort(ctx context.Context, s *DialogState, err error) (tgbotapi.MessageConfig, error) {
+	const op = "telegram.command.Dialog.abort"
+
+	msg := tgbotapi.NewMessage(s.ChatId, i18n.FromContext(ctx).Error(err))
+	if isDialogInputError(err) {
+		err = nil
+	} else {
+		err = fmt.Errorf("%s: %w", op, err)
+	}
+
+	if clearErr := d.Clear(ctx, s.ChatId); clearErr != nil {
+		err = errors.Join(err, clearErr)
+	}
+
+	return msg, err
+}
+
+// options варианты ответа на шаг i, шаг без вариантов отвечается только текстом
+func (d *Dialog) options(ctx context.Context, s *DialogState, i int) ([]DialogOption, error) {
+	if d.steps[i].Options == nil {
+		return nil, nil
+	}
+
+	options, err := d.steps[i].Options(ctx, s)
+	if err != nil {
+		return nil, i18n.WrapError(err, "common.error_list")
+	}
+
+	return options, nil
+}
+
+// prev ближайший шаг перед from, на который можно вернуться, -1 если такого нет
+func (d *Dialog) prev(s *DialogState, from int) int {
+	for i := from - 1; i >= 0; i-- {
+		if !d.steps[i].Optional && (d.steps[i].Skip == nil || !d.steps[i].Skip(s)) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// flags имена аргументов команды для шага, последнее имя выводится в подсказке
+func (step DialogStep) flags() []string {
+	if step.Flag == "" {
+		return []string{normalizeFlag(step.Name)}
+	}
+
+	return []string{normalizeFlag(step.Name), normalizeFlag(step.Flag)}
+}
+
+// keyboard варианты ответа с постраничной навигацией и кнопки возврата и отмены, page начинается с 0
+func (d *Dialog) keyboard(loc i18n.Locale, s *DialogState, i int, options []DialogOption, page int) tgbotapi.InlineKeyboardMarkup {
+	step := strconv.Itoa(i)
+	pages := max(1, (len(options)+dialogPageSize-1)/dialogPageSize)
+	page = max(0, min(page, pages-1))
+
+	from := page * dialogPageSize
+	to := min(from+dialogPageSize, len(options))
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, dialogPageSize+2)
+	for _, option := range options[from:to] {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			option.Label,
+			callbackData(d.command, dialogCallback, dialogActionPick, step, option.Id),
+		)))
+	}
+
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.back"), callbackData(d.command, dialogCallback, dialogActionPage, step, strconv.Itoa(page-1))))
+		}
+
+		if page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("url_picker.next", page+1, pages),
+				callbackData(d.command, dialogCallback, dialogActionPage, step, strconv.Itoa(page+1)),
+			))
+		}
+
+		rows = append(rows, nav)
+	}
+
+	control := make([]tgbotapi.InlineKeyboardButton, 0, 2)
+	if d.prev(s, i) >= 0 {
+		control = append(control, tgbotapi.NewInlineKeyboardButtonData(loc.T("dialog.back"), callbackData(d.command, dialogCallback, dialogActionBack, step)))
+	}
+	control = append(control, tgbotapi.NewInlineKeyboardButtonData(loc.T("dialog.cancel"), callbackData(d.command, dialogCallback, dialogActionCancel, step)))
+
+	return tgbotapi.NewInlineKeyboardMarkup(append(rows, control)...)
+}
+
+// isDialogInputError ошибка ввода пользователя: переводимая ошибка без внутренней причины
+func isDialogInputError(err error) bool {
+	var inputErr *i18n.Error
+
+	return errors.As(err, &inputErr) && inputErr.Unwrap() == nil
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+type (
+	// chatLane очередь обновлений одного чата, обновления чата выполняются строго по очереди
+	chatLane struct {
+		chatId int64
+		jobs   []func()
+	}
+
+	// dispatcher выполняет обновления разных чатов параллельно на ограниченном кол-ве обработчиков,
+	// сохраняя порядок внутри чата: у чата одновременно выполняется не больше одного обновления
+	dispatcher struct {
+		log     *slog.Logger
+		mu      sync.Mutex
+		lanes   map[int64]*chatLane
+		ready   chan *chatLane // чаты у которых есть обновление готовое к выполнению
+		pending chan struct{}  // ограничение кол-ва ожидающих обновлений, при заполнении Submit блокируется
+	}
+)
+
+func newDispatcher(log *slog.Logger, workers, maxPending int) *dispatcher {
+	d := &dispatcher{
+		log:     log,
+		lanes:   make(map[int64]*chatLane),
+		ready:   make(chan *chatLane, maxPending),
+		pending: make(chan struct{}, maxPending),
+	}
+
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+
+	return d
+}
+
+// Submit ставит обновление в очередь чата, пока ожидающих обновлений слишком много - ждет,
+// так получение новых обновлений от телеграма притормаживает вместе с обработкой
+func (d *dispatcher) Submit(chatId int64, job func()) {
+	d.pending <- struct{}{}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lane, ok := d.lanes[chatId]
+	if ok {
+		// чат уже ждет обработчика или выполняется, обновление выполнится после предыдущих
+		lane.jobs = append(lane.jobs, job)
+		return
+	}
+
+	lane = &chatLane{chatId: chatId, jobs: []func(){job}}
+	d.lanes[chatId] = lane
+	d.ready <- lane
+}
+
+// work выполняет по одному обновлению чата и возвращает чат в конец очереди,
+// чтобы чат с большим кол-вом обновлений не задерживал остальные
+func (d *dispatcher) work() {
+	for lane := range d.ready {
+		d.mu.Lock()
+		job := lane.jobs[0]
+		lane.jobs = lane.jobs[1:]
+		d.mu.Unlock()
+
+		d.run(job)
+		<-d.pending
+
+		d.mu.Lock()
+		if len(lane.jobs) == 0 {
+			delete(d.lanes, lane.chatId)
+			d.mu.Unlock()
+			continue
+		}
+		d.mu.Unlock()
+
+		// в ready не бывает больше чатов чем ожидающих обновлений, поэтому отправка не блокируется
+		d.ready <- lane
+	}
+}
+
+// run выполняет обновление, паника вне middleware команды (например в IsSupport или ClearData)
+// записывается в лог и не останавливает обработчик
+func (d *dispatcher) run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.log.Error(fmt.Sprintf("telegram.command.dispatcher.run: panic: %v\n%s", r, debug.Stack()))
+		}
+	}()
+
+	job()
+}
@@ -0,0 +1,247 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"html"
+)
+
+const (
+	answerEditUrlField = "field"        // изменяемое поле ссылки
+	answerEditUrlValue = "value"        // новое значение выбранного поля
+	answerTags         = "tags"         // теги ссылки через пробел или запятую
+	answerRepeatAlert  = "repeat_alert" // интервал повтора уведомления о недоступности
+)
+
+// editUrlFields изменяемые поля ссылки в порядке кнопок
+var editUrlFields = []string{answerUrl, answerConnectionTime, answerPingTime, answerRepeatAlert, answerTags}
+
+type (
+	// UrlEditor этот интерфейс реализует возможность изменить настройки ссылки без ее пересоздания
+	UrlEditor interface {
+		EditUrl(ctx context.Context, workspaceId, id int64, patch model.PingPatch) (model.Ping, error)
+	}
+
+	// EditUrl структура для обработки команды изменения ссылки
+	EditUrl struct {
+		urlRepo UserUrlList
+		editor  UrlEditor
+		dialog  *Dialog
+	}
+)
+
+func NewEditUrlCommand(dialog DialogChain, urlRepo UserUrlList, editor UrlEditor) *EditUrl {
+	e := &EditUrl{
+		urlRepo: urlRepo,
+		editor:  editor,
+	}
+
+	e.dialog = NewDialog(EditUrlCommand, dialog, editorFromContext, e.save,
+		urlDialogStep("edit_url.ask_pick", e.list),
+		DialogStep{Name: answerEditUrlField, Prompt: e.fieldsPrompt, Options: e.fieldOptions, Validate: validateEditUrlField},
+		DialogStep{Name: answerEditUrlValue, Prompt: e.valuePrompt, Validate: validateEditUrlValue},
+	)
+
+	return e
+}
+
+func (e *EditUrl) CommandName() string {
+	return EditUrlCommand
+}
+
+func (e *EditUrl) Description(loc i18n.Locale) string {
+	return loc.T("edit_url.description")
+}
+
+func (e *EditUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("edit_url.help")
+}
+
+func (e *EditUrl) Flags() []string {
+	return e.dialog.Flags()
+}
+
+func (e *EditUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return e.dialog.IsSupport(ctx, message)
+}
+
+func (e *EditUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return e.dialog.IsComplete(ctx, message)
+}
+
+func (e *EditUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", e.CommandName()))
+	defer span.End()
+
+	msg, err := e.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback выбор ссылки и поля кнопками, после выбора поля диалог ждет ввода нового значения
+func (e *EditUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", e.CommandName()))
+	defer span.End()
+
+	msg, err := e.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+func (e *EditUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", e.CommandName()))
+	defer span.End()
+
+	if err := e.dialog.Clear(ctx, message.Chat.ID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (e *EditUrl) list(s *DialogState) (model.PingList, error) {
+	return e.urlRepo.UrlListByWorkspace(s.Member.WorkspaceId)
+}
+
+// ping выбранная на первом шаге ссылка
+func (e *EditUrl) ping(s *DialogState) (model.Ping, error) {
+	list, err := e.list(s)
+	if err != nil {
+		return model.Ping{}, i18n.WrapError(err, "common.error_list")
+	}
+
+	ping, ok := pingByUrl(list, s.Answers[answerUrl])
+	if !ok {
+		return model.Ping{}, i18n.NewError("common.url_not_found")
+	}
+
+	return ping, nil
+}
+
+// fieldsPrompt текущие настройки ссылки, под ними кнопки выбора изменяемого поля
+func (e *EditUrl) fieldsPrompt(ctx context.Context, s *DialogState) (string, error) {
+	ping, err := e.ping(s)
+	if err != nil {
+		return "", err
+	}
+
+	loc := i18n.FromContext(ctx)
+
+	return loc.T("edit_url.fields", html.EscapeString(ping.Url), ping.ConnectionTime, ping.PingTime, repeatAlertText(loc, ping), tagsText(loc, ping.Tags)), nil
+}
+
+func (e *EditUrl) fieldOptions(ctx context.Context, s *DialogState) ([]DialogOption, error) {
+	loc := i18n.FromContext(ctx)
+
+	options := make([]DialogOption, 0, len(editUrlFields))
+	for _, field := range editUrlFields {
+		options = append(options, DialogOption{Id: field, Label: loc.T("edit_url.button_" + field), Value: field})
+	}
+
+	return options, nil
+}
+
+// valuePrompt вопрос о новом значении выбранного поля
+func (e *EditUrl) valuePrompt(ctx context.Context, s *DialogState) (string, error) {
+	return i18n.FromContext(ctx).T("edit_url.ask_" + s.Answers[answerEditUrlField]), nil
+}
+
+// save сохраняет новое значение, если адрес уже занят другой ссылкой диалог ждет другой адрес
+func (e *EditUrl) save(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+
+	current, err := e.ping(s)
+	if err != nil {
+		msg.Text = loc.Error(err)
+		if isDialogInputError(err) {
+			return msg, nil
+		}
+
+		return msg, err
+	}
+
+	ping, err := e.editor.EditUrl(ctx, s.Member.WorkspaceId, current.Id, editUrlPatch(s.Answers[answerEditUrlField], s.Answers[answerEditUrlValue]))
+	if errors.Is(err, storage.ErrUrlExists) {
+		return msg, i18n.NewError("edit_url.url_exists")
+	}
+
+	if errors.Is(err, storage.ErrUrlNotFound) {
+		msg.Text = loc.T("common.url_not_found")
+		return msg, nil
+	}
+
+	if err != nil {
+		msg.Text = loc.T("common.error_update")
+		return msg, err
+	}
+
+	msg.Text = loc.T("edit_url.success", html.EscapeString(ping.Url), ping.ConnectionTime, ping.PingTime, repeatAlertText(loc, ping), tagsText(loc, ping.Tags))
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
+}
+
+func validateEditUrlField(ctx context.Context, s *DialogState, value string) error {
+	for _, field := range editUrlFields {
+		if field == value {
+			return nil
+		}
+	}
+
+	return i18n.NewError("edit_url.invalid_field")
+}
+
+func validateEditUrlValue(ctx context.Context, s *DialogState, value string) error {
+	if err := editUrlPatch(s.Answers[answerEditUrlField], value).Validate(); err != nil {
+		return i18n.NewError("edit_url.invalid_value", i18n.FromContext(ctx).Error(err))
+	}
+
+	return nil
+}
+
+// editUrlPatch изменение одного поля ссылки
+func editUrlPatch(field, value string) model.PingPatch {
+	var patch model.PingPatch
+	switch field {
+	case answerUrl:
+		patch.Url = &value
+	case answerConnectionTime:
+		patch.ConnectionTime = &value
+	case answerPingTime:
+		patch.PingTime = &value
+	case answerTags:
+		tags := model.ParseTags(value)
+		patch.Tags = &tags
+	case answerRepeatAlert:
+		repeat := model.ParseRepeatAlert(value)
+		patch.RepeatAlert = &repeat
+	}
+
+	return patch
+}
+
+// repeatAlertText интервал повтора уведомления ссылки для пользователя
+func repeatAlertText(loc i18n.Locale, ping model.Ping) string {
+	if ping.RepeatAlert == "" {
+		return loc.T("edit_url.repeat_default")
+	}
+
+	if ping.RepeatInterval(0) == 0 {
+		return loc.T("edit_url.repeat_off")
+	}
+
+	return html.EscapeString(ping.RepeatAlert)
+}
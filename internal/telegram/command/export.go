@@ -0,0 +1,151 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"strings"
+)
+
+type (
+	// DocumentSender этот интерфейс реализует возможность отправить файл в чат
+	DocumentSender interface {
+		Send(c tgbotapi.Chattable) error
+	}
+
+	// Export структура для обработки команды выгрузки ссылок в файл
+	Export struct {
+		transfer MonitorTransfer
+		sender   DocumentSender
+	}
+)
+
+func NewExportCommand(transfer MonitorTransfer, sender DocumentSender) *Export {
+	return &Export{
+		transfer: transfer,
+		sender:   sender,
+	}
+}
+
+func (e *Export) CommandName() string {
+	return ExportCommand
+}
+
+func (e *Export) Description(loc i18n.Locale) string {
+	return loc.T("export.description")
+}
+
+func (e *Export) HelpText(loc i18n.Locale) string {
+	return loc.T("export.help")
+}
+
+func (e *Export) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == e.CommandName(), nil
+}
+
+// Run команда /export <формат> сразу отправляет файл, без формата предлагает выбрать его кнопками
+func (e *Export) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", e.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	format, ok := transfer.ParseFormat(message.CommandArguments())
+	if !ok {
+		msg.Text = loc.T("export.ask_format")
+		msg.ReplyMarkup = e.formatKeyboard()
+		return msg, nil
+	}
+
+	msg.Text, err = e.export(loc, message.Chat.ID, member.WorkspaceId, format)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback отправляет файл в формате выбранном кнопкой, сообщение с кнопками заменяется итогом выгрузки
+func (e *Export) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", e.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("export: empty callback data")
+	}
+
+	format, ok := transfer.ParseFormat(args[0])
+	if !ok {
+		return nil, fmt.Errorf("export: unknown format %q", args[0])
+	}
+
+	text, err := e.export(loc, chatId, member.WorkspaceId, format)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, text), err
+}
+
+func (e *Export) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (e *Export) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// export отправляет файл со ссылками пространства и возвращает текст ответа пользователю
+func (e *Export) export(loc i18n.Locale, chatId, workspaceId int64, format transfer.Format) (string, error) {
+	list, err := e.transfer.Export(workspaceId)
+	if err != nil {
+		return loc.T("common.error_list"), err
+	}
+
+	if len(list) == 0 {
+		return loc.T("common.empty_list"), nil
+	}
+
+	data, err := transfer.Encode(format, list)
+	if err != nil {
+		return loc.T("export.error"), err
+	}
+
+	doc := tgbotapi.NewDocument(chatId, tgbotapi.FileBytes{Name: format.FileName(), Bytes: data})
+	if err := e.sender.Send(doc); err != nil {
+		return loc.T("export.error"), err
+	}
+
+	return loc.Plural("export.success", len(list)), nil
+}
+
+func (e *Export) formatKeyboard() tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(transfer.Formats))
+	for _, f := range transfer.Formats {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(strings.ToUpper(string(f)), callbackData(e.CommandName(), string(f))))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
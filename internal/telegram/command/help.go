@@ -0,0 +1,127 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"strings"
+)
+
+type (
+	// Help структура для обработки команды вывода списка команд и справки по ним
+	Help struct {
+		commands []HandlerCommand
+	}
+)
+
+// NewHelpCommand справка строится по переданному списку команд, сама команда /help добавляется в конец списка
+func NewHelpCommand(commands []HandlerCommand) *Help {
+	h := &Help{}
+	h.commands = append(append(make([]HandlerCommand, 0, len(commands)+1), commands...), h)
+
+	return h
+}
+
+func (h *Help) CommandName() string {
+	return HelpCommand
+}
+
+func (h *Help) Description(loc i18n.Locale) string {
+	return loc.T("help.description")
+}
+
+func (h *Help) HelpText(loc i18n.Locale) string {
+	return loc.T("help.help")
+}
+
+func (h *Help) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == h.CommandName(), nil
+}
+
+// Run без аргументов выводит список команд, с названием команды - подробную справку: /help add_url
+func (h *Help) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	name := strings.TrimSpace(message.CommandArguments())
+	if name == "" {
+		msg.Text = h.listText(ctx, loc)
+		return msg, nil
+	}
+
+	// в группах команду могут указать вместе со слешем и именем бота: /add_url@bot
+	name = strings.TrimPrefix(name, "/")
+	name, _, _ = strings.Cut(name, "@")
+
+	handle := h.command(ctx, name)
+	if handle == nil {
+		msg.Text = loc.T("help.unknown", name)
+		return msg, nil
+	}
+
+	msg.Text = loc.T("help.command", handle.CommandName(), handle.Description(loc), handle.HelpText(loc)) + flagsText(loc, handle)
+
+	return msg, nil
+}
+
+// Commands список команд для справки и меню телеграма
+func (h *Help) Commands() []HandlerCommand {
+	return h.commands
+}
+
+func (h *Help) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (h *Help) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// listText команды администратора выводятся только администраторам
+func (h *Help) listText(ctx context.Context, loc i18n.Locale) string {
+	str := strings.Builder{}
+	str.WriteString(loc.T("help.title"))
+	for _, handle := range h.commands {
+		if !isAllowed(ctx, handle) {
+			continue
+		}
+
+		str.WriteString(loc.T("help.item", handle.CommandName(), handle.Description(loc)))
+	}
+	str.WriteString(loc.T("help.footer"))
+
+	return str.String()
+}
+
+func (h *Help) command(ctx context.Context, name string) HandlerCommand {
+	name = strings.ToLower(name)
+	for _, handle := range h.commands {
+		if handle.CommandName() == name && isAllowed(ctx, handle) {
+			return handle
+		}
+	}
+
+	return nil
+}
+
+// flagsText список именованных аргументов команды для справки, у команды без аргументов - пустая строка
+func flagsText(loc i18n.Locale, handle HandlerCommand) string {
+	command, ok := handle.(ArgumentsCommand)
+	if !ok || len(command.Flags()) == 0 {
+		return ""
+	}
+
+	codes := make([]string, 0, len(command.Flags()))
+	for _, flag := range command.Flags() {
+		codes = append(codes, "<code>"+flag+"</code>")
+	}
+
+	return loc.T("help.flags", strings.Join(codes, " "))
+}
@@ -0,0 +1,239 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/transfer"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	answerImportUpsert = "upsert"
+	answerImportDryRun = "dry_run"
+	answerImportFile   = "file" // формат и id файла через ":", см. readImportFile
+
+	importReportRows = 30 // сколько записей с ошибками выводить в отчете, телеграм ограничивает длину сообщения
+)
+
+type (
+	// MonitorTransfer этот интерфейс реализует возможность импортировать и экспортировать ссылки пространства
+	MonitorTransfer interface {
+		Import(ctx context.Context, workspaceId, userId int64, rows []transfer.Row, options model.ImportOptions) (model.ImportReport, error)
+		Export(workspaceId int64) ([]transfer.Monitor, error)
+	}
+
+	// FileDownloader этот интерфейс реализует возможность скачать файл отправленный боту
+	FileDownloader interface {
+		DownloadFile(fileId string, limit int64) ([]byte, error)
+	}
+
+	// Import структура для обработки команды импорта ссылок из файла
+	Import struct {
+		transfer MonitorTransfer
+		files    FileDownloader
+		dialog   *Dialog
+	}
+)
+
+func NewImportCommand(dialog DialogChain, transfer MonitorTransfer, files FileDownloader) *Import {
+	i := &Import{
+		transfer: transfer,
+		files:    files,
+	}
+
+	// режимы импорта задаются только аргументами команды, вопрос задается один - файл
+	i.dialog = NewDialog(ImportCommand, dialog, editorFromContext, i.submit,
+		DialogStep{Name: answerImportUpsert, Optional: true},
+		DialogStep{Name: answerImportDryRun, Optional: true},
+		DialogStep{Name: answerImportFile, Prompt: importFilePrompt, Read: readImportFile},
+	)
+
+	return i
+}
+
+func (i *Import) CommandName() string {
+	return ImportCommand
+}
+
+func (i *Import) Description(loc i18n.Locale) string {
+	return loc.T("import.description")
+}
+
+func (i *Import) HelpText(loc i18n.Locale) string {
+	return loc.T("import.help")
+}
+
+func (i *Import) Flags() []string {
+	return i.dialog.Flags()
+}
+
+func (i *Import) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return i.dialog.IsSupport(ctx, message)
+}
+
+func (i *Import) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return i.dialog.IsComplete(ctx, message)
+}
+
+// Run команда /import [upsert] [dry_run] запоминает режим и ждет файл, следующее сообщение с файлом импортируется
+func (i *Import) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", i.CommandName()))
+	defer span.End()
+
+	if !message.IsCommand() {
+		msg, err := i.dialog.Run(ctx, message)
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return msg, err
+	}
+
+	options, ok := parseImportOptions(message.CommandArguments())
+	if !ok {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("import.usage"))
+		msg.ParseMode = tgbotapi.ModeHTML
+		return msg, i.ClearData(ctx, message)
+	}
+
+	msg, err := i.dialog.Start(ctx, message.Chat.ID, commandArguments{Flags: map[string][]string{
+		answerImportUpsert: {strconv.FormatBool(options.Upsert)},
+		answerImportDryRun: {strconv.FormatBool(options.DryRun)},
+	}})
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback отмена ожидания файла кнопкой
+func (i *Import) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", i.CommandName()))
+	defer span.End()
+
+	reply, err := i.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return reply, err
+}
+
+func (i *Import) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return i.dialog.Clear(ctx, message.Chat.ID)
+}
+
+// submit скачивает и импортирует файл, если файл не удалось разобрать, диалог ждет исправленный файл
+func (i *Import) submit(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	format, fileId, _ := strings.Cut(s.Answers[answerImportFile], ":")
+
+	data, err := i.files.DownloadFile(fileId, transfer.MaxFileSize)
+	if err != nil {
+		msg.Text = loc.T("import.error_download")
+		return msg, err
+	}
+
+	rows, err := transfer.Decode(transfer.Format(format), data)
+	if err != nil {
+		return msg, i18n.NewError("import.invalid_file", loc.Error(err))
+	}
+
+	options := model.ImportOptions{
+		Upsert: s.Answers[answerImportUpsert] == strconv.FormatBool(true),
+		DryRun: s.Answers[answerImportDryRun] == strconv.FormatBool(true),
+	}
+
+	report, err := i.transfer.Import(ctx, s.Member.WorkspaceId, s.Member.UserId, rows, options)
+	if err != nil {
+		msg.Text = loc.T("import.error")
+		return msg, err
+	}
+
+	msg.Text = importReportText(loc, report)
+
+	return msg, nil
+}
+
+func importFilePrompt(ctx context.Context, _ *DialogState) (string, error) {
+	return i18n.FromContext(ctx).T("import.ask_file", transfer.MaxRows), nil
+}
+
+// readImportFile ответом на вопрос диалога служит файл, отправленный документом
+func readImportFile(message *tgbotapi.Message) (string, error) {
+	doc := message.Document
+	if doc == nil {
+		return "", i18n.NewError("import.error_no_file")
+	}
+
+	format, ok := transfer.FormatByFileName(doc.FileName)
+	if !ok {
+		return "", i18n.NewError("import.unknown_format")
+	}
+
+	if doc.FileSize > transfer.MaxFileSize {
+		return "", i18n.NewError("import.too_large", transfer.MaxFileSize/1024)
+	}
+
+	return fmt.Sprintf("%s:%s", format, doc.FileID), nil
+}
+
+// parseImportOptions разбирает аргументы /import: upsert - обновлять существующие ссылки, dry_run - только проверить файл
+func parseImportOptions(args string) (model.ImportOptions, bool) {
+	var options model.ImportOptions
+	for _, arg := range strings.Fields(strings.ToLower(args)) {
+		switch arg {
+		case "upsert":
+			options.Upsert = true
+		case "dry_run", "dry-run", "dry":
+			options.DryRun = true
+		default:
+			return options, false
+		}
+	}
+
+	return options, true
+}
+
+// importReportText итоги импорта и записи, которые не были импортированы
+func importReportText(loc i18n.Locale, report model.ImportReport) string {
+	str := strings.Builder{}
+	if report.DryRun {
+		str.WriteString(loc.T("import.report_dry_run"))
+	} else {
+		str.WriteString(loc.T("import.report_title"))
+	}
+
+	str.WriteString(loc.T("import.report_summary", report.Created, report.Updated, report.Unchanged, report.Skipped, report.Invalid))
+
+	var failed []model.ImportRow
+	for _, row := range report.Rows {
+		if row.Error != "" {
+			failed = append(failed, row)
+		}
+	}
+
+	for n, row := range failed {
+		if n == importReportRows {
+			str.WriteString(loc.T("import.report_more", len(failed)-n))
+			break
+		}
+
+		str.WriteString(loc.T("import.report_row", row.Line, html.EscapeString(row.Url), html.EscapeString(row.Error)))
+	}
+
+	if report.DryRun && report.Created+report.Updated > 0 {
+		str.WriteString(loc.T("import.report_apply", ImportCommand))
+	}
+
+	return str.String()
+}
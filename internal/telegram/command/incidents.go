@@ -0,0 +1,179 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// incidentListLimit сколько последних инцидентов выводить
+const incidentListLimit = 10
+
+const incidentTimeFormat = "02.01.2006 15:04:05"
+
+// incidentEventNames ключи описаний событий хронологии инцидента
+var incidentEventNames = map[model.IncidentEventKind]string{
+	model.IncidentEventDown:        "incidents.event_down",
+	model.IncidentEventDegraded:    "incidents.event_degraded",
+	model.IncidentEventRecovery:    "incidents.event_recovery",
+	model.IncidentEventAcknowledge: "incidents.event_acknowledge",
+}
+
+type (
+	// IncidentProvider этот интерфейс реализует возможность получать инциденты пространства
+	IncidentProvider interface {
+		IncidentList(ctx context.Context, workspaceId int64, limit int) (model.IncidentList, error)
+		IncidentById(ctx context.Context, workspaceId, id int64) (model.Incident, error)
+	}
+
+	// Incidents структура для обработки команды вывода последних инцидентов
+	Incidents struct {
+		incidentRepo IncidentProvider
+	}
+)
+
+func NewIncidentsCommand(incidentRepo IncidentProvider) *Incidents {
+	return &Incidents{
+		incidentRepo: incidentRepo,
+	}
+}
+
+func (i *Incidents) CommandName() string {
+	return IncidentsCommand
+}
+
+func (i *Incidents) Description(loc i18n.Locale) string {
+	return loc.T("incidents.description")
+}
+
+func (i *Incidents) HelpText(loc i18n.Locale) string {
+	return loc.T("incidents.help")
+}
+
+func (i *Incidents) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == i.CommandName(), nil
+}
+
+func (i *Incidents) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	list, err := i.incidentRepo.IncidentList(ctx, member.WorkspaceId, incidentListLimit)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		return msg, err
+	}
+
+	if len(list) == 0 {
+		msg.Text = loc.T("incidents.empty")
+		return msg, nil
+	}
+
+	str := strings.Builder{}
+	str.WriteString(loc.T("incidents.title"))
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(list))
+	for n, incident := range list {
+		str.WriteString(fmt.Sprintf("%d. %s\n\n", n+1, incidentText(loc, incident)))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			loc.T("incidents.button_timeline", n+1),
+			callbackData(i.CommandName(), strconv.FormatInt(incident.Id, 10)),
+		)))
+	}
+
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.Text = str.String()
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return msg, nil
+}
+
+// RunCallback выводит хронологию инцидента
+func (i *Incidents) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) != 1 {
+		return nil, fmt.Errorf("не верные данные кнопки: %s", query.Data)
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	incident, err := i.incidentRepo.IncidentById(ctx, member.WorkspaceId, id)
+	if err != nil {
+		msg.Text = loc.T("incidents.not_found")
+		return msg, err
+	}
+
+	str := strings.Builder{}
+	str.WriteString(incidentText(loc, incident))
+	str.WriteString(loc.T("incidents.timeline"))
+	for _, event := range incident.Events {
+		str.WriteString(fmt.Sprintf("<code>%s</code> %s", event.CreatedAt.Format(incidentTimeFormat), loc.T(incidentEventNames[event.Kind])))
+		if event.Text != "" {
+			str.WriteString(fmt.Sprintf(" - %s", html.EscapeString(event.Text)))
+		}
+		str.WriteString("\n")
+	}
+
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.Text = str.String()
+
+	return msg, nil
+}
+
+func (i *Incidents) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
+
+func (i *Incidents) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// incidentText краткое описание инцидента: ссылка, время начала и окончания, длительность и первая ошибка
+func incidentText(loc i18n.Locale, incident model.Incident) string {
+	str := strings.Builder{}
+	str.WriteString(fmt.Sprintf("🌐 <code>%s</code>\n", html.EscapeString(incident.Url)))
+	str.WriteString(loc.T("incidents.started", incident.StartedAt.Format(incidentTimeFormat)))
+
+	if incident.IsOpen() {
+		str.WriteString(loc.T("incidents.open"))
+	} else {
+		str.WriteString(loc.T("incidents.ended", incident.EndedAt.Format(incidentTimeFormat)))
+	}
+
+	str.WriteString(loc.T("incidents.duration", loc.Duration(time.Duration(incident.Duration)*time.Second)))
+
+	if incident.FirstError != "" {
+		str.WriteString(fmt.Sprintf("\n⚠️ <u>%s</u>", html.EscapeString(incident.FirstError)))
+	}
+
+	return str.String()
+}
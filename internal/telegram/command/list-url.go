@@ -2,16 +2,16 @@ package command
 
 import (
 	"context"
-	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
-	"strings"
+	"html"
 )
 
 type (
-	// UserUrlList этот интерфейс реализует возможность получения ссылок текущего пользователя
+	// UserUrlList этот интерфейс реализует возможность получения ссылок текущего пространства
 	UserUrlList interface {
-		UrlListByUser(userId int64) (model.PingList, error)
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
 	}
 
 	// ListUrl структура для обработки команды добавления новой ссылки
@@ -30,8 +30,12 @@ func (l *ListUrl) CommandName() string {
 	return ListUrlCommand
 }
 
-func (l *ListUrl) HelpText() string {
-	return "help text"
+func (l *ListUrl) Description(loc i18n.Locale) string {
+	return loc.T("list_url.description")
+}
+
+func (l *ListUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("list_url.help")
 }
 
 func (l *ListUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -44,29 +48,76 @@ func (l *ListUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (boo
 }
 
 func (l *ListUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
-	userId := message.Chat.ID
-	msg := tgbotapi.NewMessage(userId, "")
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
 
-	list, err := l.urlRepo.UrlListByUser(userId)
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	tag, err := commandTag(message)
+	if err != nil {
+		msg.Text = loc.Error(err)
+		return msg, nil
+	}
 
+	pages, err := l.pager(loc, member.WorkspaceId, tag)
 	if err != nil {
-		msg.Text = "Произошла ошибка при получении списка, повторите позже"
+		msg.Text = loc.T("common.error_list")
 		return msg, err
 	}
 
-	if len(list) == 0 {
-		msg.Text = "У вас еще нет записей"
+	if pages.Len() == 0 {
+		msg.Text = emptyListText(loc, tag)
 		return msg, nil
 	}
 
-	str := strings.Builder{}
-	for _, url := range list {
-		str.WriteString(fmt.Sprintf("🌐 <code>%s</code> \n⏳ Время ожидания - <code>%s</code> \n🕤 Время периодичности - <code>%s</code>\n\n", url.Url, url.ConnectionTime, url.PingTime))
+	return pages.Message(loc, message.Chat.ID), nil
+}
+
+// RunCallback перелистывает страницы списка, фильтр по тегу передается в данных кнопки
+func (l *ListUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	page, args, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := pagerTag(args)
+	pages, err := l.pager(loc, member.WorkspaceId, tag)
+	if err != nil {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	if pages.Len() == 0 {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, emptyListText(loc, tag)), nil
+	}
+
+	return pages.Edit(loc, query, page), nil
+}
+
+// pager ссылки пространства с тегом постранично, пустой тег - все ссылки
+func (l *ListUrl) pager(loc i18n.Locale, workspaceId int64, tag string) (*pager, error) {
+	list, err := l.urlRepo.UrlListByWorkspace(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(l.CommandName(), tagArgs(tag)...)
+	for _, url := range list.WithTag(tag) {
+		pages.Add(loc.T("list_url.item", html.EscapeString(url.Url), url.Id, url.ConnectionTime, url.PingTime, tagsText(loc, url.Tags)))
 	}
-	msg.ParseMode = tgbotapi.ModeHTML
-	msg.Text = str.String()
 
-	return msg, nil
+	return pages, nil
 }
 
 func (l *ListUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"context"
+	"errors"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+)
+
+var (
+	errNoWorkspace = errors.New("пользователь не состоит ни в одном пространстве")
+	errReadOnly    = errors.New("роль пользователя не позволяет изменять ссылки")
+)
+
+type (
+	// MemberProvider этот интерфейс реализует возможность получить текущее пространство пользователя
+	MemberProvider interface {
+		CurrentMember(ctx context.Context, userId int64) (model.Member, error)
+	}
+
+	memberContextKey struct{}
+)
+
+// withMember сохраняет в контексте текущее пространство пользователя, от имени которого выполняется команда
+func withMember(ctx context.Context, member model.Member) context.Context {
+	return context.WithValue(ctx, memberContextKey{}, member)
+}
+
+// memberFromContext текущее пространство пользователя, см. withMember
+func memberFromContext(ctx context.Context) (model.Member, error) {
+	member, ok := ctx.Value(memberContextKey{}).(model.Member)
+	if !ok {
+		return member, errNoWorkspace
+	}
+
+	return member, nil
+}
+
+// editorFromContext текущее пространство пользователя, если роль позволяет изменять ссылки
+func editorFromContext(ctx context.Context) (model.Member, error) {
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return member, err
+	}
+
+	if !member.Role.CanEdit() {
+		return member, errReadOnly
+	}
+
+	return member, nil
+}
+
+// anyoneFromContext диалог доступен пользователю даже без рабочего пространства, например изменение его настроек
+func anyoneFromContext(ctx context.Context) (model.Member, error) {
+	member, _ := memberFromContext(ctx)
+
+	return member, nil
+}
+
+// memberErrorText ответ пользователю, если команду нельзя выполнить в текущем пространстве
+func memberErrorText(ctx context.Context, err error) string {
+	loc := i18n.FromContext(ctx)
+	if errors.Is(err, errReadOnly) {
+		return loc.T("member.read_only")
+	}
+
+	return loc.T("member.no_workspace", RegistrationCommand)
+}
+
+// senderId пользователь отправивший сообщение, в групповых чатах отличается от чата
+func senderId(message *tgbotapi.Message) int64 {
+	if message.From != nil {
+		return message.From.ID
+	}
+
+	return message.Chat.ID
+}
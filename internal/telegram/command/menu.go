@@ -0,0 +1,41 @@
+package command
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+)
+
+// RegisterMenu заменяет меню команд бота в телеграме на подключенные команды с описаниями на языке пользователя,
+// меню без кода языка видят пользователи, язык телеграма которых не поддерживается. Команд администратора в меню нет
+func (c *Command) RegisterMenu() error {
+	const op = "telegram.command.RegisterMenu"
+
+	if err := c.bot.Send(c.menu(i18n.Fallback, "")); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, code := range i18n.Codes() {
+		if err := c.bot.Send(c.menu(i18n.Parse(code), code)); err != nil {
+			return fmt.Errorf("%s: %s: %w", op, code, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) menu(loc i18n.Locale, languageCode string) tgbotapi.SetMyCommandsConfig {
+	commands := make([]tgbotapi.BotCommand, 0, len(c.commands))
+	for _, handle := range c.commands {
+		if admin, ok := handle.(AdminOnly); ok && admin.AdminOnly() {
+			continue
+		}
+
+		commands = append(commands, tgbotapi.BotCommand{
+			Command:     handle.CommandName(),
+			Description: handle.Description(loc),
+		})
+	}
+
+	return tgbotapi.NewSetMyCommandsWithScopeAndLanguage(tgbotapi.NewBotCommandScopeDefault(), languageCode, commands...)
+}
@@ -0,0 +1,33 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"time"
+)
+
+// Audit записывает в лог кто, где и какую команду или кнопку выполнил, текст сообщения не записывается,
+// потому что в нем могут быть личные данные пользователя
+func Audit(log *slog.Logger) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+			start := time.Now()
+			msg, err := next(ctx, message)
+
+			kind := "answer"
+			if IsCallback(ctx) {
+				kind = "callback"
+			} else if message.IsCommand() {
+				kind = "command"
+			}
+
+			log.Info(fmt.Sprintf("audit: %s %s, user_id: %d, chat_id: %d, duration: %s, error: %t",
+				kind, CommandName(ctx), senderId(message), message.Chat.ID, time.Since(start), err != nil,
+			))
+
+			return msg, err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"cmp"
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	commandCounter struct {
+		runs   int64
+		errors int64
+		total  time.Duration
+		max    time.Duration
+	}
+
+	// CommandMetrics считает выполнения команд, ошибки и время выполнения с момента запуска
+	CommandMetrics struct {
+		mu       sync.Mutex
+		counters map[string]*commandCounter
+	}
+)
+
+func NewCommandMetrics() *CommandMetrics {
+	return &CommandMetrics{
+		counters: make(map[string]*commandCounter),
+	}
+}
+
+// Middleware подключается к командам через Command.Use
+func (m *CommandMetrics) Middleware() Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+			start := time.Now()
+			msg, err := next(ctx, message)
+			m.add(CommandName(ctx), time.Since(start), err != nil)
+
+			return msg, err
+		}
+	}
+}
+
+// Stats статистика по командам, сначала самые частые
+func (m *CommandMetrics) Stats() []model.CommandStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]model.CommandStats, 0, len(m.counters))
+	for name, counter := range m.counters {
+		list = append(list, model.CommandStats{
+			Command: name,
+			Runs:    counter.runs,
+			Errors:  counter.errors,
+			Average: counter.total.Seconds() / float64(counter.runs),
+			Max:     counter.max.Seconds(),
+		})
+	}
+
+	slices.SortFunc(list, func(a, b model.CommandStats) int {
+		if c := cmp.Compare(b.Runs, a.Runs); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Command, b.Command)
+	})
+
+	return list
+}
+
+func (m *CommandMetrics) add(name string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[name]
+	if !ok {
+		counter = &commandCounter{}
+		m.counters[name] = counter
+	}
+
+	counter.runs++
+	counter.total += duration
+	counter.max = max(counter.max, duration)
+	if failed {
+		counter.errors++
+	}
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepSize при каком кол-ве пользователей удалять тех, кто давно ничего не отправлял
+const rateLimitSweepSize = 10000
+
+type (
+	// rateBucket запас команд пользователя, пополняется равномерно до limit в минуту
+	rateBucket struct {
+		tokens float64
+		last   time.Time
+	}
+
+	rateLimiter struct {
+		mu      sync.Mutex
+		limit   float64
+		buckets map[int64]*rateBucket
+	}
+)
+
+// RateLimit ограничивает кол-во сообщений от одного пользователя: не больше limit в минуту с запасом limit подряд.
+// Сообщение сверх ограничения не выполняется, пользователь получает ответ через сколько можно повторить.
+// limit <= 0 - без ограничения
+func RateLimit(limit int) Middleware {
+	if limit <= 0 {
+		return func(next RunFunc) RunFunc {
+			return next
+		}
+	}
+
+	limiter := &rateLimiter{
+		limit:   float64(limit),
+		buckets: make(map[int64]*rateBucket),
+	}
+
+	return func(next RunFunc) RunFunc {
+		return func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+			if wait := limiter.take(senderId(message), time.Now()); wait > 0 {
+				loc := i18n.FromContext(ctx)
+				return tgbotapi.NewMessage(message.Chat.ID, loc.T("common.rate_limited", loc.Duration(max(wait, time.Second)))), nil
+			}
+
+			return next(ctx, message)
+		}
+	}
+}
+
+// take забирает одну команду из запаса пользователя, если запас пуст - возвращает сколько ждать следующей
+func (l *rateLimiter) take(userId int64, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	perSecond := l.limit / time.Minute.Seconds()
+
+	bucket, ok := l.buckets[userId]
+	if !ok {
+		if len(l.buckets) >= rateLimitSweepSize {
+			l.sweep(now, perSecond)
+		}
+
+		bucket = &rateBucket{tokens: l.limit, last: now}
+		l.buckets[userId] = bucket
+	}
+
+	bucket.tokens = min(l.limit, bucket.tokens+now.Sub(bucket.last).Seconds()*perSecond)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+	}
+
+	bucket.tokens--
+
+	return 0
+}
+
+// sweep удаляет пользователей с полным запасом, для них новый запас ничем не отличается от сохраненного
+func (l *rateLimiter) sweep(now time.Time, perSecond float64) {
+	for userId, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*perSecond >= l.limit {
+			delete(l.buckets, userId)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"runtime/debug"
+)
+
+// Recover перехватывает панику в команде: пользователь получает извинение, а паника со стеком
+// возвращается ошибкой и попадает в лог. Без него паника в одной команде останавливает бота целиком
+func Recover() Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(ctx context.Context, message *tgbotapi.Message) (msg tgbotapi.MessageConfig, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					msg = tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("common.panic"))
+					err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+				}
+			}()
+
+			return next(ctx, message)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type (
+	// RunFunc выполнение команды, см. HandlerCommand.Run
+	RunFunc func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error)
+
+	// Middleware оборачивает выполнение команды так же, как middleware chi оборачивают http.Handler:
+	// может выполнить действие до и после команды, изменить ответ или не вызывать команду вовсе
+	Middleware func(next RunFunc) RunFunc
+
+	commandNameContextKey struct{}
+	callbackContextKey    struct{}
+)
+
+// Use подключает middleware ко всем командам и нажатиям их кнопок, первая подключенная выполняется первой.
+// Вызывается до ListenCommandAndMessage
+func (c *Command) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// chain выполнение команды обернутое всеми подключенными middleware
+func (c *Command) chain(handle HandlerCommand) RunFunc {
+	return c.wrap(handle.CommandName(), handle.Run)
+}
+
+// callbackChain нажатие кнопки обернутое теми же middleware, что и команды. Middleware получают сообщение с кнопкой
+// от имени нажавшего пользователя, ответ кнопки передается мимо них. Если middleware ответили сами
+// (ограничение частоты, паника), отправляется их ответ
+func (c *Command) callbackChain(handle HandlerCallback) func(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	return func(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+		var reply tgbotapi.Chattable
+		run := c.wrap(handle.CommandName(), func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+			var err error
+			reply, err = handle.RunCallback(ctx, query)
+
+			return tgbotapi.MessageConfig{}, err
+		})
+
+		message := &tgbotapi.Message{MessageID: query.Message.MessageID, From: query.From, Chat: query.Message.Chat}
+		msg, err := run(context.WithValue(ctx, callbackContextKey{}, true), message)
+		if msg.Text != "" {
+			return msg, err
+		}
+
+		return reply, err
+	}
+}
+
+func (c *Command) wrap(name string, run RunFunc) RunFunc {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		run = c.middlewares[i](run)
+	}
+
+	return func(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+		return run(context.WithValue(ctx, commandNameContextKey{}, name), message)
+	}
+}
+
+// CommandName название выполняемой команды для middleware
+func CommandName(ctx context.Context) string {
+	name, _ := ctx.Value(commandNameContextKey{}).(string)
+
+	return name
+}
+
+// IsCallback middleware выполняется для нажатия кнопки, а не для сообщения
+func IsCallback(ctx context.Context) bool {
+	is, _ := ctx.Value(callbackContextKey{}).(bool)
+
+	return is
+}
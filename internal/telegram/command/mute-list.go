@@ -0,0 +1,140 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+)
+
+type (
+	// MutedUrlList этот интерфейс реализует возможность получить ссылки пространства с отключенными уведомлениями
+	MutedUrlList interface {
+		MutedUrlList(workspaceId int64) (model.PingList, error)
+	}
+
+	// UserProvider этот интерфейс реализует возможность получить данные пользователя
+	UserProvider interface {
+		UserById(ctx context.Context, userId int64) (model.User, error)
+	}
+
+	// MuteList структура для обработки команды вывода активных отключений уведомлений
+	MuteList struct {
+		urlRepo  MutedUrlList
+		userRepo UserProvider
+	}
+)
+
+func NewMuteListCommand(urlRepo MutedUrlList, userRepo UserProvider) *MuteList {
+	return &MuteList{
+		urlRepo:  urlRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (m *MuteList) CommandName() string {
+	return MuteListCommand
+}
+
+func (m *MuteList) Description(loc i18n.Locale) string {
+	return loc.T("mute_list.description")
+}
+
+func (m *MuteList) HelpText(loc i18n.Locale) string {
+	return loc.T("mute_list.help")
+}
+
+func (m *MuteList) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == m.CommandName(), nil
+}
+
+func (m *MuteList) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+	errorMessage := loc.T("common.error_list")
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	pages, err := m.pager(ctx, loc, member)
+	if err != nil {
+		msg.Text = errorMessage
+		return msg, err
+	}
+
+	if pages.Len() == 0 {
+		msg.Text = loc.T("mute_list.empty")
+		return msg, nil
+	}
+
+	return pages.Message(loc, message.Chat.ID), nil
+}
+
+// RunCallback перелистывает страницы списка отключенных уведомлений
+func (m *MuteList) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	page, _, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := m.pager(ctx, loc, member)
+	if err != nil {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	if pages.Len() == 0 {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("mute_list.empty")), nil
+	}
+
+	return pages.Edit(loc, query, page), nil
+}
+
+// pager отключенные уведомления постранично: отключение всех уведомлений первым элементом, затем ссылки
+func (m *MuteList) pager(ctx context.Context, loc i18n.Locale, member model.Member) (*pager, error) {
+	// отключение всех уведомлений личное, а отключения ссылок общие для пространства
+	user, err := m.userRepo.UserById(ctx, member.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := m.urlRepo.MutedUrlList(member.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(m.CommandName())
+	if user.Mute {
+		pages.Add(loc.T("mute_list.all", muteUntilText(loc, user.MuteUntil)))
+	}
+
+	for _, url := range list {
+		pages.Add(loc.T("mute_list.url", html.EscapeString(url.Url), muteUntilText(loc, url.MuteUntil)))
+	}
+
+	return pages, nil
+}
+
+func (m *MuteList) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
+
+func (m *MuteList) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"time"
+)
+
+const answerMuteUntil = "until" // время на которое отключаются уведомления
+
+type (
+	// UrlMuter этот интерфейс реализует возможность отключить уведомления по ссылке
+	UrlMuter interface {
+		MuteUrl(workspaceId int64, url string, until *time.Time, userId, chatId int64) error
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+	}
+
+	// MuteUrl структура для обработки команды отключения уведомлений по ссылке
+	MuteUrl struct {
+		urlRepo UrlMuter
+		dialog  *Dialog
+	}
+)
+
+func NewMuteUrlCommand(dialog DialogChain, urlRepo UrlMuter) *MuteUrl {
+	m := &MuteUrl{
+		urlRepo: urlRepo,
+	}
+
+	m.dialog = NewDialog(MuteUrlCommand, dialog, editorFromContext, m.mute,
+		urlDialogStep("mute_url.ask_url", m.list),
+		DialogStep{Name: answerMuteUntil, Prompt: dialogPrompt("mute_url.ask_time"), Validate: validateMuteUntil},
+	)
+
+	return m
+}
+
+func (m *MuteUrl) CommandName() string {
+	return MuteUrlCommand
+}
+
+func (m *MuteUrl) Description(loc i18n.Locale) string {
+	return loc.T("mute_url.description")
+}
+
+func (m *MuteUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("mute_url.help")
+}
+
+func (m *MuteUrl) Flags() []string {
+	return m.dialog.Flags()
+}
+
+func (m *MuteUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return m.dialog.IsSupport(ctx, message)
+}
+
+func (m *MuteUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return m.dialog.IsComplete(ctx, message)
+}
+
+func (m *MuteUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", m.CommandName()))
+	defer span.End()
+
+	msg, err := m.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback выбор ссылки кнопкой, после выбора диалог переходит к вводу времени отключения
+func (m *MuteUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", m.CommandName()))
+	defer span.End()
+
+	msg, err := m.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+func (m *MuteUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", m.CommandName()))
+	defer span.End()
+
+	if err := m.dialog.Clear(ctx, message.Chat.ID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *MuteUrl) list(s *DialogState) (model.PingList, error) {
+	return m.urlRepo.UrlListByWorkspace(s.Member.WorkspaceId)
+}
+
+func (m *MuteUrl) mute(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+
+	// время уже проверено на шаге диалога, относительное время считается от момента отключения
+	until, _ := parseMuteUntil(s.Answers[answerMuteUntil])
+	if err := m.urlRepo.MuteUrl(s.Member.WorkspaceId, s.Answers[answerUrl], until, s.Member.UserId, s.ChatId); err != nil {
+		msg.Text = loc.T("mute.error")
+		return msg, err
+	}
+
+	msg.Text = loc.T("mute_url.success", html.EscapeString(s.Answers[answerUrl]), muteUntilText(loc, until))
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
+}
+
+func validateMuteUntil(ctx context.Context, s *DialogState, value string) error {
+	if _, err := parseMuteUntil(value); err != nil {
+		return i18n.NewError("mute_url.invalid_time")
+	}
+
+	return nil
+}
@@ -2,13 +2,20 @@ package command
 
 import (
 	"context"
+	"errors"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"strings"
+	"time"
 )
 
+// muteTimeFormat формат вывода времени окончания отключения уведомлений
+const muteTimeFormat = "02.01.2006 15:04"
+
 type (
 	// UserMuteNotification этот интерфейс реализует возможность отключить уведомления для пользователя
 	UserMuteNotification interface {
-		Mute(ctx context.Context, userId int64) error
+		Mute(ctx context.Context, userId int64, until *time.Time) error
 	}
 
 	// MuteAll структура для обработки команды отключения нотификаций
@@ -27,8 +34,12 @@ func (m *MuteAll) CommandName() string {
 	return MuteAllCommand
 }
 
-func (m *MuteAll) HelpText() string {
-	return "help text"
+func (m *MuteAll) Description(loc i18n.Locale) string {
+	return loc.T("mute_all.description")
+}
+
+func (m *MuteAll) HelpText(loc i18n.Locale) string {
+	return loc.T("mute_all.help")
 }
 
 func (m *MuteAll) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -43,15 +54,22 @@ func (m *MuteAll) IsSupport(ctx context.Context, message *tgbotapi.Message) (boo
 func (m *MuteAll) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
 	userId := message.Chat.ID
 	msg := tgbotapi.NewMessage(userId, "")
+	loc := i18n.FromContext(ctx)
 
-	err := m.userMute.Mute(ctx, userId)
+	until, err := parseMuteUntil(message.CommandArguments())
+	if err != nil {
+		msg.Text = loc.T("mute_all.invalid_time")
+		return msg, err
+	}
+
+	err = m.userMute.Mute(ctx, userId, until)
 
 	if err != nil {
-		msg.Text = "Произошла ошибка при отключении уведомлений, повторите позже"
+		msg.Text = loc.T("mute.error")
 		return msg, err
 	}
 
-	msg.Text = "Уведомления отключены"
+	msg.Text = loc.T("mute_all.success", muteUntilText(loc, until))
 
 	return msg, nil
 }
@@ -63,3 +81,34 @@ func (m *MuteAll) ClearData(ctx context.Context, message *tgbotapi.Message) erro
 func (m *MuteAll) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
 	return true, nil
 }
+
+// parseMuteUntil разбирает длительность отключения уведомлений (30m|2h|1h30m),
+// пустая строка или 0 означает бессрочное отключение и возвращает nil
+func parseMuteUntil(text string) (*time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || text == "0" {
+		return nil, nil
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if duration < 0 {
+		return nil, errors.New("время отключения не может быть отрицательным")
+	}
+
+	until := time.Now().Add(duration)
+
+	return &until, nil
+}
+
+// muteUntilText до какого времени отключены уведомления и сколько осталось
+func muteUntilText(loc i18n.Locale, until *time.Time) string {
+	if until == nil {
+		return loc.T("mute.forever")
+	}
+
+	return loc.T("mute.until", until.Format(muteTimeFormat), loc.Duration(time.Until(*until)))
+}
@@ -0,0 +1,251 @@
+package command
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// messageLimit ограничение длины сообщения телеграма, длина считается в utf-16 символах
+const messageLimit = 4096
+
+type (
+	// pager разбивает длинный список на страницы, которые помещаются в одно сообщение.
+	// Состояние не хранится: кнопки содержат номер страницы и аргументы команды "<команда>:page:<страница>[:<аргумент>...]",
+	// по ним команда заново получает список и выводит нужную страницу
+	pager struct {
+		command string
+		header  string   // выводится в начале каждой страницы
+		items   []string // элементы списка, элемент не переносится на следующую страницу частично
+		args    []string // аргументы команды, например тег фильтра
+	}
+)
+
+func newPager(command string, args ...string) *pager {
+	return &pager{
+		command: command,
+		args:    args,
+	}
+}
+
+// Header задает текст, который выводится в начале каждой страницы
+func (p *pager) Header(text string) {
+	p.header = text
+}
+
+// Add добавляет элемент списка
+func (p *pager) Add(item string) {
+	p.items = append(p.items, item)
+}
+
+// Len кол-во элементов списка
+func (p *pager) Len() int {
+	return len(p.items)
+}
+
+// Message первая страница списка новым сообщением
+func (p *pager) Message(loc i18n.Locale, chatId int64) tgbotapi.MessageConfig {
+	pages := p.pages()
+
+	msg := tgbotapi.NewMessage(chatId, pages[0])
+	msg.ParseMode = tgbotapi.ModeHTML
+	if len(pages) > 1 {
+		msg.ReplyMarkup = p.keyboard(loc, 0, len(pages))
+	}
+
+	return msg
+}
+
+// Edit заменяет сообщение с кнопками на указанную страницу, page начинается с 0
+func (p *pager) Edit(loc i18n.Locale, query *tgbotapi.CallbackQuery, page int) tgbotapi.EditMessageTextConfig {
+	pages := p.pages()
+	page = max(0, min(page, len(pages)-1))
+
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, pages[page])
+	edit.ParseMode = tgbotapi.ModeHTML
+	if len(pages) > 1 {
+		keyboard := p.keyboard(loc, page, len(pages))
+		edit.ReplyMarkup = &keyboard
+	}
+
+	return edit
+}
+
+// pages тексты страниц, элементы добавляются на страницу пока она помещается в messageLimit.
+// Считается длина вместе с html разметкой, поэтому реальное сообщение получается короче
+func (p *pager) pages() []string {
+	var (
+		pages []string
+		page  strings.Builder
+		size  int
+	)
+
+	headerSize := textSize(p.header)
+	for _, item := range p.items {
+		// элемент длиннее сообщения не поместится ни на одну страницу, поэтому обрезается
+		item = truncateHTML(item, messageLimit-headerSize)
+		itemSize := textSize(item)
+		if size > 0 && headerSize+size+itemSize > messageLimit {
+			pages = append(pages, p.header+page.String())
+			page.Reset()
+			size = 0
+		}
+
+		page.WriteString(item)
+		size += itemSize
+	}
+
+	if size > 0 || len(pages) == 0 {
+		pages = append(pages, p.header+page.String())
+	}
+
+	return pages
+}
+
+// keyboard кнопки "◀ 2/5 ▶", кнопка с номером страницы обновляет текущую страницу
+func (p *pager) keyboard(loc i18n.Locale, page, pages int) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, 3)
+	if page > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("pager.prev"), p.data(page-1)))
+	}
+
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("pager.page", page+1, pages), p.data(page)))
+
+	if page < pages-1 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("pager.next"), p.data(page+1)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+func (p *pager) data(page int) string {
+	return callbackData(p.command, append([]string{urlPickerActionPage, strconv.Itoa(page)}, p.args...)...)
+}
+
+// parsePagerData разбирает данные кнопки страницы на номер страницы и аргументы команды
+func parsePagerData(data string) (int, []string, error) {
+	_, args := parseCallbackData(data)
+	if len(args) < 2 || args[0] != urlPickerActionPage {
+		return 0, nil, fmt.Errorf("не верные данные кнопки: %s", data)
+	}
+
+	page, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("не верные данные кнопки: %s", data)
+	}
+
+	return page, args[2:], nil
+}
+
+// pagerTag тег из аргументов кнопки страницы, тег может содержать разделитель данных кнопки
+func pagerTag(args []string) string {
+	return strings.Join(args, callbackSeparator)
+}
+
+// truncateHTML обрезает html текст до limit utf-16 символов вместе с разметкой, добавляя "…".
+// Текст не обрезается внутри тега или html сущности, незакрытые теги закрываются
+func truncateHTML(text string, limit int) string {
+	const ellipsis = "…"
+
+	if textSize(text) <= limit {
+		return text
+	}
+
+	// не помещается даже многоточие, например заголовок страницы занял все сообщение
+	if limit < textSize(ellipsis) {
+		return ""
+	}
+
+	var (
+		str     strings.Builder
+		open    []string // имена открытых тегов
+		size    int
+		closing int // длина закрывающих тегов для открытых
+	)
+
+	for i := 0; i < len(text); {
+		chunk := text[i:]
+		_, n := utf8.DecodeRuneInString(chunk)
+		chunk = chunk[:n]
+
+		switch text[i] {
+		case '<':
+			if end := strings.IndexByte(text[i:], '>'); end > 0 {
+				chunk = text[i : i+end+1]
+			}
+		case '&':
+			if end := strings.IndexByte(text[i:], ';'); end > 0 && end <= 10 {
+				chunk = text[i : i+end+1]
+			}
+		}
+
+		name, isTag := tagName(chunk)
+		switch {
+		case isTag && strings.HasPrefix(chunk, "</"):
+			// место под закрывающий тег уже учтено
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+				closing -= len("</" + name + ">")
+			}
+		case isTag:
+			tagSize := textSize(chunk) + len("</"+name+">")
+			if size+tagSize+closing+textSize(ellipsis) > limit {
+				return closeTags(str.String()+ellipsis, open)
+			}
+
+			open = append(open, name)
+			closing += len("</" + name + ">")
+		default:
+			if size+textSize(chunk)+closing+textSize(ellipsis) > limit {
+				return closeTags(str.String()+ellipsis, open)
+			}
+		}
+
+		str.WriteString(chunk)
+		size += textSize(chunk)
+		i += len(chunk)
+	}
+
+	return closeTags(str.String(), open)
+}
+
+// tagName имя html тега, false если текст не является тегом
+func tagName(chunk string) (string, bool) {
+	if len(chunk) < 3 || chunk[0] != '<' || chunk[len(chunk)-1] != '>' {
+		return "", false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(chunk[1:len(chunk)-1], "/"))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	return fields[0], true
+}
+
+// closeTags закрывает открытые теги в обратном порядке
+func closeTags(text string, open []string) string {
+	for i := len(open) - 1; i >= 0; i-- {
+		text += "</" + open[i] + ">"
+	}
+
+	return text
+}
+
+// textSize длина текста так, как ее считает телеграм
+func textSize(text string) int {
+	var size int
+	for _, r := range text {
+		// символы вне базовой плоскости (эмодзи) занимают два utf-16 символа
+		if r > 0xffff {
+			size += 2
+		} else {
+			size++
+		}
+	}
+
+	return size
+}
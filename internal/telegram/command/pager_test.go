@@ -0,0 +1,115 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{name: "помещается целиком", text: "<b>abc</b>", limit: 10, want: "<b>abc</b>"},
+		{name: "обычный текст", text: "abcdef", limit: 4, want: "abc…"},
+		{name: "вложенные теги закрываются", text: "<b><i>abcdef</i></b>", limit: 17, want: "<b><i>ab…</i></b>"},
+		{name: "тег не помещается вместе с закрывающим", text: "ab<b>cd</b>", limit: 5, want: "ab…"},
+		{name: "закрытый тег не закрывается повторно", text: "<b>ab</b>cdef", limit: 10, want: "<b>ab</b>…"},
+		{name: "ссылка с атрибутами", text: `<a href="https://x.com">link text</a>`, limit: 31, want: `<a href="https://x.com">li…</a>`},
+		{name: "сущность на границе обрезки", text: "a &amp; b", limit: 6, want: "a …"},
+		{name: "сущность помещается", text: "a &amp; b", limit: 8, want: "a &amp;…"},
+		{name: "эмодзи занимает два символа", text: "😀😀😀", limit: 5, want: "😀😀…"},
+		{name: "эмодзи на границе обрезки", text: "a😀b", limit: 3, want: "a…"},
+		{name: "не помещается многоточие", text: "<b>abc</b>", limit: 0, want: ""},
+		{name: "эмодзи внутри тега", text: "<code>😀😀😀</code>", limit: 18, want: "<code>😀😀…</code>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateHTML(tt.text, tt.limit)
+			if got != tt.want {
+				t.Fatalf("текст %q, ожидался %q", got, tt.want)
+			}
+
+			if size := textSize(got); size > tt.limit {
+				t.Fatalf("длина %d больше ограничения %d", size, tt.limit)
+			}
+		})
+	}
+}
+
+func TestPages(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		items  []string
+		pages  int
+		suffix string // окончание первой страницы
+	}{
+		{
+			name:   "пустой список",
+			header: "header\n",
+			pages:  1,
+			suffix: "header\n",
+		},
+		{
+			name:   "элемент длиннее сообщения с вложенными тегами",
+			header: "header\n",
+			items:  []string{"<b><i>" + strings.Repeat("x", messageLimit) + "</i></b>"},
+			pages:  1,
+			suffix: "x…</i></b>",
+		},
+		{
+			name:   "эмодзи считаются за два символа",
+			header: "",
+			items:  []string{strings.Repeat("😀", 1000), strings.Repeat("😀", 1000), strings.Repeat("😀", 1000)},
+			pages:  2,
+			suffix: strings.Repeat("😀", 1000),
+		},
+		{
+			name:   "заголовок почти на все сообщение",
+			header: strings.Repeat("h", messageLimit-10),
+			items:  []string{"<b>" + strings.Repeat("a", 20) + "</b>", "<b>" + strings.Repeat("b", 20) + "</b>"},
+			pages:  2,
+			suffix: "<b>aa…</b>",
+		},
+		{
+			name:   "заголовок на все сообщение",
+			header: strings.Repeat("h", messageLimit),
+			items:  []string{"item"},
+			pages:  1,
+			suffix: "h",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPager(ListUrlCommand)
+			p.Header(tt.header)
+			for _, item := range tt.items {
+				p.Add(item)
+			}
+
+			pages := p.pages()
+			if len(pages) != tt.pages {
+				t.Fatalf("страниц %d, ожидалось %d", len(pages), tt.pages)
+			}
+
+			for i, page := range pages {
+				if size := textSize(page); size > messageLimit {
+					t.Fatalf("длина страницы %d - %d, больше ограничения %d", i+1, size, messageLimit)
+				}
+
+				if !strings.HasPrefix(page, tt.header) {
+					t.Fatalf("страница %d без заголовка", i+1)
+				}
+			}
+
+			if !strings.HasSuffix(pages[0], tt.suffix) {
+				t.Fatalf("первая страница заканчивается на %q, ожидалось %q", pages[0][max(0, len(pages[0])-40):], tt.suffix)
+			}
+		})
+	}
+}
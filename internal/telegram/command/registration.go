@@ -2,57 +2,124 @@ package command
 
 import (
 	"context"
+	"errors"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
 	"github.com/ivankoTut/ping-url/internal/storage"
+	"html"
 )
 
 type (
 	RegistrationUser interface {
 		UserSave(ctx context.Context, userId int64, login string) error
 		UserExist(ctx context.Context, userId int64) (bool, error)
+		SaveTimezone(ctx context.Context, userId int64, timezone string) error
+		SaveLanguage(ctx context.Context, userId int64, language string) error
 	}
+
+	// InviteAcceptor этот интерфейс реализует возможность вступить в пространство по приглашению
+	InviteAcceptor interface {
+		AcceptInvite(ctx context.Context, userId int64, token string) (model.Workspace, error)
+	}
+
+	// AccessGate этот интерфейс реализует возможность проверить может ли новый пользователь зарегистрироваться по коду приглашения
+	AccessGate interface {
+		Admit(ctx context.Context, userId int64, code string) (bool, error)
+	}
+
 	Registration struct {
-		userRepo RegistrationUser
+		userRepo   RegistrationUser
+		inviteRepo InviteAcceptor
+		access     AccessGate
+		dialog     *Dialog
 	}
 )
 
-func NewRegistrationCommand(userRepo RegistrationUser) *Registration {
-	return &Registration{
-		userRepo: userRepo,
+func NewRegistrationCommand(dialog DialogChain, userRepo RegistrationUser, inviteRepo InviteAcceptor, access AccessGate) *Registration {
+	r := &Registration{
+		userRepo:   userRepo,
+		inviteRepo: inviteRepo,
+		access:     access,
 	}
+
+	// после регистрации спрашиваем часовой пояс, он нужен для тихих часов
+	r.dialog = NewDialog(RegistrationCommand, dialog, anyoneFromContext, r.saveTimezone,
+		DialogStep{
+			Name:   "timezone",
+			Prompt: dialogPrompt("registration.ask_timezone"),
+			Validate: func(ctx context.Context, s *DialogState, value string) error {
+				if _, err := parseTimezone(value); err != nil {
+					return i18n.NewError("settings.invalid_timezone")
+				}
+
+				return nil
+			},
+		},
+	)
+
+	return r
 }
 
 func (r *Registration) CommandName() string {
 	return RegistrationCommand
 }
 
-func (r *Registration) HelpText() string {
-	return "help text"
+func (r *Registration) Description(loc i18n.Locale) string {
+	return loc.T("start.description")
 }
 
-func (r *Registration) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-	if message.IsCommand() != true {
-		return false, nil
-	}
+func (r *Registration) HelpText(loc i18n.Locale) string {
+	return loc.T("start.help")
+}
 
-	return message.Command() == r.CommandName(), nil
+func (r *Registration) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return r.dialog.IsSupport(ctx, message)
 }
 
 func (r *Registration) ClearData(ctx context.Context, message *tgbotapi.Message) error {
-	return nil
+	return r.dialog.Clear(ctx, message.Chat.ID)
 }
 
 func (r *Registration) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	if !message.IsCommand() {
+		return r.dialog.Run(ctx, message)
+	}
+
 	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	// ссылка-приглашение t.me/<bot>?start=<token> приходит как /start <token>,
+	// token - код приглашения в бота или приглашение в пространство
+	token := message.CommandArguments()
 
 	ok, err := r.userRepo.UserExist(ctx, message.Chat.ID)
+	if ok && token != "" {
+		msg.Text, err = r.acceptInvite(ctx, message.Chat.ID, token)
+		msg.ParseMode = tgbotapi.ModeHTML
+		return msg, err
+	}
+
 	if ok {
-		msg.Text = "Вы уже зарегестрированы"
+		msg.Text = loc.T("registration.exists")
 		return msg, storage.ErrUserExists
 	}
 
 	if err != nil {
-		msg.Text = "Произошла ошибка, повторите позже"
+		msg.Text = loc.T("common.error")
+		return msg, err
+	}
+
+	// true - токен оказался кодом приглашения в бота, иначе это может быть приглашение в пространство
+	redeemed, err := r.access.Admit(ctx, message.Chat.ID, token)
+	if isDialogInputError(err) {
+		msg.Text = loc.Error(err)
+		msg.ParseMode = tgbotapi.ModeHTML
+		return msg, nil
+	}
+
+	if err != nil {
+		msg.Text = loc.T("common.error")
 		return msg, err
 	}
 
@@ -61,14 +128,82 @@ func (r *Registration) Run(ctx context.Context, message *tgbotapi.Message) (tgbo
 		login = message.Chat.FirstName
 	}
 
-	msg.Text = "Вы успешно зарегестрировались"
 	if err = r.userRepo.UserSave(ctx, message.Chat.ID, login); err != nil {
-		msg.Text = "Произошла ошибка при сохранении, повторите позже"
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
+
+	// уведомления отправляются вне сообщений пользователя, поэтому язык телеграма запоминаем сразу,
+	// изменить его можно в настройках
+	if err = r.userRepo.SaveLanguage(ctx, message.Chat.ID, string(loc)); err != nil {
+		msg.Text = loc.T("common.error_save")
+		return msg, err
+	}
+
+	text := loc.T("registration.success")
+	if token != "" && !redeemed {
+		inviteText, errInvite := r.acceptInvite(ctx, message.Chat.ID, token)
+		if errInvite != nil {
+			err = errInvite
+		}
+		text += "\n\n" + inviteText
+	}
+
+	ask, errAsk := r.dialog.Start(ctx, message.Chat.ID, commandArguments{})
+	if errAsk != nil {
+		msg.Text = text
+		msg.ParseMode = tgbotapi.ModeHTML
+		return msg, errors.Join(err, errAsk)
+	}
+
+	ask.Text = text + "\n\n" + ask.Text
+
+	return ask, err
+}
+
+// RunCallback отмена вопроса о часовом поясе, часовой пояс можно указать позже в настройках
+func (r *Registration) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	return r.dialog.RunCallback(ctx, query)
+}
+
+// acceptInvite добавляет пользователя в пространство по приглашению и возвращает ответ для пользователя
+func (r *Registration) acceptInvite(ctx context.Context, userId int64, token string) (string, error) {
+	loc := i18n.FromContext(ctx)
+
+	workspace, err := r.inviteRepo.AcceptInvite(ctx, userId, token)
+	if errors.Is(err, storage.ErrInviteNotFound) {
+		return loc.T("registration.invite_not_found"), nil
 	}
 
-	return msg, err
+	if err != nil {
+		return loc.T("registration.error_invite"), err
+	}
+
+	return loc.T("registration.invite_accepted", html.EscapeString(workspace.Name), loc.T(roleNames[workspace.Role]), WorkspaceCommand), nil
+}
+
+// saveTimezone сохраняет часовой пояс, указанный после регистрации
+func (r *Registration) saveTimezone(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+
+	timezone, err := parseTimezone(s.Answers["timezone"])
+	if err != nil {
+		msg.Text = loc.Error(err)
+		return msg, err
+	}
+
+	if err := r.userRepo.SaveTimezone(ctx, s.ChatId, timezone); err != nil {
+		msg.Text = loc.T("settings.error_timezone")
+		return msg, err
+	}
+
+	msg.Text = loc.T("registration.timezone_saved", timezone, SettingsCommand)
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
 }
 
 func (r *Registration) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-	return true, nil
+	return r.dialog.IsComplete(ctx, message)
 }
@@ -4,168 +4,176 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/redis/go-redis/v9"
-)
-
-const stateRemoveUrlNone = -1
-
-const (
-	stateRemoveUrlBegin  = iota //Начало удаление ссылки
-	stateRemoveUrlSetUrl        //ссылка которая будет удалена
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"strconv"
 )
 
 type (
 	// UrlRemover этот интерфейс реализует возможность удалять ссылки
 	UrlRemover interface {
-		RemoveUrl(userId int64, url string) error
-		UrlExist(userId int64, url string) (bool, error)
+		RemoveUrlById(workspaceId int64, id string) error
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
+	}
+
+	// MonitorStopper этот интерфейс реализует возможность остановить опрос удаленной ссылки
+	MonitorStopper interface {
+		Unschedule(pingId int64)
 	}
 
 	// RemoveUrl структура для обработки команды удаления ссылки
 	RemoveUrl struct {
 		urlRepo   UrlRemover
-		dialog    DialogChain
-		state     int
-		questions []string
+		scheduler MonitorStopper
+		dialog    *Dialog
 	}
 )
 
-func NewRemoveUrlCommand(dialog DialogChain, urlRepo UrlRemover) *RemoveUrl {
-	return &RemoveUrl{
-		urlRepo: urlRepo,
-		dialog:  dialog,
-		questions: []string{
-			"Укажите url адрес который необходимо удалить",
-		},
+func NewRemoveUrlCommand(dialog DialogChain, urlRepo UrlRemover, scheduler MonitorStopper) *RemoveUrl {
+	r := &RemoveUrl{
+		urlRepo:   urlRepo,
+		scheduler: scheduler,
 	}
+
+	r.dialog = NewDialog(RemoveUrlCommand, dialog, editorFromContext, r.confirm,
+		urlDialogStep("remove_url.ask_url", r.list),
+	)
+
+	return r
 }
 
 func (r *RemoveUrl) CommandName() string {
 	return RemoveUrlCommand
 }
 
-func (r *RemoveUrl) HelpText() string {
-	return "help text"
+func (r *RemoveUrl) Description(loc i18n.Locale) string {
+	return loc.T("remove_url.description")
 }
 
-func (r *RemoveUrl) key(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s", message.Chat.ID, r.CommandName())
+func (r *RemoveUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("remove_url.help")
 }
 
-func (r *RemoveUrl) keyAnswer(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s_answer", message.Chat.ID, r.CommandName())
+func (r *RemoveUrl) Flags() []string {
+	return r.dialog.Flags()
 }
 
 func (r *RemoveUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-
-	if message.IsCommand() == true {
-		return message.Command() == r.CommandName(), nil
-	}
-
-	key := r.key(message)
-
-	is, err := r.dialog.DialogExist(ctx, key)
-	if err != nil {
-		return false, err
-	}
-
-	return is, nil
+	return r.dialog.IsSupport(ctx, message)
 }
 
 func (r *RemoveUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-
-	key := r.key(message)
-
-	is, err := r.dialog.DialogExist(ctx, key)
-	if err != nil {
-		return false, err
-	}
-
-	return is == false, nil
+	return r.dialog.IsComplete(ctx, message)
 }
 
 func (r *RemoveUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
 	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", r.CommandName()))
 	defer span.End()
-	key := r.key(message)
-	msg := tgbotapi.NewMessage(message.Chat.ID, "")
 
-	state, err := r.dialog.CurrentState(ctx, key)
-	if err != nil && err != redis.Nil {
-		msg.Text = "ошибка при попытке создать запись"
+	msg, err := r.dialog.Run(ctx, message)
+	if err != nil {
 		span.RecordError(err)
-		return msg, err
 	}
 
-	if err == redis.Nil {
-		err = nil
-		state = stateRemoveUrlNone
-	}
+	return msg, err
+}
+
+// RunCallback выбор ссылки кнопкой, удаление выполняется только после подтверждения
+func (r *RemoveUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", r.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	msg := tgbotapi.NewMessage(chatId, "")
+	loc := i18n.FromContext(ctx)
 
-	var nextState int
-	switch state {
-	case stateRemoveUrlNone:
-		nextState = stateRemoveUrlBegin
-		msg.Text = r.questions[nextState]
-	case stateRemoveUrlBegin:
-		nextState = stateRemoveUrlSetUrl
-
-		is, errExist := r.urlRepo.UrlExist(message.Chat.ID, message.Text)
-		if errExist != nil {
-			msg.Text = "Ошибка при проверке ссылки, повторите ввод"
+	if r.dialog.IsCallback(query.Data) {
+		reply, err := r.dialog.RunCallback(ctx, query)
+		if err != nil {
 			span.RecordError(err)
-			return msg, errExist
 		}
 
-		if !is {
-			msg.Text = "Данная ссылка не существует"
-			return msg, nil
-		}
+		return reply, err
+	}
 
-		if err := r.urlRepo.RemoveUrl(message.Chat.ID, message.Text); err != nil {
-			msg.Text = "Произошла ошибка при удалении, повторите позже"
+	member, err := editorFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
 
-			span.RecordError(err)
+	action, id, err := parseUrlPick(query.Data)
+	if err != nil {
+		return nil, err
+	}
 
-			return msg, err
-		}
+	list, err := r.urlRepo.UrlListByWorkspace(member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
 
-		if err := r.ClearData(ctx, message); err != nil {
-			msg.Text = "Произошла ошибка, повторите позже"
+	ping, ok := pingById(list, id)
+	if !ok {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("remove_url.not_found")), nil
+	}
 
+	switch action {
+	case urlPickerActionConfirm:
+		if err := r.urlRepo.RemoveUrlById(member.WorkspaceId, strconv.FormatInt(ping.Id, 10)); err != nil {
+			msg.Text = loc.T("remove_url.error")
 			span.RecordError(err)
-
 			return msg, err
 		}
 
-		msg.Text = "Ссылка удалена"
+		// удаление подтверждается кнопкой, событие команды тут не отправляется, поэтому опрос останавливаем сами
+		r.scheduler.Unschedule(ping.Id)
 
-		return msg, err
+		edit := tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("remove_url.success", html.EscapeString(ping.Url)))
+		edit.ParseMode = tgbotapi.ModeHTML
+
+		return edit, nil
+	case urlPickerActionCancel:
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("remove_url.cancelled")), nil
 	default:
-		nextState = stateRemoveUrlNone
-		msg.Text = r.questions[0]
+		return nil, fmt.Errorf("неизвестное действие: %s", action)
 	}
+}
 
-	_, errSave := r.dialog.SaveState(ctx, key, nextState)
-	if errSave != nil {
-		msg.Text = "ошибка при сохранении текущего шага"
+func (r *RemoveUrl) list(s *DialogState) (model.PingList, error) {
+	return r.urlRepo.UrlListByWorkspace(s.Member.WorkspaceId)
+}
 
-		span.RecordError(errSave)
+// confirm запрос подтверждения удаления выбранной ссылки
+func (r *RemoveUrl) confirm(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
 
-		return msg, errSave
+	list, err := r.list(s)
+	if err != nil {
+		return tgbotapi.NewMessage(s.ChatId, loc.T("common.error_list")), err
 	}
 
-	return msg, err
+	ping, ok := pingByUrl(list, s.Answers[answerUrl])
+	if !ok {
+		return tgbotapi.NewMessage(s.ChatId, loc.T("remove_url.not_found")), nil
+	}
+
+	msg := tgbotapi.NewMessage(s.ChatId, loc.T("remove_url.confirm", html.EscapeString(ping.Url)))
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ReplyMarkup = confirmKeyboard(loc, r.CommandName(), ping.Id)
+
+	return msg, nil
 }
 
 func (r *RemoveUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
 	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", r.CommandName()))
 	defer span.End()
 
-	if err := r.dialog.DeleteDialog(ctx, r.key(message)); err != nil {
+	if err := r.dialog.Clear(ctx, message.Chat.ID); err != nil {
 		span.RecordError(err)
 		return err
 	}
 
-	return r.dialog.DeleteDialog(ctx, r.keyAnswer(message))
+	return nil
 }
@@ -0,0 +1,476 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	settingsActionTimezone   = "timezone"
+	settingsActionQuietHours = "quiet"
+	settingsActionLanguage   = "language" // "settings:language" - выбор языка, "settings:language:<код>" - сохранение
+)
+
+const (
+	answerSettingsField = "field" // изменяемая настройка, settingsActionTimezone или settingsActionQuietHours
+	answerSettingsValue = "value"
+)
+
+// settingsLanguageAuto выбор языка телеграма вместо сохраненного в настройках
+const settingsLanguageAuto = "auto"
+
+// weekdayNames короткие названия дней недели для ввода тихих часов, индекс соответствует time.Weekday
+var weekdayNames = [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"}
+
+// weekdayKeys ключи коротких названий дней недели для вывода, индекс соответствует time.Weekday
+var weekdayKeys = [7]string{"weekday.sun", "weekday.mon", "weekday.tue", "weekday.wed", "weekday.thu", "weekday.fri", "weekday.sat"}
+
+// weekdayAliases дополнительные названия дней недели, которые можно использовать при вводе тихих часов
+var weekdayAliases = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+type (
+	// UserSettings этот интерфейс реализует возможность получать и изменять настройки уведомлений пользователя
+	UserSettings interface {
+		NotificationSettings(ctx context.Context, userId int64) (model.NotificationSettings, error)
+		SaveTimezone(ctx context.Context, userId int64, timezone string) error
+		SaveQuietHours(ctx context.Context, userId int64, list model.QuietHoursList) error
+		SaveLanguage(ctx context.Context, userId int64, language string) error
+	}
+
+	// Settings структура для обработки команды просмотра и изменения настроек
+	Settings struct {
+		settingsRepo UserSettings
+		dialog       *Dialog
+	}
+)
+
+func NewSettingsCommand(dialog DialogChain, settingsRepo UserSettings) *Settings {
+	s := &Settings{
+		settingsRepo: settingsRepo,
+	}
+
+	s.dialog = NewDialog(SettingsCommand, dialog, anyoneFromContext, s.save,
+		DialogStep{
+			Name:     answerSettingsField,
+			Prompt:   dialogPrompt("settings.ask_field"),
+			Options:  s.fieldOptions,
+			Validate: validateSettingsField,
+		},
+		DialogStep{
+			Name:     answerSettingsValue,
+			Prompt:   s.valuePrompt,
+			Validate: validateSettingsValue,
+		},
+	)
+
+	return s
+}
+
+func (s *Settings) CommandName() string {
+	return SettingsCommand
+}
+
+func (s *Settings) Description(loc i18n.Locale) string {
+	return loc.T("settings.description")
+}
+
+func (s *Settings) HelpText(loc i18n.Locale) string {
+	return loc.T("settings.help")
+}
+
+func (s *Settings) Flags() []string {
+	return s.dialog.Flags()
+}
+
+func (s *Settings) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return s.dialog.IsSupport(ctx, message)
+}
+
+func (s *Settings) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return s.dialog.IsComplete(ctx, message)
+}
+
+// Run команда без аргументов выводит настройки с кнопками, с аргументами сразу изменяет настройку,
+// например /settings timezone +3, остальные сообщения отвечают на вопросы диалога
+func (s *Settings) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", s.CommandName()))
+	defer span.End()
+
+	if message.IsCommand() && strings.TrimSpace(message.CommandArguments()) == "" {
+		if err := s.ClearData(ctx, message); err != nil {
+			span.RecordError(err)
+			return tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("common.error")), err
+		}
+
+		return s.settingsMessage(ctx, message.Chat.ID)
+	}
+
+	msg, err := s.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+func (s *Settings) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", s.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	if s.dialog.IsCallback(query.Data) {
+		reply, err := s.dialog.RunCallback(ctx, query)
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return reply, err
+	}
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) == 2 && args[0] == settingsActionLanguage {
+		return s.saveLanguage(ctx, query.From, chatId, args[1])
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("не верные данные кнопки: %s", query.Data)
+	}
+
+	switch args[0] {
+	case settingsActionTimezone, settingsActionQuietHours:
+		// кнопка отвечает на первый шаг диалога, остается ввести значение
+		msg, err := s.dialog.Start(ctx, chatId, commandArguments{Flags: map[string][]string{answerSettingsField: {args[0]}}})
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return msg, err
+	case settingsActionLanguage:
+		msg := tgbotapi.NewMessage(chatId, loc.T("settings.ask_language"))
+		msg.ReplyMarkup = s.languageKeyboard(loc)
+		return msg, nil
+	default:
+		return nil, fmt.Errorf("неизвестное действие: %s", args[0])
+	}
+}
+
+func (s *Settings) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return s.dialog.Clear(ctx, message.Chat.ID)
+}
+
+// fieldOptions настройки, которые изменяются вводом значения, язык выбирается только кнопками
+func (s *Settings) fieldOptions(ctx context.Context, _ *DialogState) ([]DialogOption, error) {
+	loc := i18n.FromContext(ctx)
+
+	return []DialogOption{
+		{Id: settingsActionTimezone, Label: loc.T("settings.button_timezone"), Value: settingsActionTimezone},
+		{Id: settingsActionQuietHours, Label: loc.T("settings.button_quiet_hours"), Value: settingsActionQuietHours},
+	}, nil
+}
+
+// valuePrompt вопрос зависит от выбранной настройки
+func (s *Settings) valuePrompt(ctx context.Context, state *DialogState) (string, error) {
+	if state.Answers[answerSettingsField] == settingsActionQuietHours {
+		return i18n.FromContext(ctx).T("settings.ask_quiet_hours"), nil
+	}
+
+	return i18n.FromContext(ctx).T("settings.ask_timezone"), nil
+}
+
+// save сохраняет введенное значение и выводит настройки с изменениями
+func (s *Settings) save(ctx context.Context, state *DialogState) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+	value := state.Answers[answerSettingsValue]
+
+	switch state.Answers[answerSettingsField] {
+	case settingsActionTimezone:
+		timezone, err := parseTimezone(value)
+		if err != nil {
+			return tgbotapi.NewMessage(state.ChatId, loc.Error(err)), err
+		}
+
+		if err := s.settingsRepo.SaveTimezone(ctx, state.ChatId, timezone); err != nil {
+			return tgbotapi.NewMessage(state.ChatId, loc.T("settings.error_timezone")), err
+		}
+	case settingsActionQuietHours:
+		list, err := parseQuietHours(value)
+		if err != nil {
+			return tgbotapi.NewMessage(state.ChatId, loc.Error(err)), err
+		}
+
+		if err := s.settingsRepo.SaveQuietHours(ctx, state.ChatId, list); err != nil {
+			return tgbotapi.NewMessage(state.ChatId, loc.T("settings.error_quiet_hours")), err
+		}
+	}
+
+	return s.settingsMessage(ctx, state.ChatId)
+}
+
+// saveLanguage сохраняет выбранный язык и выводит настройки уже на нем
+func (s *Settings) saveLanguage(ctx context.Context, from *tgbotapi.User, chatId int64, code string) (tgbotapi.Chattable, error) {
+	language := i18n.Locale(code)
+	if code == settingsLanguageAuto {
+		language = ""
+	} else if !language.IsValid() {
+		return nil, fmt.Errorf("неизвестный язык: %s", code)
+	}
+
+	if err := s.settingsRepo.SaveLanguage(ctx, from.ID, string(language)); err != nil {
+		msg := tgbotapi.NewMessage(chatId, i18n.FromContext(ctx).T("settings.error_language"))
+		return msg, err
+	}
+
+	ctx = i18n.WithLocale(ctx, i18n.Resolve(string(language), from.LanguageCode))
+
+	return s.settingsMessage(ctx, chatId)
+}
+
+// languageKeyboard кнопки выбора языка, названия языков выводятся на них самих
+func (s *Settings) languageKeyboard(loc i18n.Locale) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(i18n.Locales)+1)
+	for _, l := range i18n.Locales {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(l.Name(), callbackData(s.CommandName(), settingsActionLanguage, string(l))))
+	}
+
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("settings.language_auto"), callbackData(s.CommandName(), settingsActionLanguage, settingsLanguageAuto)))
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+// settingsMessage текущие настройки пользователя с кнопками для их изменения
+func (s *Settings) settingsMessage(ctx context.Context, userId int64) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(userId, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	settings, err := s.settingsRepo.NotificationSettings(ctx, userId)
+	if err != nil {
+		msg.Text = loc.T("settings.error")
+		return msg, err
+	}
+
+	language := loc.T("settings.language_auto_current", loc.Name())
+	if l := i18n.Locale(settings.Language); l.IsValid() {
+		language = l.Name()
+	}
+
+	str := strings.Builder{}
+	str.WriteString(loc.T("settings.title"))
+	str.WriteString(loc.T("settings.timezone", settings.Timezone))
+	str.WriteString(loc.T("settings.language", language))
+	str.WriteString(loc.T("settings.quiet_hours"))
+
+	if len(settings.QuietHours) == 0 {
+		str.WriteString(loc.T("settings.quiet_hours_empty"))
+	} else {
+		str.WriteString("\n")
+		for _, q := range settings.QuietHours {
+			str.WriteString(fmt.Sprintf("%s <code>%s</code>\n", loc.T(weekdayKeys[q.Weekday]), q))
+		}
+	}
+
+	msg.Text = str.String()
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("settings.button_timezone"), callbackData(s.CommandName(), settingsActionTimezone)),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("settings.button_quiet_hours"), callbackData(s.CommandName(), settingsActionQuietHours)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("settings.button_language"), callbackData(s.CommandName(), settingsActionLanguage)),
+		),
+	)
+
+	return msg, nil
+}
+
+// validateSettingsField настройку можно передать аргументом команды, поэтому ее название проверяется
+func validateSettingsField(ctx context.Context, _ *DialogState, value string) error {
+	if value != settingsActionTimezone && value != settingsActionQuietHours {
+		return i18n.NewError("settings.error_field", value)
+	}
+
+	return nil
+}
+
+// validateSettingsValue проверяет значение выбранной настройки, при ошибке значение вводится повторно
+func validateSettingsValue(ctx context.Context, s *DialogState, value string) error {
+	if s.Answers[answerSettingsField] == settingsActionQuietHours {
+		if _, err := parseQuietHours(value); err != nil {
+			return i18n.NewError("settings.invalid_quiet_hours", i18n.FromContext(ctx).Error(err))
+		}
+
+		return nil
+	}
+
+	if _, err := parseTimezone(value); err != nil {
+		return i18n.NewError("settings.invalid_timezone")
+	}
+
+	return nil
+}
+
+// parseTimezone принимает название часового пояса (Europe/Moscow) или смещение от UTC в часах (+3, UTC-5)
+func parseTimezone(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.EqualFold(text, "local") {
+		return "", i18n.NewError("settings.error_empty_timezone")
+	}
+
+	if loc, err := time.LoadLocation(text); err == nil {
+		return loc.String(), nil
+	}
+
+	offset := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(text), "UTC"), "GMT")
+	hours, err := strconv.Atoi(offset)
+	if err != nil || hours < -12 || hours > 14 {
+		return "", i18n.NewError("settings.error_unknown_timezone", text)
+	}
+
+	if hours == 0 {
+		return model.DefaultTimezone, nil
+	}
+
+	// в зонах Etc/GMT знак инвертирован: Etc/GMT-3 это UTC+3
+	timezone := fmt.Sprintf("Etc/GMT%+d", -hours)
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return "", err
+	}
+
+	return timezone, nil
+}
+
+// parseQuietHours разбирает тихие часы, каждая строка имеет вид "дни начало-конец",
+// дни можно перечислять через запятую, задавать диапазоном (пн-пт) или словом "все"
+func parseQuietHours(text string) (model.QuietHoursList, error) {
+	text = strings.TrimSpace(text)
+	if text == "0" || text == "-" {
+		return model.QuietHoursList{}, nil
+	}
+
+	days := make(map[time.Weekday]model.QuietHours)
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' }) {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, i18n.NewError("settings.error_quiet_line", line)
+		}
+
+		weekdays, err := parseWeekdays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		start, end, ok := strings.Cut(fields[1], "-")
+		if !ok {
+			return nil, i18n.NewError("settings.error_quiet_interval", fields[1])
+		}
+
+		startMinute, err := parseDayMinute(start)
+		if err != nil {
+			return nil, err
+		}
+
+		endMinute, err := parseDayMinute(end)
+		if err != nil {
+			return nil, err
+		}
+
+		if startMinute == endMinute {
+			return nil, i18n.NewError("settings.error_quiet_empty", fields[1])
+		}
+
+		for _, weekday := range weekdays {
+			days[weekday] = model.QuietHours{Weekday: weekday, Start: startMinute, End: endMinute}
+		}
+	}
+
+	list := make(model.QuietHoursList, 0, len(days))
+	for _, q := range days {
+		list = append(list, q)
+	}
+
+	slices.SortFunc(list, func(a, b model.QuietHours) int {
+		return int(a.Weekday) - int(b.Weekday)
+	})
+
+	return list, nil
+}
+
+func parseWeekdays(text string) ([]time.Weekday, error) {
+	text = strings.ToLower(text)
+	if text == "все" || text == "all" || text == "*" {
+		return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}, nil
+	}
+
+	var weekdays []time.Weekday
+	for _, part := range strings.Split(text, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+
+		first, err := parseWeekday(from)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRange {
+			weekdays = append(weekdays, first)
+			continue
+		}
+
+		last, err := parseWeekday(to)
+		if err != nil {
+			return nil, err
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			weekdays = append(weekdays, day)
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return weekdays, nil
+}
+
+func parseWeekday(text string) (time.Weekday, error) {
+	for i, name := range weekdayNames {
+		if name == text {
+			return time.Weekday(i), nil
+		}
+	}
+
+	if weekday, ok := weekdayAliases[text]; ok {
+		return weekday, nil
+	}
+
+	return 0, i18n.NewError("settings.error_weekday", text)
+}
+
+// parseDayMinute переводит время вида 23:30 в минуты от начала суток, 24:00 - конец суток
+func parseDayMinute(text string) (int, error) {
+	if text == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, i18n.NewError("settings.error_time", text)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
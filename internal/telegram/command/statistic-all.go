@@ -4,14 +4,14 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
-	"strings"
 )
 
 type (
-	// StatisticUrlList этот интерфейс реализует возможность получения ссылок статистики для определенного пользователя
+	// StatisticUrlList этот интерфейс реализует возможность получения статистики по ссылкам пространства
 	StatisticUrlList interface {
-		StatisticByUser(userId int64) (model.StatisticResultList, error)
+		StatisticByWorkspace(workspaceId int64) (model.StatisticResultList, error)
 	}
 
 	// StatisticAll структура для обработки команды вывода общей статистики
@@ -30,8 +30,12 @@ func (s *StatisticAll) CommandName() string {
 	return StatisticAllCommand
 }
 
-func (s *StatisticAll) HelpText() string {
-	return "help text"
+func (s *StatisticAll) Description(loc i18n.Locale) string {
+	return loc.T("statistic_all.description")
+}
+
+func (s *StatisticAll) HelpText(loc i18n.Locale) string {
+	return loc.T("statistic_all.help")
 }
 
 func (s *StatisticAll) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -44,41 +48,77 @@ func (s *StatisticAll) IsSupport(ctx context.Context, message *tgbotapi.Message)
 }
 
 func (s *StatisticAll) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
-	userId := message.Chat.ID
-	msg := tgbotapi.NewMessage(userId, "")
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
 
-	_, span := tracer.Start(ctx, fmt.Sprintf("run_statistic_%d", userId))
+	_, span := tracer.Start(ctx, fmt.Sprintf("run_statistic_%d", message.Chat.ID))
 	defer span.End()
 
-	list, err := s.statisticRepo.StatisticByUser(userId)
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	pages, err := s.pager(loc, member.WorkspaceId)
 
 	if err != nil {
 		span.RecordError(err)
-		msg.Text = "Произошла ошибка при получении списка, повторите позже"
+		msg.Text = loc.T("common.error_list")
 		return msg, err
 	}
 
-	if len(list) == 0 {
-		msg.Text = "У вас еще нет записей"
+	if pages.Len() == 0 {
+		msg.Text = loc.T("common.empty_list")
 		return msg, nil
 	}
 
-	str := strings.Builder{}
+	return pages.Message(loc, message.Chat.ID), nil
+}
+
+// RunCallback перелистывает страницы общей статистики
+func (s *StatisticAll) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", s.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	page, _, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := s.pager(loc, member.WorkspaceId)
+	if err != nil {
+		span.RecordError(err)
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	if pages.Len() == 0 {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.empty_list")), nil
+	}
+
+	return pages.Edit(loc, query, page), nil
+}
+
+// pager статистика опросов и инцидентов по всем ссылкам пространства постранично
+func (s *StatisticAll) pager(loc i18n.Locale, workspaceId int64) (*pager, error) {
+	list, err := s.statisticRepo.StatisticByWorkspace(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(s.CommandName())
 	for _, url := range list {
-		str.WriteString(fmt.Sprintf("🌐 <code>%s</code> \n"+
-			"🔄 Коли-во соединений = <code>%d</code> \n"+
-			"👌 Коли-во успешных соединений = <code>%d</code> \n"+
-			"⛔️ Коли-во прерваных соединений = <code>%d</code> \n"+
-			"⏳ Макс-ое время ожидания - <code>%.4f</code> \n"+
-			"⏳ Мин-ое время ожидания - <code>%.4f</code> \n"+
-			"🕤 Среднее время ожидания - <code>%.4f</code>\n\n",
-			url.Url, url.CountPing, url.CorrectCount, url.CancelCount, url.MaxConnectionTime, url.MinConnectionTime, url.AvgConnectionTime),
-		)
+		pages.Add(statisticText(loc, url) + incidentStatisticText(loc, url.Incidents))
 	}
-	msg.ParseMode = tgbotapi.ModeHTML
-	msg.Text = str.String()
 
-	return msg, nil
+	return pages, nil
 }
 
 func (s *StatisticAll) ClearData(ctx context.Context, message *tgbotapi.Message) error {
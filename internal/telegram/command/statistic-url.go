@@ -4,194 +4,164 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
-	"github.com/redis/go-redis/v9"
+	"html"
 	"strings"
 )
 
-const stateStatisticUrlNone = -1
-
-const (
-	stateStatisticUrlBegin  = iota //Начало получения статистики по ссылке
-	stateStatisticUrlSetUrl        //ссылка по которой будет предоставлена статистика
-)
-
 type (
 	// UrlStatistic этот интерфейс реализует возможность полученияданных по ссылке
 	UrlStatistic interface {
-		StatisticByUrl(userId int64, url string) (model.Statistic, error)
+		StatisticByUrl(workspaceId int64, url string) (model.Statistic, error)
 	}
 
-	// UrlRepositoryExist этот интерфейс реализует возможность проверить наличие ссылки у пользователя
+	// UrlRepositoryExist этот интерфейс реализует возможность проверить наличие ссылки в пространстве и выбрать ее из списка
 	UrlRepositoryExist interface {
-		UrlExist(userId int64, url string) (bool, error)
+		UrlExist(workspaceId int64, url string) (bool, error)
+		UrlListByWorkspace(workspaceId int64) (model.PingList, error)
 	}
 
 	// StatisticUrl структура для обработки команды вывода статистики для определенной ссылки
 	StatisticUrl struct {
 		statisticRepo UrlStatistic
-		dialog        DialogChain
 		urlRepo       UrlRepositoryExist
-		questions     []string
+		dialog        *Dialog
 	}
 )
 
 func NewStatisticUrlCommand(statisticRepo UrlStatistic, dialog DialogChain, urlRepo UrlRepositoryExist) *StatisticUrl {
-	return &StatisticUrl{
+	s := &StatisticUrl{
 		statisticRepo: statisticRepo,
-		dialog:        dialog,
 		urlRepo:       urlRepo,
-		questions: []string{
-			"Укажите url адрес по которому необходимо вывести статистику",
-		},
 	}
+
+	s.dialog = NewDialog(StatisticUrlCommand, dialog, memberFromContext, s.statistic,
+		urlDialogStep("statistic_url.ask_url", s.list),
+	)
+
+	return s
 }
 
 func (s *StatisticUrl) CommandName() string {
 	return StatisticUrlCommand
 }
 
-func (s *StatisticUrl) HelpText() string {
-	return "help text"
+func (s *StatisticUrl) Description(loc i18n.Locale) string {
+	return loc.T("statistic_url.description")
 }
 
-func (s *StatisticUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-
-	if message.IsCommand() == true {
-		return message.Command() == s.CommandName(), nil
-	}
-
-	key := s.key(message)
-
-	is, err := s.dialog.DialogExist(ctx, key)
-	if err != nil {
-		return false, err
-	}
-
-	return is, nil
+func (s *StatisticUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("statistic_url.help")
 }
 
-func (s *StatisticUrl) key(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s", message.Chat.ID, s.CommandName())
+func (s *StatisticUrl) Flags() []string {
+	return s.dialog.Flags()
 }
 
-func (s *StatisticUrl) keyAnswer(message *tgbotapi.Message) string {
-	return fmt.Sprintf("%d_%s_answer", message.Chat.ID, s.CommandName())
+func (s *StatisticUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return s.dialog.IsSupport(ctx, message)
 }
 
 func (s *StatisticUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
 	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", s.CommandName()))
 	defer span.End()
-	key := s.key(message)
-	msg := tgbotapi.NewMessage(message.Chat.ID, "")
 
-	state, err := s.dialog.CurrentState(ctx, key)
-	if err != nil && err != redis.Nil {
-		msg.Text = "ошибка при попытке создать запись"
+	msg, err := s.dialog.Run(ctx, message)
+	if err != nil {
 		span.RecordError(err)
-		return msg, err
-	}
-
-	if err == redis.Nil {
-		err = nil
-		state = stateRemoveUrlNone
 	}
 
-	var nextState int
-	switch state {
-	case stateStatisticUrlNone:
-		nextState = stateStatisticUrlBegin
-		msg.Text = s.questions[nextState]
-	case stateStatisticUrlBegin:
-		nextState = stateStatisticUrlSetUrl
-
-		is, errExist := s.urlRepo.UrlExist(message.Chat.ID, message.Text)
-		if errExist != nil {
-			msg.Text = "Ошибка при проверке ссылки, повторите ввод"
-			span.RecordError(err)
-			return msg, errExist
-		}
+	return msg, err
+}
 
-		if !is {
-			msg.Text = "Данная ссылка не существует"
-			return msg, nil
-		}
+// RunCallback выводит статистику по ссылке выбранной кнопкой
+func (s *StatisticUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", s.CommandName()))
+	defer span.End()
 
-		stats, err := s.statisticRepo.StatisticByUrl(message.Chat.ID, message.Text)
-		if err != nil {
-			msg.Text = "Произошла ошибка при получении статистики"
+	msg, err := s.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
 
-			span.RecordError(err)
+	return msg, err
+}
 
-			return msg, err
-		}
+func (s *StatisticUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", s.CommandName()))
+	defer span.End()
 
-		if err := s.ClearData(ctx, message); err != nil {
-			msg.Text = "Произошла ошибка, повторите позже"
+	if err := s.dialog.Clear(ctx, message.Chat.ID); err != nil {
+		span.RecordError(err)
+		return err
+	}
 
-			span.RecordError(err)
+	return nil
+}
 
-			return msg, err
-		}
+func (s *StatisticUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return s.dialog.IsComplete(ctx, message)
+}
 
-		str := strings.Builder{}
-		str.WriteString(fmt.Sprintf("🌐 <code>%s</code> \n"+
-			"🔄 Коли-во соединений - <code>%d</code> \n"+
-			"👌 Коли-во успешных соединений - <code>%d</code> \n"+
-			"⛔️ Коли-во прерваных соединений - <code>%d</code> \n"+
-			"⏳ Макс-ое время ожидания - <code>%.4f</code> \n"+
-			"⏳ Мин-ое время ожидания - <code>%.4f</code> \n"+
-			"🕤 Среднее время ожидания - <code>%.4f</code>\n\n",
-			stats.Url, stats.CountPing, stats.CorrectCount, stats.CancelCount, stats.MaxConnectionTime, stats.MinConnectionTime, stats.AvgConnectionTime,
-		))
-
-		if len(stats.Errors) > 0 {
-			str.WriteString("Спсиок ошибок\n\n")
-			for _, errText := range stats.Errors {
-				str.WriteString(fmt.Sprintf("Кол-во: %d\n<code>%s</code> \n\n", errText.Count, errText.Text))
-			}
-		}
+func (s *StatisticUrl) list(state *DialogState) (model.PingList, error) {
+	return s.urlRepo.UrlListByWorkspace(state.Member.WorkspaceId)
+}
 
-		msg.ParseMode = tgbotapi.ModeHTML
-		msg.Text = str.String()
+func (s *StatisticUrl) statistic(ctx context.Context, state *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(state.ChatId, "")
+	loc := i18n.FromContext(ctx)
 
+	stats, err := s.statisticRepo.StatisticByUrl(state.Member.WorkspaceId, state.Answers[answerUrl])
+	if err != nil {
+		msg.Text = loc.T("statistic.error")
 		return msg, err
-	default:
-		nextState = stateRemoveUrlNone
-		msg.Text = s.questions[0]
 	}
 
-	_, errSave := s.dialog.SaveState(ctx, key, nextState)
-	if errSave != nil {
-		msg.Text = "ошибка при сохранении текущего шага"
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.Text = statisticUrlText(loc, stats)
 
-		span.RecordError(errSave)
+	return msg, nil
+}
+
+// statisticUrlText форматирует подробную статистику по ссылке вместе со списком ошибок
+func statisticUrlText(loc i18n.Locale, stats model.Statistic) string {
+	str := strings.Builder{}
+	str.WriteString(statisticText(loc, stats))
+	str.WriteString(incidentStatisticText(loc, stats.Incidents))
 
-		return msg, errSave
+	if len(stats.Errors) > 0 {
+		str.WriteString(loc.T("statistic.errors"))
+		for _, errText := range stats.Errors {
+			str.WriteString(loc.Plural("statistic.error_item", errText.Count, html.EscapeString(errText.Text)))
+		}
 	}
 
-	return msg, err
+	return str.String()
 }
 
-func (s *StatisticUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
-	ctx, span := tracer.Start(ctx, fmt.Sprintf("clear data for %s", s.CommandName()))
-	defer span.End()
+// statisticText форматирует кол-во опросов и время ответа по ссылке
+func statisticText(loc i18n.Locale, stats model.Statistic) string {
+	return loc.T("statistic.summary",
+		html.EscapeString(stats.Url), stats.CountPing, stats.CorrectCount, stats.CancelCount,
+		loc.Number(stats.MaxConnectionTime, 4), loc.Number(stats.MinConnectionTime, 4), loc.Number(stats.AvgConnectionTime, 4),
+	)
+}
 
-	if err := s.dialog.DeleteDialog(ctx, s.key(message)); err != nil {
-		span.RecordError(err)
-		return err
+// incidentStatisticText форматирует кол-во инцидентов, MTTR и MTBF по ссылке
+func incidentStatisticText(loc i18n.Locale, stats model.IncidentStatistic) string {
+	if stats.Count == 0 {
+		return loc.T("statistic.no_incidents")
 	}
 
-	return s.dialog.DeleteDialog(ctx, s.keyAnswer(message))
+	return loc.T("statistic.incidents", stats.Count, secondsText(loc, stats.Mttr), secondsText(loc, stats.Mtbf))
 }
 
-func (s *StatisticUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
-	key := s.key(message)
-
-	is, err := s.dialog.DialogExist(ctx, key)
-	if err != nil {
-		return false, err
+// secondsText переводит кол-во секунд в длительность вида "1 ч 2 мин"
+func secondsText(loc i18n.Locale, seconds float64) string {
+	if seconds <= 0 {
+		return "-"
 	}
 
-	return is == false, nil
+	return loc.Seconds(seconds)
 }
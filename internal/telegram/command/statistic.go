@@ -4,27 +4,36 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
 	"strings"
 )
 
 type (
-	// CurrentStatisticUrlList этот интерфейс реализует возможность получения ссылок статистики для определенного пользователя только по существующим ссылкам
+	// CurrentStatisticUrlList этот интерфейс реализует возможность получения статистики по ссылкам пространства только по существующим ссылкам
 	CurrentStatisticUrlList interface {
-		CurrentStatisticByUser(userId int64, urlList []string) (model.StatisticResultList, error)
+		CurrentStatisticByWorkspace(workspaceId int64, urlList []string) (model.StatisticResultList, error)
+	}
+
+	// TagStatistic этот интерфейс реализует возможность получения общей статистики по ссылкам с тегом
+	TagStatistic interface {
+		GroupStatistic(ctx context.Context, workspaceId int64, tag string) (model.GroupStatistic, error)
 	}
 
 	// Statistic структура для обработки команды вывода текущей статистики
 	Statistic struct {
 		statisticRepo CurrentStatisticUrlList
 		urlRepo       UserUrlList
+		groupRepo     TagStatistic
 	}
 )
 
-func NewStatisticCommand(statisticRepo CurrentStatisticUrlList, urlRepo UserUrlList) *Statistic {
+func NewStatisticCommand(statisticRepo CurrentStatisticUrlList, urlRepo UserUrlList, groupRepo TagStatistic) *Statistic {
 	return &Statistic{
 		statisticRepo: statisticRepo,
 		urlRepo:       urlRepo,
+		groupRepo:     groupRepo,
 	}
 }
 
@@ -32,8 +41,12 @@ func (s *Statistic) CommandName() string {
 	return StatisticCommand
 }
 
-func (s *Statistic) HelpText() string {
-	return "help text"
+func (s *Statistic) Description(loc i18n.Locale) string {
+	return loc.T("statistic.description")
+}
+
+func (s *Statistic) HelpText(loc i18n.Locale) string {
+	return loc.T("statistic.help")
 }
 
 func (s *Statistic) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -46,50 +59,102 @@ func (s *Statistic) IsSupport(ctx context.Context, message *tgbotapi.Message) (b
 }
 
 func (s *Statistic) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
-	const errorMessage = "Произошла ошибка при получении списка, повторите позже"
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+	errorMessage := loc.T("common.error_list")
 
-	userId := message.Chat.ID
-	msg := tgbotapi.NewMessage(userId, "")
-
-	_, span := tracer.Start(ctx, fmt.Sprintf("run_statistic_%d", userId))
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("run_statistic_%d", message.Chat.ID))
 	defer span.End()
 
-	urlList, err := s.urlUserList(message.Chat.ID)
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	tag, err := commandTag(message)
+	if err != nil {
+		msg.Text = loc.Error(err)
+		return msg, nil
+	}
+
+	pages, err := s.pager(ctx, loc, member.WorkspaceId, tag)
 	if err != nil {
 		span.RecordError(err)
 		msg.Text = errorMessage
 		return msg, err
 	}
 
-	list, err := s.statisticRepo.CurrentStatisticByUser(userId, urlList)
+	if pages.Len() == 0 {
+		msg.Text = emptyListText(loc, tag)
+		return msg, nil
+	}
+
+	return pages.Message(loc, message.Chat.ID), nil
+}
+
+// RunCallback перелистывает страницы статистики, фильтр по тегу передается в данных кнопки
+func (s *Statistic) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", s.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	page, args, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
 
+	tag := pagerTag(args)
+	pages, err := s.pager(ctx, loc, member.WorkspaceId, tag)
 	if err != nil {
 		span.RecordError(err)
-		msg.Text = errorMessage
-		return msg, err
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	if pages.Len() == 0 {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, emptyListText(loc, tag)), nil
 	}
 
+	return pages.Edit(loc, query, page), nil
+}
+
+// pager статистика по ссылкам с тегом постранично, с тегом первой идет общая статистика по всем его ссылкам
+func (s *Statistic) pager(ctx context.Context, loc i18n.Locale, workspaceId int64, tag string) (*pager, error) {
+	urlList, err := s.urlWorkspaceList(workspaceId, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.statisticRepo.CurrentStatisticByWorkspace(workspaceId, urlList)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := newPager(s.CommandName(), tagArgs(tag)...)
 	if len(list) == 0 {
-		msg.Text = "У вас еще нет записей"
-		return msg, nil
+		return pages, nil
+	}
+
+	if tag != "" {
+		group, err := s.groupRepo.GroupStatistic(ctx, workspaceId, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		pages.Add(groupStatisticText(loc, group))
 	}
 
-	str := strings.Builder{}
 	for _, url := range list {
-		str.WriteString(fmt.Sprintf("🌐 <code>%s</code> \n"+
-			"🔄 Коли-во соединений - <code>%d</code> \n"+
-			"👌 Коли-во успешных соединений - <code>%d</code> \n"+
-			"⛔️ Коли-во прерваных соединений - <code>%d</code> \n"+
-			"⏳ Макс-ое время ожидания - <code>%.4f</code> \n"+
-			"⏳ Мин-ое время ожидания - <code>%.4f</code> \n"+
-			"🕤 Среднее время ожидания - <code>%.4f</code>\n\n",
-			url.Url, url.CountPing, url.CorrectCount, url.CancelCount, url.MaxConnectionTime, url.MinConnectionTime, url.AvgConnectionTime),
-		)
-	}
-	msg.ParseMode = tgbotapi.ModeHTML
-	msg.Text = str.String()
-
-	return msg, nil
+		pages.Add(statisticText(loc, url) + "\n")
+	}
+
+	return pages, nil
 }
 
 func (s *Statistic) ClearData(ctx context.Context, message *tgbotapi.Message) error {
@@ -100,16 +165,33 @@ func (s *Statistic) IsComplete(ctx context.Context, message *tgbotapi.Message) (
 	return true, nil
 }
 
-func (s *Statistic) urlUserList(id int64) ([]string, error) {
-	list, err := s.urlRepo.UrlListByUser(id)
+// urlWorkspaceList адреса ссылок пространства с тегом, пустой тег - все ссылки
+func (s *Statistic) urlWorkspaceList(workspaceId int64, tag string) ([]string, error) {
+	list, err := s.urlRepo.UrlListByWorkspace(workspaceId)
 	if err != nil {
 		return nil, err
 	}
 
-	urlList := make([]string, len(list), len(list))
-	for _, item := range list {
-		urlList = append(urlList, item.Url)
+	return list.WithTag(tag).Urls(), nil
+}
+
+// groupStatisticText форматирует общую статистику по ссылкам с тегом
+func groupStatisticText(loc i18n.Locale, group model.GroupStatistic) string {
+	stats := group.Statistic
+
+	str := strings.Builder{}
+	str.WriteString(loc.T("statistic.group",
+		html.EscapeString(group.Tag), len(group.Urls), stats.CountPing, stats.CorrectCount, stats.CancelCount,
+		loc.Number(stats.MaxConnectionTime, 4), loc.Number(stats.MinConnectionTime, 4), loc.Number(stats.AvgConnectionTime, 4),
+	))
+	str.WriteString(incidentStatisticText(loc, stats.Incidents))
+
+	if len(stats.Errors) > 0 {
+		str.WriteString(loc.T("statistic.group_errors"))
+		for _, errText := range stats.Errors {
+			str.WriteString(loc.Plural("statistic.error_item", errText.Count, html.EscapeString(errText.Text)))
+		}
 	}
 
-	return urlList, nil
+	return str.String()
 }
@@ -0,0 +1,242 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	statusPageSize  = 10
+	statusErrorSize = 120 // длинные ошибки обрезаются, телеграм ограничивает длину сообщения
+)
+
+// statusEmoji значок статуса ссылки в обзоре
+var statusEmoji = map[model.MonitorStatus]string{
+	model.StatusDown:     "🔴",
+	model.StatusDegraded: "🟡",
+	model.StatusPending:  "⚪",
+	model.StatusPaused:   "⏸",
+	model.StatusUp:       "🟢",
+}
+
+type (
+	// MonitorStatusList этот интерфейс реализует возможность получить текущее состояние ссылок пространства
+	MonitorStatusList interface {
+		StatusByWorkspace(ctx context.Context, workspaceId int64) (model.MonitorOverviewList, error)
+	}
+
+	// Status структура для обработки команды вывода текущего состояния ссылок
+	Status struct {
+		overview MonitorStatusList
+	}
+)
+
+func NewStatusCommand(overview MonitorStatusList) *Status {
+	return &Status{
+		overview: overview,
+	}
+}
+
+func (s *Status) CommandName() string {
+	return StatusCommand
+}
+
+func (s *Status) Description(loc i18n.Locale) string {
+	return loc.T("status.description")
+}
+
+func (s *Status) HelpText(loc i18n.Locale) string {
+	return loc.T("status.help")
+}
+
+func (s *Status) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == s.CommandName(), nil
+}
+
+// Run выводит первую страницу обзора, остальные страницы открываются кнопками
+func (s *Status) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", s.CommandName()))
+	defer span.End()
+	msg := tgbotapi.NewMessage(message.Chat.ID, "")
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	tag, err := commandTag(message)
+	if err != nil {
+		msg.Text = loc.Error(err)
+		return msg, nil
+	}
+
+	list, err := s.overview.StatusByWorkspace(ctx, member.WorkspaceId)
+	if err != nil {
+		msg.Text = loc.T("common.error_list")
+		span.RecordError(err)
+		return msg, err
+	}
+
+	list = list.WithTag(tag)
+	if len(list) == 0 {
+		msg.Text = emptyListText(loc, tag)
+		return msg, nil
+	}
+
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.Text = statusText(loc, list, tag, 0, time.Now())
+	msg.ReplyMarkup = s.keyboard(loc, list, tag, 0)
+
+	return msg, nil
+}
+
+// RunCallback перелистывает страницы обзора и обновляет его в том же сообщении
+func (s *Status) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", s.CommandName()))
+	defer span.End()
+	chatId := query.Message.Chat.ID
+	loc := i18n.FromContext(ctx)
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(chatId, memberErrorText(ctx, err)), nil
+	}
+
+	page, args, err := parsePagerData(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := pagerTag(args)
+
+	list, err := s.overview.StatusByWorkspace(ctx, member.WorkspaceId)
+	if err != nil {
+		span.RecordError(err)
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, loc.T("common.error_list")), err
+	}
+
+	list = list.WithTag(tag)
+	if len(list) == 0 {
+		return tgbotapi.NewEditMessageText(chatId, query.Message.MessageID, emptyListText(loc, tag)), nil
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatId, query.Message.MessageID, statusText(loc, list, tag, page, time.Now()), s.keyboard(loc, list, tag, page))
+	edit.ParseMode = tgbotapi.ModeHTML
+
+	return edit, nil
+}
+
+func (s *Status) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+
+	return nil
+}
+
+func (s *Status) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
+
+// keyboard кнопки перехода по страницам и обновления текущей страницы, фильтр по тегу сохраняется в данных кнопок
+func (s *Status) keyboard(loc i18n.Locale, list model.MonitorOverviewList, tag string, page int) tgbotapi.InlineKeyboardMarkup {
+	pages := statusPages(list)
+	page = max(0, min(page, pages-1))
+
+	data := func(page int) string {
+		return callbackData(s.CommandName(), append([]string{urlPickerActionPage, strconv.Itoa(page)}, tagArgs(tag)...)...)
+	}
+
+	row := make([]tgbotapi.InlineKeyboardButton, 0, 3)
+	if page > 0 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.back"), data(page-1)))
+	}
+
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("status.refresh"), data(page)))
+
+	if page < pages-1 {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.next", page+1, pages), data(page+1)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+func statusPages(list model.MonitorOverviewList) int {
+	return max(1, (len(list)+statusPageSize-1)/statusPageSize)
+}
+
+// statusText сводка по статусам и страница списка ссылок с их состоянием, page начинается с 0
+func statusText(loc i18n.Locale, list model.MonitorOverviewList, tag string, page int, now time.Time) string {
+	page = max(0, min(page, statusPages(list)-1))
+
+	str := strings.Builder{}
+	if tag == "" {
+		str.WriteString(loc.T("status.title", len(list)))
+	} else {
+		str.WriteString(loc.T("status.title_tag", html.EscapeString(tag), len(list)))
+	}
+
+	var summary []string
+	for _, status := range []model.MonitorStatus{model.StatusDown, model.StatusDegraded, model.StatusPending, model.StatusPaused, model.StatusUp} {
+		if count := list.Count(status); count > 0 {
+			summary = append(summary, fmt.Sprintf("%s %d", statusEmoji[status], count))
+		}
+	}
+	str.WriteString(strings.Join(summary, "  ") + "\n")
+
+	from := page * statusPageSize
+	for _, item := range list[from:min(from+statusPageSize, len(list))] {
+		str.WriteString("\n" + statusItemText(loc, item, now))
+	}
+
+	return str.String()
+}
+
+// statusItemText состояние одной ссылки: статус и сколько он длится, время ответа и ошибка последнего опроса
+func statusItemText(loc i18n.Locale, item model.MonitorOverview, now time.Time) string {
+	str := strings.Builder{}
+	str.WriteString(fmt.Sprintf("%s %s", statusEmoji[item.Status], html.EscapeString(item.Url)))
+	if item.Critical {
+		str.WriteString(" 🔥")
+	}
+
+	name := loc.T("status.name_" + string(item.Status))
+	if item.Status == model.StatusPending || item.Since.IsZero() {
+		str.WriteString("\n    " + name + "\n")
+		return str.String()
+	}
+
+	if item.Status == model.StatusPaused {
+		str.WriteString("\n    " + name)
+	} else {
+		str.WriteString("\n    " + loc.T("status.since", name, loc.Duration(now.Sub(item.Since))))
+	}
+
+	str.WriteString(loc.T("status.latency", loc.Number(item.LastLatency, 3)))
+	if item.LastStatusCode > 0 {
+		str.WriteString(loc.T("status.code", item.LastStatusCode))
+	}
+
+	if item.LastError != "" {
+		text := item.LastError
+		if utf8.RuneCountInString(text) > statusErrorSize {
+			text = string([]rune(text)[:statusErrorSize-1]) + "…"
+		}
+
+		str.WriteString("\n    <i>" + html.EscapeString(text) + "</i>")
+	}
+
+	return str.String() + "\n"
+}
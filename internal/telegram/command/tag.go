@@ -0,0 +1,46 @@
+package command
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"strings"
+)
+
+// commandTag тег из аргумента команды, например /status env:prod, пустая строка - без фильтра
+func commandTag(message *tgbotapi.Message) (string, error) {
+	return model.ParseTagFilter(message.CommandArguments())
+}
+
+// tagArgs аргументы данных кнопки для фильтра по тегу, см. pagerTag
+func tagArgs(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+
+	return []string{tag}
+}
+
+// tagsText теги ссылки одной строкой, у ссылки без тегов - пустая строка
+func tagsText(loc i18n.Locale, tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	codes := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		codes = append(codes, "<code>"+html.EscapeString(tag)+"</code>")
+	}
+
+	return loc.T("tag.list", strings.Join(codes, " "))
+}
+
+// emptyListText ответ на пустой список ссылок с учетом фильтра по тегу
+func emptyListText(loc i18n.Locale, tag string) string {
+	if tag == "" {
+		return loc.T("common.empty_list")
+	}
+
+	return loc.T("tag.empty_list", html.EscapeString(tag))
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/alert"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+)
+
+type (
+	// AlertTemplateProvider этот интерфейс реализует возможность получить шаблон уведомления пользователя
+	AlertTemplateProvider interface {
+		AlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) (string, error)
+	}
+
+	// TemplatePreview структура для обработки команды предпросмотра уведомлений на тестовых данных
+	TemplatePreview struct {
+		templateRepo AlertTemplateProvider
+		apiPath      string
+	}
+)
+
+func NewTemplatePreviewCommand(templateRepo AlertTemplateProvider, apiPath string) *TemplatePreview {
+	return &TemplatePreview{
+		templateRepo: templateRepo,
+		apiPath:      apiPath,
+	}
+}
+
+func (t *TemplatePreview) CommandName() string {
+	return TemplatePreviewCommand
+}
+
+func (t *TemplatePreview) Description(loc i18n.Locale) string {
+	return loc.T("template_preview.description")
+}
+
+func (t *TemplatePreview) HelpText(loc i18n.Locale) string {
+	return loc.T("template_preview.help")
+}
+
+func (t *TemplatePreview) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+
+	if message.IsCommand() != true {
+		return false, nil
+	}
+
+	return message.Command() == t.CommandName(), nil
+}
+
+func (t *TemplatePreview) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	loc := i18n.FromContext(ctx)
+	msg := tgbotapi.NewMessage(message.Chat.ID, loc.T("template_preview.ask_kind"))
+	msg.ReplyMarkup = templateKindKeyboard(loc, t.CommandName())
+
+	return msg, nil
+}
+
+func (t *TemplatePreview) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	chatId := query.Message.Chat.ID
+	msg := tgbotapi.NewMessage(chatId, "")
+	loc := i18n.FromContext(ctx)
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	kind, err := parseTemplateKind(query.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := t.templateRepo.AlertTemplate(ctx, chatId, model.ChannelTelegram, kind)
+	if err != nil {
+		msg.Text = loc.T("template.error")
+		return msg, err
+	}
+
+	text, err := alert.Render(loc, kind, body, alert.SampleData(t.apiPath))
+	if err != nil {
+		// сохраненный шаблон проверяется при сохранении, сюда попадаем только если изменился набор переменных
+		msg.Text = loc.T("template.invalid", html.EscapeString(loc.Error(err)))
+		return msg, nil
+	}
+
+	msg.Text = text
+
+	return msg, nil
+}
+
+func (t *TemplatePreview) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return nil
+}
+
+func (t *TemplatePreview) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return true, nil
+}
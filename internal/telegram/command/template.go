@@ -0,0 +1,227 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/alert"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+	"slices"
+	"strings"
+)
+
+const (
+	answerTemplateKind = "kind"
+	answerTemplateBody = "body"
+)
+
+// templateReset ответ на шаг ввода шаблона, который возвращает шаблон по умолчанию
+const templateReset = "0"
+
+type (
+	// AlertTemplates этот интерфейс реализует возможность получать и изменять шаблоны уведомлений пользователя
+	AlertTemplates interface {
+		AlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) (string, error)
+		SaveAlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind, body string) error
+		DeleteAlertTemplate(ctx context.Context, userId int64, channel string, kind model.AlertKind) error
+	}
+
+	// Template структура для обработки команды изменения шаблонов уведомлений
+	Template struct {
+		templateRepo AlertTemplates
+		dialog       *Dialog
+		apiPath      string
+	}
+)
+
+func NewTemplateCommand(dialog DialogChain, templateRepo AlertTemplates, apiPath string) *Template {
+	t := &Template{
+		templateRepo: templateRepo,
+		apiPath:      apiPath,
+	}
+
+	t.dialog = NewDialog(TemplateCommand, dialog, anyoneFromContext, t.save,
+		DialogStep{
+			Name:     answerTemplateKind,
+			Prompt:   dialogPrompt("template.ask_kind"),
+			Options:  templateKindOptions,
+			Validate: validateTemplateKind,
+		},
+		DialogStep{
+			Name:     answerTemplateBody,
+			Prompt:   t.bodyPrompt,
+			Validate: validateTemplateBody,
+		},
+	)
+
+	return t
+}
+
+func (t *Template) CommandName() string {
+	return TemplateCommand
+}
+
+func (t *Template) Description(loc i18n.Locale) string {
+	return loc.T("template.description")
+}
+
+func (t *Template) HelpText(loc i18n.Locale) string {
+	return loc.T("template.help")
+}
+
+func (t *Template) Flags() []string {
+	return t.dialog.Flags()
+}
+
+func (t *Template) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return t.dialog.IsSupport(ctx, message)
+}
+
+func (t *Template) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return t.dialog.IsComplete(ctx, message)
+}
+
+func (t *Template) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", t.CommandName()))
+	defer span.End()
+
+	msg, err := t.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+func (t *Template) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", t.CommandName()))
+	defer span.End()
+
+	reply, err := t.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return reply, err
+}
+
+func (t *Template) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return t.dialog.Clear(ctx, message.Chat.ID)
+}
+
+// bodyPrompt текущий шаблон выбранного уведомления и доступные в нем переменные
+func (t *Template) bodyPrompt(ctx context.Context, s *DialogState) (string, error) {
+	loc := i18n.FromContext(ctx)
+	kind := model.AlertKind(s.Answers[answerTemplateKind])
+
+	body, err := t.templateRepo.AlertTemplate(ctx, s.ChatId, model.ChannelTelegram, kind)
+	if err != nil {
+		return "", i18n.WrapError(err, "template.error")
+	}
+
+	title := loc.T("template.current", alert.KindName(loc, kind))
+	if body == "" {
+		body = alert.DefaultTemplate(loc, kind)
+		title = loc.T("template.current_default", alert.KindName(loc, kind))
+	}
+
+	str := strings.Builder{}
+	str.WriteString(fmt.Sprintf("%s:\n<pre>%s</pre>\n\n", title, html.EscapeString(body)))
+	str.WriteString(loc.T("template.variables_title"))
+	str.WriteString(alert.VariablesHelp(loc))
+	str.WriteString(loc.T("template.ask_body"))
+
+	return str.String(), nil
+}
+
+// save сохраняет шаблон или возвращает шаблон по умолчанию и показывает, как будет выглядеть уведомление
+func (t *Template) save(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	kind := model.AlertKind(s.Answers[answerTemplateKind])
+	body := s.Answers[answerTemplateBody]
+
+	var err error
+	if body == templateReset {
+		err = t.templateRepo.DeleteAlertTemplate(ctx, s.ChatId, model.ChannelTelegram, kind)
+		body = ""
+	} else {
+		err = t.templateRepo.SaveAlertTemplate(ctx, s.ChatId, model.ChannelTelegram, kind, body)
+	}
+
+	if err != nil {
+		msg.Text = loc.T("template.error_save")
+		return msg, err
+	}
+
+	preview, err := alert.Render(loc, kind, body, alert.SampleData(t.apiPath))
+	if err != nil {
+		msg.Text = loc.T("template.saved")
+		return msg, nil
+	}
+
+	msg.Text = loc.T("template.saved_preview", preview)
+
+	return msg, nil
+}
+
+// templateKindOptions виды уведомлений, шаблон которых можно изменить
+func templateKindOptions(ctx context.Context, _ *DialogState) ([]DialogOption, error) {
+	loc := i18n.FromContext(ctx)
+
+	options := make([]DialogOption, 0, len(alert.Kinds))
+	for _, kind := range alert.Kinds {
+		options = append(options, DialogOption{Id: string(kind), Label: alert.KindName(loc, kind), Value: string(kind)})
+	}
+
+	return options, nil
+}
+
+func validateTemplateKind(ctx context.Context, _ *DialogState, value string) error {
+	if !slices.Contains(alert.Kinds, model.AlertKind(value)) {
+		return i18n.NewError("template.error_kind", value)
+	}
+
+	return nil
+}
+
+// validateTemplateBody шаблон проверяется до сохранения, при ошибке его можно исправить и отправить еще раз
+func validateTemplateBody(ctx context.Context, s *DialogState, value string) error {
+	if value == templateReset {
+		return nil
+	}
+
+	if err := alert.Validate(model.AlertKind(s.Answers[answerTemplateKind]), value); err != nil {
+		return i18n.NewError("template.invalid_retry", i18n.FromContext(ctx).Error(err))
+	}
+
+	return nil
+}
+
+// templateKindKeyboard кнопки выбора вида уведомления для команды
+func templateKindKeyboard(loc i18n.Locale, command string) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(alert.Kinds))
+	for _, kind := range alert.Kinds {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(alert.KindName(loc, kind), callbackData(command, string(kind))))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+func parseTemplateKind(data string) (model.AlertKind, error) {
+	_, args := parseCallbackData(data)
+	if len(args) != 1 {
+		return "", fmt.Errorf("не верные данные кнопки: %s", data)
+	}
+
+	kind := model.AlertKind(args[0])
+	if !slices.Contains(alert.Kinds, kind) {
+		return "", fmt.Errorf("неизвестный вид уведомления: %s", args[0])
+	}
+
+	return kind, nil
+}
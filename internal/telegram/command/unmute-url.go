@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"html"
+)
+
+type (
+	// UrlUnmuter этот интерфейс реализует возможность включить уведомления по ссылке
+	UrlUnmuter interface {
+		UnmuteUrl(workspaceId int64, url string) error
+		MutedUrlList(workspaceId int64) (model.PingList, error)
+	}
+
+	// UnmuteUrl структура для обработки команды включения уведомлений по ссылке
+	UnmuteUrl struct {
+		urlRepo UrlUnmuter
+		dialog  *Dialog
+	}
+)
+
+func NewUnmuteUrlCommand(dialog DialogChain, urlRepo UrlUnmuter) *UnmuteUrl {
+	u := &UnmuteUrl{
+		urlRepo: urlRepo,
+	}
+
+	step := urlDialogStep("unmute_url.ask_url", u.list)
+	step.Empty = "unmute_url.empty"
+	u.dialog = NewDialog(UnMuteUrlCommand, dialog, editorFromContext, u.unmute, step)
+
+	return u
+}
+
+func (u *UnmuteUrl) CommandName() string {
+	return UnMuteUrlCommand
+}
+
+func (u *UnmuteUrl) Description(loc i18n.Locale) string {
+	return loc.T("unmute_url.description")
+}
+
+func (u *UnmuteUrl) HelpText(loc i18n.Locale) string {
+	return loc.T("unmute_url.help")
+}
+
+func (u *UnmuteUrl) Flags() []string {
+	return u.dialog.Flags()
+}
+
+func (u *UnmuteUrl) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return u.dialog.IsSupport(ctx, message)
+}
+
+func (u *UnmuteUrl) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return u.dialog.IsComplete(ctx, message)
+}
+
+func (u *UnmuteUrl) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", u.CommandName()))
+	defer span.End()
+
+	msg, err := u.dialog.Run(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// RunCallback включает уведомления по ссылке выбранной кнопкой
+func (u *UnmuteUrl) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run callback: %s", u.CommandName()))
+	defer span.End()
+
+	msg, err := u.dialog.RunCallback(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return msg, err
+}
+
+// list выбрать можно только ссылки с отключенными уведомлениями
+func (u *UnmuteUrl) list(s *DialogState) (model.PingList, error) {
+	return u.urlRepo.MutedUrlList(s.Member.WorkspaceId)
+}
+
+func (u *UnmuteUrl) unmute(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(s.ChatId, "")
+	loc := i18n.FromContext(ctx)
+
+	if err := u.urlRepo.UnmuteUrl(s.Member.WorkspaceId, s.Answers[answerUrl]); err != nil {
+		msg.Text = loc.T("unmute_all.error")
+		return msg, err
+	}
+
+	msg.Text = loc.T("unmute_url.success", html.EscapeString(s.Answers[answerUrl]))
+	msg.ParseMode = tgbotapi.ModeHTML
+
+	return msg, nil
+}
+
+func (u *UnmuteUrl) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return u.dialog.Clear(ctx, message.Chat.ID)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
 )
 
 type (
@@ -27,8 +28,12 @@ func (u *UnmuteAll) CommandName() string {
 	return UnMuteAllCommand
 }
 
-func (u *UnmuteAll) HelpText() string {
-	return "help text"
+func (u *UnmuteAll) Description(loc i18n.Locale) string {
+	return loc.T("unmute_all.description")
+}
+
+func (u *UnmuteAll) HelpText(loc i18n.Locale) string {
+	return loc.T("unmute_all.help")
 }
 
 func (u *UnmuteAll) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
@@ -43,15 +48,16 @@ func (u *UnmuteAll) IsSupport(ctx context.Context, message *tgbotapi.Message) (b
 func (u *UnmuteAll) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
 	userId := message.Chat.ID
 	msg := tgbotapi.NewMessage(userId, "")
+	loc := i18n.FromContext(ctx)
 
 	err := u.userUnmute.Unmute(ctx, userId)
 
 	if err != nil {
-		msg.Text = "Произошла ошибка при включении уведомлений, повторите позже"
+		msg.Text = loc.T("unmute_all.error")
 		return msg, err
 	}
 
-	msg.Text = "Уведомления включены"
+	msg.Text = loc.T("unmute_all.success")
 
 	return msg, nil
 }
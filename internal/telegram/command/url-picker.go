@@ -0,0 +1,160 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"strconv"
+	"unicode/utf8"
+)
+
+// кнопки выбора ссылки имеют данные вида "<команда>:<действие>:<id ссылки или номер страницы>"
+const (
+	urlPickerActionPick    = "pick"    // выбрана ссылка
+	urlPickerActionPage    = "page"    // переход на страницу списка
+	urlPickerActionConfirm = "confirm" // подтверждено действие над ссылкой
+	urlPickerActionCancel  = "cancel"  // действие над ссылкой отменено
+)
+
+const (
+	urlPickerPageSize  = 8
+	urlPickerLabelSize = 48 // длинные ссылки обрезаются, чтобы кнопка помещалась на экране
+)
+
+// urlPickerKeyboard кнопки выбора ссылки с постраничной навигацией, page начинается с 0
+func urlPickerKeyboard(loc i18n.Locale, command string, list model.PingList, page int) tgbotapi.InlineKeyboardMarkup {
+	pages := (len(list) + urlPickerPageSize - 1) / urlPickerPageSize
+	page = max(0, min(page, pages-1))
+
+	from := page * urlPickerPageSize
+	to := min(from+urlPickerPageSize, len(list))
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, urlPickerPageSize+1)
+	for _, ping := range list[from:to] {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			urlPickerLabel(ping),
+			callbackData(command, urlPickerActionPick, strconv.FormatInt(ping.Id, 10)),
+		)))
+	}
+
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.back"), callbackData(command, urlPickerActionPage, strconv.Itoa(page-1))))
+		}
+
+		if page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("url_picker.next", page+1, pages),
+				callbackData(command, urlPickerActionPage, strconv.Itoa(page+1)),
+			))
+		}
+
+		rows = append(rows, nav)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// urlPickerPage заменяет кнопки выбора ссылки на указанную страницу в том же сообщении
+func urlPickerPage(loc i18n.Locale, query *tgbotapi.CallbackQuery, command string, list model.PingList, page int64) tgbotapi.Chattable {
+	return tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, urlPickerKeyboard(loc, command, list, int(page)))
+}
+
+// confirmKeyboard кнопки подтверждения действия над ссылкой, используются для необратимых действий
+func confirmKeyboard(loc i18n.Locale, command string, pingId int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.confirm"), callbackData(command, urlPickerActionConfirm, strconv.FormatInt(pingId, 10))),
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("url_picker.cancel"), callbackData(command, urlPickerActionCancel, strconv.FormatInt(pingId, 10))),
+	))
+}
+
+// parseUrlPick разбирает данные кнопки выбора ссылки на действие и id ссылки (номер страницы для перехода по страницам)
+func parseUrlPick(data string) (string, int64, error) {
+	_, args := parseCallbackData(data)
+	if len(args) != 2 {
+		return "", 0, fmt.Errorf("не верные данные кнопки: %s", data)
+	}
+
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("не верные данные кнопки: %s", data)
+	}
+
+	return args[0], id, nil
+}
+
+// pingById ищет ссылку в списке по идентификатору
+func pingById(list model.PingList, id int64) (model.Ping, bool) {
+	for _, ping := range list {
+		if ping.Id == id {
+			return ping, true
+		}
+	}
+
+	return model.Ping{}, false
+}
+
+// pingByUrl ищет ссылку в списке по адресу, используется когда ссылку ввели текстом
+func pingByUrl(list model.PingList, url string) (model.Ping, bool) {
+	for _, ping := range list {
+		if ping.Url == url {
+			return ping, true
+		}
+	}
+
+	return model.Ping{}, false
+}
+
+// urlDialogStep шаг диалога с выбором ссылки кнопкой или вводом адреса, list возвращает ссылки доступные для выбора
+func urlDialogStep(prompt string, list func(s *DialogState) (model.PingList, error)) DialogStep {
+	return DialogStep{
+		Name:   answerUrl,
+		Prompt: dialogPrompt(prompt),
+		Empty:  "common.empty_list",
+		Options: func(ctx context.Context, s *DialogState) ([]DialogOption, error) {
+			pings, err := list(s)
+			if err != nil {
+				return nil, err
+			}
+
+			options := make([]DialogOption, 0, len(pings))
+			for _, ping := range pings {
+				options = append(options, DialogOption{Id: strconv.FormatInt(ping.Id, 10), Label: urlPickerLabel(ping), Value: ping.Url})
+			}
+
+			return options, nil
+		},
+		Validate: func(ctx context.Context, s *DialogState, value string) error {
+			pings, err := list(s)
+			if err != nil {
+				return i18n.WrapError(err, "common.error_check")
+			}
+
+			if _, ok := pingByUrl(pings, value); !ok {
+				return i18n.NewError("common.url_not_exists")
+			}
+
+			return nil
+		},
+	}
+}
+
+func urlPickerLabel(ping model.Ping) string {
+	label := ping.Url
+	if utf8.RuneCountInString(label) > urlPickerLabelSize {
+		label = string([]rune(label)[:urlPickerLabelSize-1]) + "…"
+	}
+
+	if ping.Critical {
+		label = "🔥 " + label
+	}
+
+	if ping.Mute {
+		label = "🔕 " + label
+	}
+
+	return label
+}
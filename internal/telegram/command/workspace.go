@@ -0,0 +1,351 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"github.com/ivankoTut/ping-url/internal/model"
+	"github.com/ivankoTut/ping-url/internal/storage"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	workspaceActionSwitch = "switch"
+	workspaceActionNew    = "new"
+	workspaceActionInvite = "invite"
+	workspaceActionRemove = "remove"
+	workspaceActionUnlink = "unlink"
+)
+
+const (
+	workspaceInviteTtl   = time.Hour * 24 // время жизни ссылки-приглашения
+	workspaceNameMaxSize = 64
+)
+
+// roleNames ключи названий ролей для вывода пользователю
+var roleNames = map[model.Role]string{
+	model.RoleOwner:  "role.owner",
+	model.RoleEditor: "role.editor",
+	model.RoleViewer: "role.viewer",
+}
+
+type (
+	// WorkspaceManager этот интерфейс реализует возможность управлять пространствами пользователя и их участниками
+	WorkspaceManager interface {
+		WorkspaceList(ctx context.Context, userId int64) ([]model.Workspace, error)
+		CreateWorkspace(ctx context.Context, userId int64, name string) (int64, error)
+		SwitchWorkspace(ctx context.Context, userId, workspaceId int64) error
+		Members(ctx context.Context, workspaceId int64) ([]model.Member, error)
+		RemoveMember(ctx context.Context, workspaceId, userId int64) error
+		CreateInvite(ctx context.Context, workspaceId, createdBy int64, role model.Role, ttl time.Duration) (model.Invite, error)
+		Chats(ctx context.Context, workspaceId int64) ([]model.WorkspaceChat, error)
+		DisconnectChat(ctx context.Context, workspaceId, chatId int64) error
+	}
+
+	// BotNameProvider этот интерфейс реализует возможность получить имя бота для ссылок-приглашений
+	BotNameProvider interface {
+		UserName() string
+	}
+
+	// Workspace структура для обработки команды управления рабочими пространствами
+	Workspace struct {
+		workspaceRepo WorkspaceManager
+		bot           BotNameProvider
+		dialog        *Dialog
+	}
+)
+
+func NewWorkspaceCommand(dialog DialogChain, workspaceRepo WorkspaceManager, bot BotNameProvider) *Workspace {
+	w := &Workspace{
+		workspaceRepo: workspaceRepo,
+		bot:           bot,
+	}
+
+	// единственный шаг диалога - ввод названия нового пространства, диалог начинается кнопкой
+	w.dialog = NewDialog(WorkspaceCommand, dialog, memberFromContext, w.create,
+		DialogStep{
+			Name:   "name",
+			Prompt: dialogPrompt("workspace.ask_name"),
+			Validate: func(ctx context.Context, s *DialogState, value string) error {
+				if value == "" || utf8.RuneCountInString(value) > workspaceNameMaxSize {
+					return i18n.NewError("workspace.invalid_name", workspaceNameMaxSize)
+				}
+
+				return nil
+			},
+		},
+	)
+
+	return w
+}
+
+func (w *Workspace) CommandName() string {
+	return WorkspaceCommand
+}
+
+func (w *Workspace) Description(loc i18n.Locale) string {
+	return loc.T("workspace.description")
+}
+
+func (w *Workspace) HelpText(loc i18n.Locale) string {
+	return loc.T("workspace.help")
+}
+
+func (w *Workspace) IsSupport(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return w.dialog.IsSupport(ctx, message)
+}
+
+func (w *Workspace) IsComplete(ctx context.Context, message *tgbotapi.Message) (bool, error) {
+	return w.dialog.IsComplete(ctx, message)
+}
+
+func (w *Workspace) Run(ctx context.Context, message *tgbotapi.Message) (tgbotapi.MessageConfig, error) {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("Run command: %s", w.CommandName()))
+	defer span.End()
+
+	if !message.IsCommand() {
+		msg, err := w.dialog.Run(ctx, message)
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return msg, err
+	}
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		return tgbotapi.NewMessage(message.Chat.ID, memberErrorText(ctx, err)), nil
+	}
+
+	if err := w.ClearData(ctx, message); err != nil {
+		span.RecordError(err)
+		return tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("common.error")), err
+	}
+
+	return w.workspaceMessage(ctx, message.Chat.ID, member)
+}
+
+func (w *Workspace) RunCallback(ctx context.Context, query *tgbotapi.CallbackQuery) (tgbotapi.Chattable, error) {
+	chatId := query.Message.Chat.ID
+	msg := tgbotapi.NewMessage(chatId, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+
+	if w.dialog.IsCallback(query.Data) {
+		return w.dialog.RunCallback(ctx, query)
+	}
+
+	member, err := memberFromContext(ctx)
+	if err != nil {
+		msg.Text = memberErrorText(ctx, err)
+		return msg, nil
+	}
+
+	_, args := parseCallbackData(query.Data)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("не верные данные кнопки: %s", query.Data)
+	}
+
+	if args[0] == workspaceActionNew {
+		return w.dialog.Start(ctx, chatId, commandArguments{})
+	}
+
+	if len(args) != 2 {
+		return nil, fmt.Errorf("не верные данные кнопки: %s", query.Data)
+	}
+
+	// приглашать, исключать участников и отключать чаты может только владелец
+	if args[0] != workspaceActionSwitch && member.Role != model.RoleOwner {
+		msg.Text = loc.T("workspace.owner_only")
+		return msg, nil
+	}
+
+	switch args[0] {
+	case workspaceActionSwitch:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := w.workspaceRepo.SwitchWorkspace(ctx, member.UserId, id); err != nil {
+			msg.Text = loc.T("workspace.error_switch")
+			return msg, err
+		}
+
+		member.WorkspaceId = id
+		member.Role, err = w.role(ctx, member.UserId, id)
+		if err != nil {
+			msg.Text = loc.T("common.error")
+			return msg, err
+		}
+	case workspaceActionInvite:
+		role := model.Role(args[1])
+		if !role.IsValid() || role == model.RoleOwner {
+			return nil, fmt.Errorf("неизвестная роль: %s", args[1])
+		}
+
+		invite, err := w.workspaceRepo.CreateInvite(ctx, member.WorkspaceId, member.UserId, role, workspaceInviteTtl)
+		if err != nil {
+			msg.Text = loc.T("workspace.error_invite")
+			return msg, err
+		}
+
+		msg.Text = loc.T("workspace.invite",
+			loc.T(roleNames[role]), invite.ExpiresAt.UTC().Format("02.01.2006 15:04 UTC"), w.bot.UserName(), invite.Token,
+		)
+
+		return msg, nil
+	case workspaceActionRemove:
+		userId, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		err = w.workspaceRepo.RemoveMember(ctx, member.WorkspaceId, userId)
+		if errors.Is(err, storage.ErrNotMember) {
+			msg.Text = loc.T("workspace.member_not_found")
+			return msg, nil
+		}
+
+		if err != nil {
+			msg.Text = loc.T("workspace.error_remove")
+			return msg, err
+		}
+	case workspaceActionUnlink:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := w.workspaceRepo.DisconnectChat(ctx, member.WorkspaceId, id); err != nil {
+			msg.Text = loc.T("workspace.error_unlink")
+			return msg, err
+		}
+	default:
+		return nil, fmt.Errorf("неизвестное действие: %s", args[0])
+	}
+
+	return w.workspaceMessage(ctx, chatId, member)
+}
+
+func (w *Workspace) ClearData(ctx context.Context, message *tgbotapi.Message) error {
+	return w.dialog.Clear(ctx, message.Chat.ID)
+}
+
+// create создает пространство с введенным названием, пользователь сразу переключается на него
+func (w *Workspace) create(ctx context.Context, s *DialogState) (tgbotapi.MessageConfig, error) {
+	id, err := w.workspaceRepo.CreateWorkspace(ctx, s.Member.UserId, s.Answers["name"])
+	if err != nil {
+		return tgbotapi.NewMessage(s.ChatId, i18n.FromContext(ctx).T("workspace.error_create")), err
+	}
+
+	member := model.Member{WorkspaceId: id, UserId: s.Member.UserId, Login: s.Member.Login, Role: model.RoleOwner}
+
+	return w.workspaceMessage(ctx, s.ChatId, member)
+}
+
+// role роль пользователя в пространстве
+func (w *Workspace) role(ctx context.Context, userId, workspaceId int64) (model.Role, error) {
+	list, err := w.workspaceRepo.WorkspaceList(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, workspace := range list {
+		if workspace.Id == workspaceId {
+			return workspace.Role, nil
+		}
+	}
+
+	return "", storage.ErrNotMember
+}
+
+// workspaceMessage текущее пространство с участниками и чатами, кнопки управления доступны только владельцу
+func (w *Workspace) workspaceMessage(ctx context.Context, chatId int64, member model.Member) (tgbotapi.MessageConfig, error) {
+	msg := tgbotapi.NewMessage(chatId, "")
+	msg.ParseMode = tgbotapi.ModeHTML
+	loc := i18n.FromContext(ctx)
+	errorMessage := loc.T("workspace.error")
+
+	list, err := w.workspaceRepo.WorkspaceList(ctx, member.UserId)
+	if err != nil {
+		msg.Text = errorMessage
+		return msg, err
+	}
+
+	members, err := w.workspaceRepo.Members(ctx, member.WorkspaceId)
+	if err != nil {
+		msg.Text = errorMessage
+		return msg, err
+	}
+
+	chats, err := w.workspaceRepo.Chats(ctx, member.WorkspaceId)
+	if err != nil {
+		msg.Text = errorMessage
+		return msg, err
+	}
+
+	isOwner := member.Role == model.RoleOwner
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	str := strings.Builder{}
+	for _, workspace := range list {
+		if workspace.Id == member.WorkspaceId {
+			str.WriteString(loc.T("workspace.current", html.EscapeString(workspace.Name), loc.T(roleNames[workspace.Role])))
+			continue
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			"↪️ "+workspace.Name,
+			callbackData(w.CommandName(), workspaceActionSwitch, strconv.FormatInt(workspace.Id, 10)),
+		)))
+	}
+
+	str.WriteString(loc.Plural("workspace.members", len(members)))
+	for _, m := range members {
+		str.WriteString(fmt.Sprintf("%s - %s\n", html.EscapeString(m.Login), loc.T(roleNames[m.Role])))
+		if isOwner && m.Role != model.RoleOwner {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("workspace.button_remove", m.Login),
+				callbackData(w.CommandName(), workspaceActionRemove, strconv.FormatInt(m.UserId, 10)),
+			)))
+		}
+	}
+
+	str.WriteString(loc.T("workspace.chats"))
+	if len(chats) == 0 {
+		str.WriteString(loc.T("workspace.no_chats", ConnectChatCommand))
+	}
+
+	for _, chat := range chats {
+		str.WriteString(html.EscapeString(chat.Title) + "\n")
+		if isOwner {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				loc.T("workspace.button_unlink", chat.Title),
+				callbackData(w.CommandName(), workspaceActionUnlink, strconv.FormatInt(chat.ChatId, 10)),
+			)))
+		}
+	}
+
+	if isOwner {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("workspace.button_invite_editor"), callbackData(w.CommandName(), workspaceActionInvite, string(model.RoleEditor))),
+			tgbotapi.NewInlineKeyboardButtonData(loc.T("workspace.button_invite_viewer"), callbackData(w.CommandName(), workspaceActionInvite, string(model.RoleViewer))),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(loc.T("workspace.button_new"), callbackData(w.CommandName(), workspaceActionNew)),
+	))
+
+	msg.Text = str.String()
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return msg, nil
+}
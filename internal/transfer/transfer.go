@@ -0,0 +1,303 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	Csv  Format = "csv"
+	Yaml Format = "yaml"
+	Json Format = "json"
+
+	// MaxRows сколько ссылок можно импортировать из одного файла
+	MaxRows = 1000
+	// MaxFileSize ограничение размера файла импорта в байтах
+	MaxFileSize = 1 << 20
+)
+
+// Formats поддерживаемые форматы в порядке вывода пользователю
+var Formats = []Format{Csv, Yaml, Json}
+
+// columns колонки csv файла, без заголовка колонки идут в этом порядке
+var columns = []string{"url", "connection_time", "ping_time", "critical", "tags"}
+
+var (
+	ErrUnknownFormat = i18n.NewError("transfer.error_format")
+	ErrEmpty         = i18n.NewError("transfer.error_empty")
+)
+
+type (
+	Format string
+
+	// Monitor настройки ссылки в файле импорта и экспорта
+	Monitor struct {
+		Url            string   `json:"url" yaml:"url"`
+		ConnectionTime string   `json:"connection_time" yaml:"connection_time"`
+		PingTime       string   `json:"ping_time" yaml:"ping_time"`
+		Critical       *bool    `json:"critical,omitempty" yaml:"critical,omitempty"` // nil - не указано в файле
+		Tags           []string `json:"tags,omitempty" yaml:"tags,omitempty"`         // nil - не указано в файле, в csv теги через пробел
+	}
+
+	// Row запись файла импорта, Line - номер строки для csv и yaml или порядковый номер записи для json,
+	// Err - ошибка разбора записи, остальные записи файла при этом импортируются
+	Row struct {
+		Line    int
+		Monitor Monitor
+		Err     error
+	}
+)
+
+// ParseFormat формат по названию: csv, yaml, yml, json
+func ParseFormat(name string) (Format, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "yml" {
+		name = string(Yaml)
+	}
+
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, true
+		}
+	}
+
+	return "", false
+}
+
+// FormatByFileName формат по расширению файла
+func FormatByFileName(fileName string) (Format, bool) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(fileName), "."))
+}
+
+// ContentType тип содержимого файла для ответа апи
+func (f Format) ContentType() string {
+	switch f {
+	case Csv:
+		return "text/csv; charset=utf-8"
+	case Yaml:
+		return "application/yaml; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// FileName имя файла экспорта
+func (f Format) FileName() string {
+	return "monitors." + string(f)
+}
+
+// Decode разбирает файл импорта, ошибка возвращается только если файл не удалось разобрать целиком,
+// ошибки отдельных записей сохраняются в Row.Err
+func Decode(f Format, data []byte) ([]Row, error) {
+	const op = "transfer.Decode"
+
+	var rows []Row
+	var err error
+	switch f {
+	case Csv:
+		rows, err = decodeCsv(data)
+	case Yaml:
+		rows, err = decodeYaml(data)
+	case Json:
+		rows, err = decodeJson(data)
+	default:
+		err = ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrEmpty)
+	}
+
+	if len(rows) > MaxRows {
+		return nil, fmt.Errorf("%s: %w", op, i18n.NewError("transfer.error_too_many", MaxRows))
+	}
+
+	return rows, nil
+}
+
+// Encode сохраняет ссылки в файл выбранного формата
+func Encode(f Format, list []Monitor) ([]byte, error) {
+	const op = "transfer.Encode"
+
+	var buf bytes.Buffer
+	var err error
+	switch f {
+	case Csv:
+		err = encodeCsv(&buf, list)
+	case Yaml:
+		err = yaml.NewEncoder(&buf).Encode(list)
+	case Json:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(list)
+	default:
+		err = ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeCsv первая строка считается заголовком, если в ней есть колонка url, иначе колонки идут по порядку columns
+func decodeCsv(data []byte) ([]Row, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	index := make(map[string]int, len(columns))
+	for i, name := range columns {
+		index[name] = i
+	}
+
+	var rows []Row
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, i18n.WrapError(err, "transfer.error_csv", err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if first && isCsvHeader(record) {
+			index = make(map[string]int, len(record))
+			for i, name := range record {
+				index[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+
+			if _, ok := index["url"]; !ok {
+				return nil, i18n.NewError("transfer.error_csv_header")
+			}
+
+			continue
+		}
+
+		rows = append(rows, csvRow(line, record, index))
+	}
+
+	return rows, nil
+}
+
+func isCsvHeader(record []string) bool {
+	for _, cell := range record {
+		if strings.EqualFold(strings.TrimSpace(cell), "url") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func csvRow(line int, record []string, index map[string]int) Row {
+	value := func(name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	row := Row{Line: line, Monitor: Monitor{
+		Url:            value("url"),
+		ConnectionTime: value("connection_time"),
+		PingTime:       value("ping_time"),
+	}}
+
+	if critical := value("critical"); critical != "" {
+		is, err := strconv.ParseBool(critical)
+		if err != nil {
+			row.Err = i18n.NewError("transfer.error_critical", critical)
+		}
+
+		row.Monitor.Critical = &is
+	}
+
+	if tags := value("tags"); tags != "" {
+		row.Monitor.Tags = strings.Fields(tags)
+	}
+
+	return row
+}
+
+// decodeYaml файл должен содержать список ссылок, ошибки типов полей относятся к отдельной записи
+func decodeYaml(data []byte) ([]Row, error) {
+	var nodes []yaml.Node
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, i18n.WrapError(err, "transfer.error_yaml", err)
+	}
+
+	rows := make([]Row, 0, len(nodes))
+	for _, node := range nodes {
+		row := Row{Line: node.Line}
+		if err := node.Decode(&row.Monitor); err != nil {
+			row.Err = i18n.WrapError(err, "transfer.error_row", err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// decodeJson файл должен содержать массив ссылок, ошибки типов полей относятся к отдельной записи
+func decodeJson(data []byte) ([]Row, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, i18n.WrapError(err, "transfer.error_json", err)
+	}
+
+	rows := make([]Row, 0, len(items))
+	for i, item := range items {
+		row := Row{Line: i + 1}
+		if err := json.Unmarshal(item, &row.Monitor); err != nil {
+			row.Err = i18n.WrapError(err, "transfer.error_row", err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func encodeCsv(w io.Writer, list []Monitor) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	for _, m := range list {
+		var critical string
+		if m.Critical != nil {
+			critical = strconv.FormatBool(*m.Critical)
+		}
+
+		if err := writer.Write([]string{m.Url, m.ConnectionTime, m.PingTime, critical, strings.Join(m.Tags, " ")}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,225 @@
+package transfer
+
+import (
+	"errors"
+	"github.com/ivankoTut/ping-url/internal/i18n"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type wantRow struct {
+	Line    int
+	Monitor Monitor
+	Err     string // ключ i18n ошибки записи
+}
+
+func TestDecode(t *testing.T) {
+	critical, notCritical := true, false
+
+	tests := []struct {
+		name    string
+		format  Format
+		data    string
+		want    []wantRow
+		count   int    // проверяется вместо want, если want не указан
+		wantErr string // ключ i18n ошибки разбора файла
+	}{
+		{
+			name:   "csv без заголовка, колонки по порядку",
+			format: Csv,
+			data:   "https://example.com,10s,1m,true,env:prod team:payments\n",
+			want: []wantRow{
+				{Line: 1, Monitor: Monitor{Url: "https://example.com", ConnectionTime: "10s", PingTime: "1m", Critical: &critical, Tags: []string{"env:prod", "team:payments"}}},
+			},
+		},
+		{
+			name:   "csv с заголовком в другом порядке",
+			format: Csv,
+			data:   "Ping_Time, URL ,critical\n1m,https://example.com,false\n",
+			want: []wantRow{
+				{Line: 2, Monitor: Monitor{Url: "https://example.com", PingTime: "1m", Critical: &notCritical}},
+			},
+		},
+		{
+			name:   "csv комментарии и короткие строки",
+			format: Csv,
+			data:   "# ссылки\nurl,connection_time,ping_time\nhttps://a.com\nhttps://b.com,5s,30s\n",
+			want: []wantRow{
+				{Line: 3, Monitor: Monitor{Url: "https://a.com"}},
+				{Line: 4, Monitor: Monitor{Url: "https://b.com", ConnectionTime: "5s", PingTime: "30s"}},
+			},
+		},
+		{
+			name:   "csv значение в кавычках",
+			format: Csv,
+			data:   "url,tags\n\"https://example.com/?a=1,2\",\"env:prod  team:ops\"\n",
+			want: []wantRow{
+				{Line: 2, Monitor: Monitor{Url: "https://example.com/?a=1,2", Tags: []string{"env:prod", "team:ops"}}},
+			},
+		},
+		{
+			name:   "csv ошибка в одной записи",
+			format: Csv,
+			data:   "url,critical\nhttps://a.com,yes\nhttps://b.com,1\n",
+			want: []wantRow{
+				{Line: 2, Monitor: Monitor{Url: "https://a.com", Critical: &notCritical}, Err: "transfer.error_critical"},
+				{Line: 3, Monitor: Monitor{Url: "https://b.com", Critical: &critical}},
+			},
+		},
+		{
+			name:    "csv незакрытая кавычка",
+			format:  Csv,
+			data:    "url\n\"https://example.com\n",
+			wantErr: "transfer.error_csv",
+		},
+		{
+			name:    "csv только заголовок",
+			format:  Csv,
+			data:    "url,connection_time,ping_time\n",
+			wantErr: "transfer.error_empty",
+		},
+		{
+			name:    "csv пустой файл",
+			format:  Csv,
+			data:    "",
+			wantErr: "transfer.error_empty",
+		},
+		{
+			name:   "csv максимум записей",
+			format: Csv,
+			data:   strings.Repeat("https://example.com,10s,1m\n", MaxRows),
+			count:  MaxRows,
+		},
+		{
+			name:    "csv записей больше максимума",
+			format:  Csv,
+			data:    strings.Repeat("https://example.com,10s,1m\n", MaxRows+1),
+			wantErr: "transfer.error_too_many",
+		},
+		{
+			name:   "yaml список ссылок",
+			format: Yaml,
+			data:   "- url: https://a.com\n  connection_time: 10s\n  ping_time: 1m\n  tags: [env:prod]\n- url: https://b.com\n  critical: true\n",
+			want: []wantRow{
+				{Line: 1, Monitor: Monitor{Url: "https://a.com", ConnectionTime: "10s", PingTime: "1m", Tags: []string{"env:prod"}}},
+				{Line: 5, Monitor: Monitor{Url: "https://b.com", Critical: &critical}},
+			},
+		},
+		{
+			name:   "yaml ошибка типа в одной записи",
+			format: Yaml,
+			data:   "- url: https://a.com\n  tags:\n    key: value\n- url: https://b.com\n",
+			want: []wantRow{
+				{Line: 1, Monitor: Monitor{Url: "https://a.com"}, Err: "transfer.error_row"},
+				{Line: 4, Monitor: Monitor{Url: "https://b.com"}},
+			},
+		},
+		{
+			name:    "yaml не список",
+			format:  Yaml,
+			data:    "url: https://a.com\n",
+			wantErr: "transfer.error_yaml",
+		},
+		{
+			name:    "yaml неверный синтаксис",
+			format:  Yaml,
+			data:    "- url: [https://a.com\n",
+			wantErr: "transfer.error_yaml",
+		},
+		{
+			name:    "yaml записей больше максимума",
+			format:  Yaml,
+			data:    strings.Repeat("- url: https://example.com\n", MaxRows+1),
+			wantErr: "transfer.error_too_many",
+		},
+		{
+			name:   "json массив ссылок",
+			format: Json,
+			data:   `[{"url": "https://a.com", "ping_time": "1m", "critical": false}, {"url": "https://b.com", "tags": ["env:prod"]}]`,
+			want: []wantRow{
+				{Line: 1, Monitor: Monitor{Url: "https://a.com", PingTime: "1m", Critical: &notCritical}},
+				{Line: 2, Monitor: Monitor{Url: "https://b.com", Tags: []string{"env:prod"}}},
+			},
+		},
+		{
+			name:   "json ошибка типа в одной записи",
+			format: Json,
+			data:   `[{"url": "https://a.com", "critical": "yes"}, {"url": "https://b.com"}]`,
+			want: []wantRow{
+				{Line: 1, Monitor: Monitor{Url: "https://a.com", Critical: &notCritical}, Err: "transfer.error_row"},
+				{Line: 2, Monitor: Monitor{Url: "https://b.com"}},
+			},
+		},
+		{
+			name:    "json объект вместо массива",
+			format:  Json,
+			data:    `{"url": "https://a.com"}`,
+			wantErr: "transfer.error_json",
+		},
+		{
+			name:    "json неверный синтаксис",
+			format:  Json,
+			data:    `[{"url": "https://a.com"`,
+			wantErr: "transfer.error_json",
+		},
+		{
+			name:    "json пустой массив",
+			format:  Json,
+			data:    `[]`,
+			wantErr: "transfer.error_empty",
+		},
+		{
+			name:    "json записей больше максимума",
+			format:  Json,
+			data:    "[" + strings.Repeat(`{"url": "https://example.com"},`, MaxRows) + `{"url": "https://example.com"}]`,
+			wantErr: "transfer.error_too_many",
+		},
+		{
+			name:    "неизвестный формат",
+			format:  Format("xml"),
+			data:    "<monitors/>",
+			wantErr: "transfer.error_format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Decode(tt.format, []byte(tt.data))
+			if key := errorKey(err); key != tt.wantErr {
+				t.Fatalf("ошибка %q (%v), ожидалась %q", key, err, tt.wantErr)
+			}
+
+			if tt.want == nil {
+				if len(rows) != tt.count {
+					t.Fatalf("записей %d, ожидалось %d", len(rows), tt.count)
+				}
+
+				return
+			}
+
+			got := make([]wantRow, 0, len(rows))
+			for _, row := range rows {
+				got = append(got, wantRow{Line: row.Line, Monitor: row.Monitor, Err: errorKey(row.Err)})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("записи\n%+v\nожидались\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// errorKey ключ i18n ошибки, пустая строка если ошибки нет
+func errorKey(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var i18nErr *i18n.Error
+	if !errors.As(err, &i18nErr) {
+		return err.Error()
+	}
+
+	return i18nErr.Key
+}
@@ -0,0 +1,3 @@
+ALTER TABLE ping DROP COLUMN mute_until;
+ALTER TABLE ping DROP COLUMN mute;
+ALTER TABLE users DROP COLUMN mute_until;
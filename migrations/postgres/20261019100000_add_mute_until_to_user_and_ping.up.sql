@@ -0,0 +1,3 @@
+ALTER TABLE users ADD mute_until timestamptz default null;
+ALTER TABLE ping ADD mute boolean default false;
+ALTER TABLE ping ADD mute_until timestamptz default null;
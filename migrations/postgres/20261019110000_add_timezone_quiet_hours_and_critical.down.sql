@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quiet_hours;
+ALTER TABLE ping DROP COLUMN critical;
+ALTER TABLE users DROP COLUMN timezone;
@@ -0,0 +1,11 @@
+ALTER TABLE users ADD timezone varchar(64) default 'UTC';
+ALTER TABLE ping ADD critical boolean default false;
+
+CREATE TABLE IF NOT EXISTS quiet_hours(
+    user_id BIGINT NOT NULL,
+    weekday smallint NOT NULL,
+    start_minute smallint NOT NULL,
+    end_minute smallint NOT NULL,
+    PRIMARY KEY (user_id, weekday),
+    FOREIGN KEY (user_id)  REFERENCES users (id) ON DELETE CASCADE
+);
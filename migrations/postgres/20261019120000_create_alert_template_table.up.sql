@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS alert_template(
+    user_id BIGINT NOT NULL,
+    channel varchar(32) NOT NULL,
+    kind varchar(16) NOT NULL,
+    body TEXT NOT NULL,
+    PRIMARY KEY (user_id, channel, kind),
+    FOREIGN KEY (user_id)  REFERENCES users (id) ON DELETE CASCADE
+);
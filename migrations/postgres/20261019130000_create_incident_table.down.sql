@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS incident_event;
+DROP TABLE IF EXISTS incident;
+ALTER TABLE ping DROP CONSTRAINT IF EXISTS ping_pkey;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS incident(
+    id BIGSERIAL PRIMARY KEY,
+    ping_id INT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    first_error TEXT NOT NULL DEFAULT ''
+);
+
+-- у таблицы ping не было первичного ключа, без него на нее нельзя сослаться
+ALTER TABLE ping ADD PRIMARY KEY (id);
+ALTER TABLE incident ADD FOREIGN KEY (ping_id) REFERENCES ping (id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS incident_open_idx ON incident (ping_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS incident_ping_started_idx ON incident (ping_id, started_at);
+
+CREATE TABLE IF NOT EXISTS incident_event(
+    id BIGSERIAL PRIMARY KEY,
+    incident_id BIGINT NOT NULL,
+    kind varchar(16) NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (incident_id)  REFERENCES incident (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS incident_event_incident_idx ON incident_event (incident_id, created_at);
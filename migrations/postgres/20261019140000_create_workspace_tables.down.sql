@@ -0,0 +1,7 @@
+ALTER TABLE ping DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE users DROP COLUMN IF EXISTS api_workspace_id;
+ALTER TABLE users DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS workspace_invite;
+DROP TABLE IF EXISTS workspace_chat;
+DROP TABLE IF EXISTS workspace_member;
+DROP TABLE IF EXISTS workspace;
@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS workspace(
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_member(
+    workspace_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role varchar(16) NOT NULL,
+    PRIMARY KEY (workspace_id, user_id),
+    FOREIGN KEY (workspace_id)  REFERENCES workspace (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id)  REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS workspace_member_user_idx ON workspace_member (user_id);
+
+-- групповые чаты и каналы, в которые дублируются уведомления пространства
+CREATE TABLE IF NOT EXISTS workspace_chat(
+    workspace_id BIGINT NOT NULL,
+    chat_id BIGINT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (workspace_id, chat_id),
+    FOREIGN KEY (workspace_id)  REFERENCES workspace (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS workspace_invite(
+    token varchar(32) PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    role varchar(16) NOT NULL,
+    created_by BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (workspace_id)  REFERENCES workspace (id) ON DELETE CASCADE
+);
+
+-- у каждого существующего пользователя появляется личное пространство с id равным id пользователя,
+-- так старые строки url_status в clickhouse (userId) остаются привязаны к нужному пространству
+INSERT INTO workspace(id, name, created_by)
+SELECT id, coalesce(nullif(login, ''), id::text), id FROM users;
+
+SELECT setval('workspace_id_seq', greatest((SELECT max(id) FROM workspace), 1));
+
+INSERT INTO workspace_member(workspace_id, user_id, role)
+SELECT id, id, 'owner' FROM users;
+
+-- workspace_id текущее пространство пользователя в боте, api_workspace_id пространство к которому дает доступ api ключ
+ALTER TABLE users ADD COLUMN IF NOT EXISTS workspace_id BIGINT REFERENCES workspace (id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS api_workspace_id BIGINT REFERENCES workspace (id) ON DELETE SET NULL;
+UPDATE users SET workspace_id = id, api_workspace_id = id;
+
+-- user_id у ссылки теперь означает кто ее добавил
+ALTER TABLE ping ADD COLUMN IF NOT EXISTS workspace_id BIGINT REFERENCES workspace (id) ON DELETE CASCADE;
+UPDATE ping SET workspace_id = user_id;
+ALTER TABLE ping ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS ping_workspace_idx ON ping (workspace_id);
@@ -0,0 +1 @@
+ALTER TABLE users ADD language varchar(8) NOT NULL default '';
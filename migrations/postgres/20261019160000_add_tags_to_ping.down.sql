@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ping_tags_idx;
+ALTER TABLE ping DROP COLUMN tags;
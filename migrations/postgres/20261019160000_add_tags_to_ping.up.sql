@@ -0,0 +1,2 @@
+ALTER TABLE ping ADD tags text[] NOT NULL default '{}';
+CREATE INDEX IF NOT EXISTS ping_tags_idx ON ping USING gin (tags);
@@ -0,0 +1,3 @@
+-- is_admin повторяет список администраторов из конфига, синхронизируется при запуске приложения
+ALTER TABLE users ADD is_admin boolean NOT NULL default false;
+ALTER TABLE users ADD blocked boolean NOT NULL default false;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS access_code;
+DROP TABLE IF EXISTS access_allow;
+DROP TABLE IF EXISTS access_setting;
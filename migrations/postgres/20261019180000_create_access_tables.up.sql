@@ -0,0 +1,19 @@
+-- режим доступа к боту, всегда одна строка: open, invite или allowlist
+CREATE TABLE IF NOT EXISTS access_setting(
+    id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    mode varchar(16) NOT NULL
+);
+-- пользователи телеграма с доступом в режиме allowlist, пользователь может быть еще не зарегистрирован
+CREATE TABLE IF NOT EXISTS access_allow(
+    user_id BIGINT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- коды приглашений для регистрации в режиме invite, /start <code>
+CREATE TABLE IF NOT EXISTS access_code(
+    code varchar(32) PRIMARY KEY,
+    max_uses INT NOT NULL,
+    uses INT NOT NULL DEFAULT 0,
+    created_by BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE ping DROP COLUMN repeat_alert;
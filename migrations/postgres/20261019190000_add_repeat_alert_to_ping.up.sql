@@ -0,0 +1 @@
+ALTER TABLE ping ADD repeat_alert varchar(20) NOT NULL default '';
@@ -0,0 +1,2 @@
+ALTER TABLE ping DROP COLUMN mute_chat_id;
+ALTER TABLE ping DROP COLUMN muted_by;
@@ -0,0 +1,3 @@
+-- кто отключил уведомления по ссылке и в каком чате, туда приходит сообщение об окончании отключения
+ALTER TABLE ping ADD muted_by bigint NULL REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE ping ADD mute_chat_id bigint NULL;